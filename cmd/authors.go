@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"git-visible/internal/config"
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// 命令行标志变量
+var (
+	authorsSince     string // 起始日期，空表示全部历史
+	authorsUntil     string // 结束日期
+	authorsBranch    string // 指定分支名
+	authorsAllBranch bool   // 是否遍历所有本地分支（去重）
+	authorsFormat    string // 输出格式：table/json
+	authorsApply     bool   // 是否逐条确认并保存建议的别名组
+	authorsYes       bool   // --apply 时不询问，接受全部建议
+	authorsTZ        string // 按天划分使用的时区，空表示使用配置或本地时区
+)
+
+// authorsAllHistory 是未指定 --since 时使用的起始日期，覆盖全部提交历史。
+const authorsAllHistory = "1970-01-01"
+
+// authorsCmd 实现 authors 子命令，列出所有作者身份并建议别名组。
+var authorsCmd = newAuthorsCmd()
+
+// newAuthorsCmd 构建 authors 命令，便于在测试中复用。
+func newAuthorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "authors",
+		Short: "List author identities and suggest alias groups",
+		Long: `List every distinct author name/email across all registered repositories
+with commit counts, first/last seen dates and the number of repositories
+(mailmap is applied, aliases are not).
+
+Likely duplicates are grouped into suggested alias groups: the same name with
+different emails, GitHub noreply addresses (12345+login@users.noreply.github.com)
+matching another identity's name or email, and emails that differ only in case.
+Suggestions already covered by the configured aliases are not shown.
+
+With --apply each suggestion is confirmed interactively and the accepted ones
+are saved to the config (use --yes to accept all).`,
+		Example: `  git-visible authors
+  git-visible authors --since 1y -f json
+  git-visible authors --apply
+  git-visible authors --apply --yes`,
+		Args: cobra.NoArgs,
+		RunE: runAuthors,
+	}
+
+	cmd.Flags().StringVar(&authorsSince, "since", "", "Start date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y; default: all history)")
+	cmd.Flags().StringVar(&authorsUntil, "until", "", "End date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
+	cmd.Flags().StringVarP(&authorsBranch, "branch", "b", "", "Branch to include (default: HEAD)")
+	cmd.Flags().BoolVar(&authorsAllBranch, "all-branches", false, "Include all local branches (deduplicated by commit hash)")
+	cmd.MarkFlagsMutuallyExclusive("branch", "all-branches")
+	cmd.Flags().StringVarP(&authorsFormat, "format", "f", "table", "Output format: table/json")
+	cmd.Flags().BoolVar(&authorsApply, "apply", false, "Confirm each suggested alias group and save the accepted ones to the config")
+	cmd.Flags().BoolVarP(&authorsYes, "yes", "y", false, "With --apply, accept all suggestions without asking")
+	cmd.MarkFlagsMutuallyExclusive("apply", "format")
+	addTimezoneFlag(cmd, &authorsTZ)
+	return cmd
+}
+
+// init 注册 authors 命令。
+func init() {
+	rootCmd.AddCommand(authorsCmd)
+}
+
+// runAuthors 是 authors 命令的核心逻辑：收集全部作者身份，给出别名建议，--apply 时写入配置。
+func runAuthors(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	format := strings.ToLower(strings.TrimSpace(authorsFormat))
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: table, json)", authorsFormat)
+	}
+	if authorsYes && !authorsApply {
+		return fmt.Errorf("--yes requires --apply")
+	}
+
+	since := strings.TrimSpace(authorsSince)
+	if since == "" {
+		since = authorsAllHistory
+	}
+	runCtx, err := prepareRun(nil, 0, since, authorsUntil, calendarFlags{timezone: authorsTZ})
+	if err != nil {
+		if errors.Is(err, errNoRepositoriesAdded) {
+			fmt.Fprintln(out, "no repositories added")
+			return nil
+		}
+		return err
+	}
+
+	// 列出所有作者：不按邮箱过滤，也不应用别名，别名只用于判断建议是否已配置
+	opts := runCtx.collectOptions(stats.BranchOption{
+		Branch:      strings.TrimSpace(authorsBranch),
+		AllBranches: authorsAllBranch,
+	}, false)
+	opts.Emails = nil
+	opts.NormalizeEmail = nil
+
+	authors, done, collectErr := stats.CollectAuthors(opts)
+	if collectErr != nil {
+		if len(done) == 0 {
+			return fmt.Errorf("all repositories failed to collect authors: %w", collectErr)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: some repositories failed, showing partial results:", collectErr)
+	}
+	plans := planAliasSuggestions(runCtx.Config.Aliases, stats.SuggestAliases(authors))
+
+	if format == "json" {
+		return writeAuthorsJSON(out, authors, plans)
+	}
+	writeAuthorsTable(out, authors)
+	writeAliasPlans(out, plans)
+	if !authorsApply {
+		if len(plans) > 0 {
+			fmt.Fprintln(out, "\nRun with --apply to review and save them.")
+		}
+		return nil
+	}
+	return applyAliasPlans(cmd.InOrStdin(), out, runCtx.Config, plans, authorsYes)
+}
+
+// aliasPlan 是一条别名建议相对已配置别名的处理方式。
+type aliasPlan struct {
+	stats.AliasSuggestion
+	Extends   string   `json:"extends,omitempty"`   // 并入的已有别名组，为空表示新建
+	NewEmails []string `json:"newEmails"`           // 尚未出现在任何别名组中的邮箱
+	Conflicts []string `json:"conflicts,omitempty"` // 邮箱分属多个已有别名组时的组名，此时不自动应用
+}
+
+// planAliasSuggestions 将建议与已配置的别名对照（大小写不敏感）：邮箱都已在别名组中的建议被丢弃，
+// 与一个已有组重叠的建议并入该组，与多个已有组重叠的建议标记为冲突。
+func planAliasSuggestions(aliases []config.Alias, suggestions []stats.AliasSuggestion) []aliasPlan {
+	owner := make(map[string]string)
+	for _, alias := range aliases {
+		for _, email := range alias.Emails {
+			owner[strings.ToLower(strings.TrimSpace(email))] = alias.Name
+		}
+	}
+
+	plans := make([]aliasPlan, 0, len(suggestions))
+	for _, s := range suggestions {
+		p := aliasPlan{AliasSuggestion: s}
+		var owners []string
+		for _, email := range s.Emails {
+			name, ok := owner[strings.ToLower(email)]
+			switch {
+			case !ok:
+				p.NewEmails = append(p.NewEmails, email)
+			case !containsFold(owners, name):
+				owners = append(owners, name)
+			}
+		}
+		if len(p.NewEmails) == 0 {
+			continue
+		}
+		switch len(owners) {
+		case 0:
+		case 1:
+			p.Extends = owners[0]
+		default:
+			p.Conflicts = owners
+		}
+		plans = append(plans, p)
+	}
+	return plans
+}
+
+// containsFold 报告 list 中是否有与 s 大小写不敏感相等的元素。
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe 返回该建议将对配置做的修改。
+func (p aliasPlan) describe() string {
+	switch {
+	case len(p.Conflicts) > 0:
+		return fmt.Sprintf("emails already belong to aliases %s, merge them manually", strings.Join(quoteAll(p.Conflicts), ", "))
+	case p.Extends != "":
+		return fmt.Sprintf("add %s to alias %q", strings.Join(p.NewEmails, ", "), p.Extends)
+	default:
+		return fmt.Sprintf("new alias %q with %s", p.Name, strings.Join(p.Emails, ", "))
+	}
+}
+
+// quoteAll 为每个字符串加上引号。
+func quoteAll(list []string) []string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return quoted
+}
+
+// applyAliasPlans 逐条确认建议（yes 为 true 时全部接受），将接受的建议写入配置并保存。
+// 冲突的建议只提示不应用；从 in 读到 EOF 时视为拒绝其余建议。
+func applyAliasPlans(in io.Reader, out io.Writer, cfg *config.Config, plans []aliasPlan, yes bool) error {
+	fmt.Fprintln(out)
+	reader := bufio.NewReader(in)
+	updated := *cfg
+	var changed []string
+	for i, p := range plans {
+		if len(p.Conflicts) > 0 {
+			fmt.Fprintf(out, "%d. skipped: %s\n", i+1, p.describe())
+			continue
+		}
+		if !yes {
+			fmt.Fprintf(out, "%d. %s? [y/N] ", i+1, p.describe())
+			answer, _ := reader.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if answer != "y" && answer != "yes" {
+				continue
+			}
+		}
+		var name string
+		updated.Aliases, name = applyAliasPlan(updated.Aliases, p)
+		if !containsFold(changed, name) {
+			changed = append(changed, name)
+		}
+	}
+
+	if len(changed) == 0 {
+		fmt.Fprintln(out, "no aliases changed")
+		return nil
+	}
+	if err := config.Save(updated); err != nil {
+		return err
+	}
+	for _, name := range changed {
+		alias := updated.Aliases[findAliasIndex(updated.Aliases, name)]
+		fmt.Fprintf(out, "alias %q saved: %s\n", alias.Name, strings.Join(alias.Emails, ", "))
+	}
+	return nil
+}
+
+// applyAliasPlan 将一条建议应用到别名列表，返回新列表与被修改的别名组名称。
+// 新建组与已有组重名时在名称后附加主邮箱，避免覆盖已有组。
+func applyAliasPlan(aliases []config.Alias, p aliasPlan) ([]config.Alias, string) {
+	aliases = append([]config.Alias(nil), aliases...)
+	if p.Extends != "" {
+		i := findAliasIndex(aliases, p.Extends)
+		aliases[i].Emails = append(append([]string(nil), aliases[i].Emails...), p.NewEmails...)
+		return aliases, aliases[i].Name
+	}
+
+	name := p.Name
+	if findAliasIndex(aliases, name) >= 0 {
+		name = fmt.Sprintf("%s (%s)", name, p.Emails[0])
+	}
+	return append(aliases, config.Alias{Name: name, Emails: p.Emails}), name
+}
+
+// writeAuthorsTable 以对齐的文本表格输出作者身份。
+func writeAuthorsTable(out io.Writer, authors []stats.AuthorIdentity) {
+	if len(authors) == 0 {
+		fmt.Fprintln(out, "no commits found")
+		return
+	}
+
+	nameWidth, emailWidth := len("Name"), len("Email")
+	for _, a := range authors {
+		nameWidth = max(nameWidth, len(a.Name))
+		emailWidth = max(emailWidth, len(a.Email))
+	}
+	fmt.Fprintf(out, "%-*s  %-*s  %7s  %5s  %-10s  %s\n", nameWidth, "Name", emailWidth, "Email", "Commits", "Repos", "First seen", "Last seen")
+	for _, a := range authors {
+		fmt.Fprintf(out, "%-*s  %-*s  %7d  %5d  %s  %s\n", nameWidth, a.Name, emailWidth, a.Email,
+			a.Commits, a.Repos, a.FirstSeen.Format("2006-01-02"), a.LastSeen.Format("2006-01-02"))
+	}
+}
+
+// writeAliasPlans 输出编号的别名建议及其对配置的修改。
+func writeAliasPlans(out io.Writer, plans []aliasPlan) {
+	if len(plans) == 0 {
+		fmt.Fprintln(out, "\nNo alias suggestions.")
+		return
+	}
+	fmt.Fprintln(out, "\nSuggested alias groups:")
+	for i, p := range plans {
+		fmt.Fprintf(out, "  %d. %s (%s): %s\n", i+1, p.Name, strings.Join(p.Reasons, ", "), strings.Join(p.Emails, ", "))
+		fmt.Fprintf(out, "     %s\n", p.describe())
+	}
+}
+
+// authorsJSONOutput 是 authors 命令 JSON 输出的顶层结构。
+type authorsJSONOutput struct {
+	Authors     []stats.AuthorIdentity `json:"authors"`
+	Suggestions []aliasPlan            `json:"suggestions"`
+}
+
+// writeAuthorsJSON 以 JSON 格式输出作者身份与别名建议。
+func writeAuthorsJSON(out io.Writer, authors []stats.AuthorIdentity, plans []aliasPlan) error {
+	if authors == nil {
+		authors = []stats.AuthorIdentity{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(authorsJSONOutput{Authors: authors, Suggestions: plans})
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+	"git-visible/internal/stats"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAuthorsRepos 创建两个仓库：Alice 以公司邮箱、个人邮箱与 GitHub noreply 地址提交，Bob 只有一个邮箱。
+func setupAuthorsRepos(t *testing.T) string {
+	t.Helper()
+
+	home := withTempHome(t)
+	base := time.Date(2024, 5, 6, 10, 0, 0, 0, time.Local)
+	repoA := filepath.Join(home, "code", "api")
+	createRepoWithCommitSpecs(t, repoA, []commitSpec{
+		{Name: "Alice", Email: "alice@company.com", When: base},
+		{Name: "Alice", Email: "alice@company.com", When: base.AddDate(0, 1, 0)},
+		{Name: "Bob", Email: "bob@example.com", When: base.AddDate(0, 0, 1)},
+	})
+	repoB := filepath.Join(home, "code", "web")
+	createRepoWithCommitSpecs(t, repoB, []commitSpec{
+		{Name: "Alice", Email: "alice@company.com", When: base.AddDate(1, 0, 0)},
+		{Name: "alice", Email: "alice@gmail.com", When: base.AddDate(0, 2, 0)},
+		{Name: "Alice W", Email: "42+alice@users.noreply.github.com", When: base.AddDate(0, 3, 0)},
+	})
+	writeReposFile(t, home, []string{repoA, repoB})
+	return home
+}
+
+func executeAuthorsCommand(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := newAuthorsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestAuthors_TableListsIdentitiesAndSuggestions(t *testing.T) {
+	setupAuthorsRepos(t)
+	setTestConfig(t, config.Config{Months: config.DefaultMonths})
+
+	out, err := executeAuthorsCommand(t, "")
+	require.NoError(t, err)
+
+	lines := strings.Split(out, "\n")
+	require.GreaterOrEqual(t, len(lines), 5)
+	assert.Regexp(t, `^Name\s+Email\s+Commits\s+Repos\s+First seen\s+Last seen$`, lines[0])
+	assert.Regexp(t, `^Alice\s+alice@company\.com\s+3\s+2\s+2024-05-06\s+2025-05-06$`, lines[1])
+	assert.Contains(t, out, "Suggested alias groups:")
+	assert.Contains(t, out, "1. Alice (same name, GitHub noreply): alice@company.com, alice@gmail.com, 42+alice@users.noreply.github.com")
+	assert.Contains(t, out, `new alias "Alice" with alice@company.com`)
+	assert.NotContains(t, out, "bob@example.com,")
+	assert.Contains(t, out, "Run with --apply")
+}
+
+func TestAuthors_JSONMarksExistingAlias(t *testing.T) {
+	setupAuthorsRepos(t)
+	setTestConfig(t, config.Config{
+		Months:  config.DefaultMonths,
+		Aliases: []config.Alias{{Name: "Alice", Emails: []string{"alice@company.com"}}},
+	})
+
+	out, err := executeAuthorsCommand(t, "", "-f", "json")
+	require.NoError(t, err)
+
+	var got authorsJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	require.Len(t, got.Authors, 4)
+	assert.Equal(t, "alice@company.com", got.Authors[0].Email)
+	assert.Equal(t, 2, got.Authors[0].Repos)
+	require.Len(t, got.Suggestions, 1)
+	assert.Equal(t, "Alice", got.Suggestions[0].Extends)
+	assert.Equal(t, []string{"alice@gmail.com", "42+alice@users.noreply.github.com"}, got.Suggestions[0].NewEmails)
+}
+
+func TestAuthors_SinceLimitsRange(t *testing.T) {
+	setupAuthorsRepos(t)
+	setTestConfig(t, config.Config{Months: config.DefaultMonths})
+
+	out, err := executeAuthorsCommand(t, "", "--since", "2025-01-01", "-f", "json")
+	require.NoError(t, err)
+
+	var got authorsJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	require.Len(t, got.Authors, 1)
+	assert.Equal(t, 1, got.Authors[0].Commits)
+	assert.Empty(t, got.Suggestions)
+}
+
+func TestAuthors_ApplyPromptsAndSaves(t *testing.T) {
+	setupAuthorsRepos(t)
+	setTestConfig(t, config.Config{
+		Months:  config.DefaultMonths,
+		Aliases: []config.Alias{{Name: "Alice", Emails: []string{"alice@company.com"}}},
+	})
+
+	out, err := executeAuthorsCommand(t, "n\n", "--apply")
+	require.NoError(t, err)
+	assert.Contains(t, out, `1. add alice@gmail.com, 42+alice@users.noreply.github.com to alias "Alice"? [y/N] `)
+	assert.Contains(t, out, "no aliases changed")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@company.com"}, cfg.Aliases[0].Emails)
+
+	out, err = executeAuthorsCommand(t, "y\n", "--apply")
+	require.NoError(t, err)
+	assert.Contains(t, out, `alias "Alice" saved: alice@company.com, alice@gmail.com, 42+alice@users.noreply.github.com`)
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.Aliases, 1)
+	assert.Equal(t, []string{"alice@company.com", "alice@gmail.com", "42+alice@users.noreply.github.com"}, cfg.Aliases[0].Emails)
+
+	// 已被别名覆盖的建议不再出现
+	out, err = executeAuthorsCommand(t, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "No alias suggestions.")
+}
+
+func TestAuthors_ApplyYesAcceptsAll(t *testing.T) {
+	setupAuthorsRepos(t)
+	setTestConfig(t, config.Config{Months: config.DefaultMonths})
+
+	out, err := executeAuthorsCommand(t, "", "--apply", "--yes")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "[y/N]")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.Aliases, 1)
+	assert.Equal(t, "Alice", cfg.Aliases[0].Name)
+	assert.Equal(t, "alice@company.com", cfg.Aliases[0].Emails[0])
+}
+
+func TestAuthors_FlagValidation(t *testing.T) {
+	withTempHome(t)
+
+	_, err := executeAuthorsCommand(t, "", "--yes")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--yes requires --apply")
+
+	_, err = executeAuthorsCommand(t, "", "--apply", "-f", "json")
+	require.Error(t, err)
+
+	_, err = executeAuthorsCommand(t, "", "-f", "csv")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported format")
+}
+
+func TestPlanAliasSuggestions_ConflictAndNameClash(t *testing.T) {
+	aliases := []config.Alias{
+		{Name: "Work", Emails: []string{"a@work.com"}},
+		{Name: "Home", Emails: []string{"a@home.com"}},
+		{Name: "Bob", Emails: []string{"bob@other.com"}},
+	}
+	suggestions := []stats.AliasSuggestion{
+		{Name: "A", Emails: []string{"a@work.com", "a@home.com", "a@new.com"}},
+		{Name: "Bob", Emails: []string{"bob@example.com", "bob@gmail.com"}},
+		{Name: "Done", Emails: []string{"a@work.com"}},
+	}
+
+	plans := planAliasSuggestions(aliases, suggestions)
+	require.Len(t, plans, 2)
+	assert.Equal(t, []string{"Work", "Home"}, plans[0].Conflicts)
+	assert.Contains(t, plans[0].describe(), "merge them manually")
+
+	updated, name := applyAliasPlan(aliases, plans[1])
+	assert.Equal(t, "Bob (bob@example.com)", name)
+	require.Len(t, updated, 4)
+	assert.Len(t, aliases, 3, "applyAliasPlan must not modify the input")
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"git-visible/internal/cache"
+	"git-visible/internal/config"
+	"git-visible/internal/repo"
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultCacheTTL 是 cache prune 默认的过期时间。
+const defaultCacheTTL = 30 * 24 * time.Hour
+
+var (
+	cachePruneTTL    time.Duration // 超过该时长的结果缓存视为过期，0 表示不按时间清理
+	cachePruneDryRun bool          // 仅列出将被清理的文件
+)
+
+// cacheCmd 实现 cache 子命令组，用于查看与清理 ~/.config/git-visible/cache。
+var cacheCmd = newCacheCmd()
+
+// newCacheCmd 构建 cache 命令组，便于在测试中复用。
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect, prune or clear the stats cache",
+		Long: `Inspect and maintain the on-disk cache (~/.config/git-visible/cache).
+
+The cache holds per-query result entries and one commit index per repository.
+Set "cache_max_mb" to cap its size; least recently used files are evicted first.`,
+		Example: `  git-visible cache stats
+  git-visible cache prune --ttl 168h
+  git-visible cache clear
+  git-visible cache clear ~/code/project`,
+		Args: cobra.NoArgs,
+	}
+	cmd.AddCommand(newCacheStatsCmd())
+	cmd.AddCommand(newCachePruneCmd())
+	cmd.AddCommand(newCacheClearCmd())
+	return cmd
+}
+
+// newCacheStatsCmd 构建 cache stats 子命令。
+func newCacheStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show cache size and per-repository breakdown",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheStats,
+	}
+}
+
+// newCachePruneCmd 构建 cache prune 子命令。
+func newCachePruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove stale, orphaned and expired cache files",
+		Long: `Remove cache files that can no longer be used:
+  - result entries whose branch fingerprint no longer matches the repository
+  - entries and indexes of repositories that are no longer registered
+  - result entries older than --ttl
+  - files that cannot be parsed`,
+		Args: cobra.NoArgs,
+		RunE: runCachePrune,
+	}
+	cmd.Flags().DurationVar(&cachePruneTTL, "ttl", defaultCacheTTL, "Remove result entries older than this (0 disables)")
+	cmd.Flags().BoolVar(&cachePruneDryRun, "dry-run", false, "Only list files that would be removed")
+	return cmd
+}
+
+// newCacheClearCmd 构建 cache clear 子命令。
+func newCacheClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear [repo]",
+		Short: "Remove all cache files, or those of one repository",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runCacheClear,
+	}
+}
+
+// cacheRepoUsage 是 cache stats 中单个仓库的占用汇总。
+type cacheRepoUsage struct {
+	repo    string
+	entries int
+	indexes int
+	bytes   int64
+}
+
+// runCacheStats 输出缓存总量与按仓库的明细。
+func runCacheStats(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	dir, err := cache.Dir()
+	if err != nil {
+		return err
+	}
+	files, err := cache.List()
+	if err != nil {
+		return err
+	}
+
+	var (
+		entries, indexes       int
+		entryBytes, indexBytes int64
+		usageByRepo            = make(map[string]*cacheRepoUsage)
+	)
+	for _, f := range files {
+		repoPath := f.RepoPath
+		if repoPath == "" {
+			repoPath = "(unreadable)"
+		}
+		u := usageByRepo[repoPath]
+		if u == nil {
+			u = &cacheRepoUsage{repo: repoPath}
+			usageByRepo[repoPath] = u
+		}
+		u.bytes += f.Size
+		if f.Index {
+			indexes++
+			indexBytes += f.Size
+			u.indexes++
+		} else {
+			entries++
+			entryBytes += f.Size
+			u.entries++
+		}
+	}
+
+	fmt.Fprintf(out, "Cache directory: %s\n", dir)
+	fmt.Fprintf(out, "Result entries:  %d (%s)\n", entries, formatBytes(entryBytes))
+	fmt.Fprintf(out, "Commit indexes:  %d (%s)\n", indexes, formatBytes(indexBytes))
+	total := fmt.Sprintf("Total:           %s", formatBytes(entryBytes+indexBytes))
+	if cfg, err := config.Load(); err == nil && cfg.CacheMaxMB > 0 {
+		total += fmt.Sprintf(" / limit %d MB", cfg.CacheMaxMB)
+	}
+	fmt.Fprintln(out, total)
+
+	if len(usageByRepo) == 0 {
+		return nil
+	}
+
+	usages := make([]*cacheRepoUsage, 0, len(usageByRepo))
+	for _, u := range usageByRepo {
+		usages = append(usages, u)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].bytes != usages[j].bytes {
+			return usages[i].bytes > usages[j].bytes
+		}
+		return usages[i].repo < usages[j].repo
+	})
+
+	fmt.Fprintln(out)
+	writeCacheUsageTable(out, usages)
+	return nil
+}
+
+// writeCacheUsageTable 以表格形式输出按仓库的缓存占用。
+func writeCacheUsageTable(out io.Writer, usages []*cacheRepoUsage) {
+	repoWidth := len("Repository")
+	for _, u := range usages {
+		repoWidth = max(repoWidth, len(u.repo))
+	}
+
+	fmt.Fprintf(out, "%-*s  %7s  %7s  %10s\n", repoWidth, "Repository", "Entries", "Indexes", "Size")
+	fmt.Fprintln(out, strings.Repeat("─", repoWidth+2+7+2+7+2+10))
+	for _, u := range usages {
+		fmt.Fprintf(out, "%-*s  %7d  %7d  %10s\n", repoWidth, u.repo, u.entries, u.indexes, formatBytes(u.bytes))
+	}
+}
+
+// runCachePrune 清理失效、孤立与过期的缓存文件。
+func runCachePrune(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	repos, err := repo.LoadRepos()
+	if err != nil {
+		return err
+	}
+	registered := make(map[string]struct{}, len(repos))
+	for _, p := range repos {
+		registered[filepath.Clean(p)] = struct{}{}
+	}
+
+	files, err := cache.List()
+	if err != nil {
+		return err
+	}
+
+	// 同一仓库 + 分支组合的指纹只计算一次
+	fingerprints := make(map[string]string)
+	fingerprintOf := func(key cache.CacheKey) string {
+		id := fmt.Sprintf("%s\n%s\n%t", key.RepoPath, key.Branch, key.AllBranch)
+		if fp, ok := fingerprints[id]; ok {
+			return fp
+		}
+		fp, err := stats.RepoFingerprint(key.RepoPath, stats.BranchOption{Branch: key.Branch, AllBranches: key.AllBranch})
+		if err != nil {
+			fp = ""
+		}
+		fingerprints[id] = fp
+		return fp
+	}
+
+	now := time.Now()
+	removed := 0
+	var freed int64
+	for _, f := range files {
+		reason := ""
+		_, isRegistered := registered[filepath.Clean(f.RepoPath)]
+		switch {
+		case f.RepoPath == "":
+			reason = "unreadable"
+		case !isRegistered:
+			reason = "repository not registered"
+		case f.Index:
+			// 已注册仓库的提交索引始终可增量复用
+		case cachePruneTTL > 0 && now.Sub(f.Entry.CreatedAt) > cachePruneTTL:
+			reason = "expired"
+		case fingerprintOf(f.Entry.Key) != f.Entry.Key.HEADHash:
+			reason = "stale fingerprint"
+		}
+		if reason == "" {
+			continue
+		}
+
+		if !cachePruneDryRun {
+			if err := cache.Remove(f.Path); err != nil {
+				return err
+			}
+		}
+		removed++
+		freed += f.Size
+		fmt.Fprintf(out, "%s (%s)\n", filepath.Base(f.Path), reason)
+	}
+
+	verb := "pruned"
+	if cachePruneDryRun {
+		verb = "would prune"
+	}
+	fmt.Fprintf(out, "%s %d file(s), %s\n", verb, removed, formatBytes(freed))
+	return nil
+}
+
+// runCacheClear 删除全部缓存文件，或仅删除指定仓库的缓存文件。
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	target := ""
+	if len(args) == 1 {
+		abs, err := filepath.Abs(strings.TrimSpace(args[0]))
+		if err != nil {
+			return err
+		}
+		target = filepath.Clean(abs)
+	}
+
+	files, err := cache.List()
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	var freed int64
+	for _, f := range files {
+		if target != "" && filepath.Clean(f.RepoPath) != target {
+			continue
+		}
+		if err := cache.Remove(f.Path); err != nil {
+			return err
+		}
+		removed++
+		freed += f.Size
+	}
+
+	fmt.Fprintf(out, "removed %d file(s), %s\n", removed, formatBytes(freed))
+	return nil
+}
+
+// formatBytes 将字节数格式化为易读的 B/KB/MB/GB。
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n)
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		value /= unit
+		if value < unit || suffix == "GB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// init 注册 cache 命令。
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-visible/internal/cache"
+	"git-visible/internal/config"
+	"git-visible/internal/stats"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachePrune_RemovesStaleAndUnregistered(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	repoA := filepath.Join(home, "code", "repo-a")
+	repoB := filepath.Join(home, "code", "repo-b")
+	createRepoWithCommits(t, repoA, 2, "test@example.com", base)
+	createRepoWithCommits(t, repoB, 2, "test@example.com", base)
+
+	collectForCache(t, home, []string{repoA, repoB}, base)
+
+	// repo-a 前进一个提交，旧结果缓存失效；repo-b 被移出仓库列表。
+	appendCommit(t, repoA, base.Add(time.Hour))
+	writeReposFile(t, home, []string{repoA})
+
+	out, err := executeCacheCommand(t, "prune")
+	require.NoError(t, err)
+	assert.Contains(t, out, "stale fingerprint")
+	assert.Contains(t, out, "repository not registered")
+
+	files, err := cache.List()
+	require.NoError(t, err)
+	for _, f := range files {
+		assert.Equal(t, repoA, f.RepoPath, "only repo-a files should survive")
+		if !f.Index {
+			t.Errorf("stale result entry %s should have been pruned", f.Path)
+		}
+	}
+}
+
+func TestCachePrune_DryRunKeepsFiles(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	repoA := filepath.Join(home, "code", "repo-a")
+	createRepoWithCommits(t, repoA, 1, "test@example.com", base)
+	collectForCache(t, home, []string{repoA}, base)
+	writeReposFile(t, home, nil)
+
+	before, err := cache.List()
+	require.NoError(t, err)
+	require.NotEmpty(t, before)
+
+	out, err := executeCacheCommand(t, "prune", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "would prune 2 file(s)")
+
+	after, err := cache.List()
+	require.NoError(t, err)
+	assert.Len(t, after, len(before))
+}
+
+func TestCacheClear_SingleRepo(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	repoA := filepath.Join(home, "code", "repo-a")
+	repoB := filepath.Join(home, "code", "repo-b")
+	createRepoWithCommits(t, repoA, 1, "test@example.com", base)
+	createRepoWithCommits(t, repoB, 1, "test@example.com", base)
+	collectForCache(t, home, []string{repoA, repoB}, base)
+
+	out, err := executeCacheCommand(t, "clear", repoA)
+	require.NoError(t, err)
+	assert.Contains(t, out, "removed 2 file(s)")
+
+	files, err := cache.List()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		assert.Equal(t, repoB, f.RepoPath)
+	}
+
+	out, err = executeCacheCommand(t, "clear")
+	require.NoError(t, err)
+	assert.Contains(t, out, "removed 2 file(s)")
+}
+
+func TestCacheStats_PerRepoBreakdown(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	repoA := filepath.Join(home, "code", "repo-a")
+	createRepoWithCommits(t, repoA, 1, "test@example.com", base)
+	collectForCache(t, home, []string{repoA}, base)
+
+	out, err := executeCacheCommand(t, "stats")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Result entries:  1")
+	assert.Contains(t, out, "Commit indexes:  1")
+	assert.Contains(t, out, repoA)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KB", formatBytes(1536))
+	assert.Equal(t, "2.0 MB", formatBytes(2<<20))
+	assert.Equal(t, "3.0 GB", formatBytes(3<<30))
+}
+
+func executeCacheCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	cachePruneTTL = defaultCacheTTL
+	cachePruneDryRun = false
+
+	cmd := newCacheCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// collectForCache 注册仓库并执行一次带缓存的收集，生成结果缓存与提交索引。
+func collectForCache(t *testing.T, home string, repos []string, day time.Time) {
+	t.Helper()
+
+	writeReposFile(t, home, repos)
+	_, err := stats.CollectStats(repos, nil, day, day, stats.BranchOption{}, nil, true)
+	require.NoError(t, err)
+}
+
+func appendCommit(t *testing.T, repoPath string, when time.Time) {
+	t.Helper()
+
+	r, err := git.PlainOpen(repoPath)
+	require.NoError(t, err)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "extra.txt"), []byte(when.String()), 0o644))
+	_, err = wt.Add("extra.txt")
+	require.NoError(t, err)
+
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: when}
+	_, err = wt.Commit("extra commit", &git.CommitOptions{Author: sig, Committer: sig})
+	require.NoError(t, err)
+}
@@ -2,16 +2,32 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"git-visible/internal/cache"
 	"git-visible/internal/config"
 	"git-visible/internal/repo"
 	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var errNoRepositoriesAdded = errors.New("no repositories added")
 
+// autoAliasName 是 email 为 auto 时由识别出的邮箱组成的隐式别名组名称。
+const autoAliasName = "me (auto)"
+
+// detectEmailsFn 从 git 配置中识别"我"的邮箱，测试中可替换。
+var detectEmailsFn = repo.DetectEmails
+
 // RunContext holds the common initialization result for commands.
 type RunContext struct {
 	Repos          []string
@@ -20,17 +36,28 @@ type RunContext struct {
 	Until          time.Time
 	Config         *config.Config
 	NormalizeEmail func(string) string // 邮箱别名规范化函数，无别名时为 nil
+	WeekStart      time.Weekday        // 热力图每周第一天（--week-start 或配置 week_start，默认周日）
+	Timezone       stats.Timezone      // 按天划分使用的时区（--tz 或配置 timezone，默认本地时区）
 
 	months int
 }
 
+// calendarFlags 是影响日期划分的命令行标志，空值表示使用配置。
+type calendarFlags struct {
+	weekStart string // --week-start
+	timezone  string // --tz
+}
+
 // prepareRun performs common command initialization:
 // load config, load repos, parse time range, merge emails.
-func prepareRun(emails []string, months int, since, until string) (*RunContext, error) {
+// cal 中的标志优先于配置 week_start / timezone。
+// 未指定 emails 且配置 email 为 auto 时，使用从 git 配置识别出的邮箱（视为同一身份）。
+func prepareRun(emails []string, months int, since, until string, cal calendarFlags) (*RunContext, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, err
 	}
+	cache.SetSizeLimit(int64(cfg.CacheMaxMB) << 20)
 
 	// 清洗命令行传入的邮箱参数
 	cleanedEmails := make([]string, 0, len(emails))
@@ -61,19 +88,43 @@ func prepareRun(emails []string, months int, since, until string) (*RunContext,
 		rangeMonths = cfg.Months
 	}
 
-	start, end, err := stats.TimeRange(since, until, rangeMonths)
+	ws, err := resolveWeekStart(cfg, cal.weekStart, time.Sunday)
+	if err != nil {
+		return nil, err
+	}
+	tz, err := resolveTimezone(cfg, cal.timezone)
+	if err != nil {
+		return nil, err
+	}
+	// 日期解析、"今天"与按天划分都使用该时区
+	start, end, err := stats.TimeRange(since, until, rangeMonths, ws, tz.Location)
 	if err != nil {
 		return nil, err
 	}
 
 	mergedEmails := cleanedEmails
-	if len(mergedEmails) == 0 && strings.TrimSpace(cfg.Email) != "" {
+	aliasCfg := cfg
+	switch {
+	case len(mergedEmails) > 0:
+	case cfg.AutoEmail():
+		detected := detectEmailsFn(repos)
+		if len(detected) == 0 {
+			return nil, fmt.Errorf("email is set to auto but no user.email was found in git config (set one with: git config --global user.email you@example.com)")
+		}
+		for _, d := range detected {
+			mergedEmails = append(mergedEmails, d.Email)
+		}
+		// 识别出的邮箱作为隐式别名组追加在已配置的别名之后，统计时视为同一个人
+		withMe := *cfg
+		withMe.Aliases = append(append([]config.Alias(nil), cfg.Aliases...), config.Alias{Name: autoAliasName, Emails: mergedEmails})
+		aliasCfg = &withMe
+	case strings.TrimSpace(cfg.Email) != "":
 		mergedEmails = []string{strings.TrimSpace(cfg.Email)}
 	}
 
 	var normalizeEmail func(string) string
-	if len(cfg.Aliases) > 0 {
-		normalizeEmail = cfg.NormalizeEmail
+	if len(aliasCfg.Aliases) > 0 {
+		normalizeEmail = aliasCfg.NormalizeEmail
 	}
 
 	return &RunContext{
@@ -83,6 +134,215 @@ func prepareRun(emails []string, months int, since, until string) (*RunContext,
 		Until:          end,
 		Config:         cfg,
 		NormalizeEmail: normalizeEmail,
+		WeekStart:      ws,
+		Timezone:       tz,
 		months:         resolvedMonths,
 	}, nil
 }
+
+// mergeModeFromFlags 将 --no-merges/--merges-only 标志转换为合并提交模式。
+// 两个标志由 cobra 保证互斥。
+func mergeModeFromFlags(noMerges, mergesOnly bool) stats.MergeMode {
+	switch {
+	case noMerges:
+		return stats.MergesExclude
+	case mergesOnly:
+		return stats.MergesOnly
+	default:
+		return stats.MergesInclude
+	}
+}
+
+// addMergeFlags 为命令添加互斥的 --no-merges/--merges-only 标志。
+func addMergeFlags(cmd *cobra.Command, noMerges, mergesOnly *bool) {
+	cmd.Flags().BoolVar(noMerges, "no-merges", false, "Skip merge commits (like git log --no-merges)")
+	cmd.Flags().BoolVar(mergesOnly, "merges-only", false, "Only count merge commits (like git log --merges)")
+	cmd.MarkFlagsMutuallyExclusive("no-merges", "merges-only")
+}
+
+// addPathFlag 为命令添加可重复的 --path 路径过滤标志。
+func addPathFlag(cmd *cobra.Command, paths *[]string) {
+	cmd.Flags().StringArrayVar(paths, "path", nil, "Only count commits touching matching paths (repeatable; glob, ** and !negation)")
+}
+
+// addCoAuthorsFlag 为命令添加 --co-authors 标志（与配置项 co_authors 取或）。
+func addCoAuthorsFlag(cmd *cobra.Command, coAuthors *bool) {
+	cmd.Flags().BoolVar(coAuthors, "co-authors", false, "Also credit Co-authored-by trailers (default from config co_authors)")
+}
+
+// collectOptions 基于公共初始化结果构建收集参数，命令只需补充自身特有的选项。
+func (c *RunContext) collectOptions(branch stats.BranchOption, useCache bool) stats.CollectOptions {
+	return stats.CollectOptions{
+		Repos:          c.Repos,
+		Emails:         c.Emails,
+		Since:          c.Since,
+		Until:          c.Until,
+		Branch:         branch.Branch,
+		AllBranch:      branch.AllBranches,
+		UseCache:       useCache,
+		NormalizeEmail: c.NormalizeEmail,
+		CoAuthors:      c.Config.CoAuthors,
+		AuthorLocal:    c.Timezone.AuthorLocal,
+		MailmapFile:    c.Config.MailmapPath(),
+	}
+}
+
+// resolveTheme 解析热力图主题：name（命令行标志）优先，为空时使用配置 theme；
+// 配置 themes 中的自定义主题优先于同名内置主题。
+func resolveTheme(cfg *config.Config, name string) (stats.Theme, error) {
+	name = strings.TrimSpace(name)
+	if name == "" && cfg != nil {
+		name = strings.TrimSpace(cfg.Theme)
+	}
+
+	var custom map[string]stats.Theme
+	if cfg != nil && len(cfg.Themes) > 0 {
+		custom = make(map[string]stats.Theme, len(cfg.Themes))
+		for themeName, c := range cfg.Themes {
+			t, err := stats.NewTheme(themeName, [4]string{c.Empty, c.Low, c.Medium, c.High}, c.Today)
+			if err != nil {
+				return stats.Theme{}, err
+			}
+			custom[themeName] = t
+		}
+	}
+	return stats.ResolveTheme(name, custom)
+}
+
+// resolveThresholds 解析热力图档位：s（命令行标志）优先，为空时使用配置 thresholds。
+func resolveThresholds(cfg *config.Config, s string) (stats.Thresholds, error) {
+	if strings.TrimSpace(s) == "" && cfg != nil {
+		s = cfg.Thresholds
+	}
+	return stats.ParseThresholds(s)
+}
+
+// resolveWeekStart 解析每周第一天：s（命令行标志）优先，其次为配置 week_start，都未设置时返回 fallback。
+func resolveWeekStart(cfg *config.Config, s string, fallback time.Weekday) (time.Weekday, error) {
+	if strings.TrimSpace(s) == "" && cfg != nil {
+		s = cfg.WeekStart
+	}
+	if strings.TrimSpace(s) == "" {
+		return fallback, nil
+	}
+	return stats.ParseWeekStart(s)
+}
+
+// resolveTimezone 解析按天划分使用的时区：s（命令行标志）优先，为空时使用配置 timezone。
+func resolveTimezone(cfg *config.Config, s string) (stats.Timezone, error) {
+	if strings.TrimSpace(s) == "" && cfg != nil {
+		s = cfg.Timezone
+	}
+	return stats.ParseTimezone(s)
+}
+
+// addTimezoneFlag 为命令添加 --tz 标志（默认取配置 timezone）。
+func addTimezoneFlag(cmd *cobra.Command, tz *string) {
+	cmd.Flags().StringVar(tz, "tz", "", "Timezone for day bucketing: local, author (each commit's own author offset), or an IANA name like UTC (default: config timezone or local)")
+}
+
+// addWeekStartFlag 为命令添加 --week-start 标志（默认取配置 week_start）。
+func addWeekStartFlag(cmd *cobra.Command, weekStart *string) {
+	cmd.Flags().StringVar(weekStart, "week-start", "", "First day of the week, e.g. monday or sunday (default: config week_start; heatmap rows start on Sunday, weekly buckets on ISO Monday)")
+}
+
+// addThemeFlags 为命令添加 --theme/--thresholds 标志（默认取配置 theme/thresholds）。
+func addThemeFlags(cmd *cobra.Command, theme, thresholds *string) {
+	cmd.Flags().StringVar(theme, "theme", "", "Color theme: github, halloween, colorblind-safe, monochrome, or a custom theme from config (default: config theme or github)")
+	cmd.Flags().StringVar(thresholds, "thresholds", "", "Intensity thresholds: fixed, quantile, or three increasing lower bounds like 1,10,20 (default: config thresholds or fixed)")
+}
+
+// isTerminalFn 报告文件描述符是否为终端，测试中可替换。
+var isTerminalFn = term.IsTerminal
+
+// terminalSizeFn 返回终端的列数与行数，测试中可替换。
+var terminalSizeFn = term.GetSize
+
+// terminalWidth 返回输出终端的列数：输出为终端时读取窗口大小，否则使用 COLUMNS 环境变量，
+// 均不可用时返回 0（宽度未知）。
+func terminalWidth(out io.Writer) int {
+	if f, ok := out.(*os.File); ok && isTerminalFn(int(f.Fd())) {
+		if w, _, err := terminalSizeFn(int(f.Fd())); err == nil && w > 0 {
+			return w
+		}
+	}
+	if w, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS"))); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
+
+// addColorFlags 为命令添加 --color/--charset 标志。
+func addColorFlags(cmd *cobra.Command, color, charset *string) {
+	cmd.Flags().StringVar(color, "color", "auto", "Colorize output: auto (only on a terminal, honors NO_COLOR), always, never")
+	cmd.Flags().StringVar(charset, "charset", "unicode", "Cell characters: unicode (block glyphs) or ascii (. o O @)")
+}
+
+// resolveColor 根据 --color 取值决定是否输出 ANSI 颜色。
+// auto 模式下设置了 NO_COLOR（非空）、TERM=dumb 或输出不是终端时不着色；always/never 优先于 NO_COLOR。
+func resolveColor(mode string, out io.Writer) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "", "auto":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		f, ok := out.(*os.File)
+		return ok && isTerminalFn(int(f.Fd())), nil
+	default:
+		return false, fmt.Errorf("unsupported color mode %q (supported: auto, always, never)", mode)
+	}
+}
+
+// pendingOutput 是 --output 的待提交输出：内容先写入目标同目录下的临时文件，
+// commit 时再 rename 覆盖目标；中途失败时 discard 删除临时文件，已有的目标文件保持不变。
+type pendingOutput struct {
+	file *os.File
+	path string
+}
+
+// createPendingOutput 在 path 所在目录创建临时文件。
+// 权限与 os.Create 一致：目标已存在时沿用其权限，否则为 0666 去掉 umask。
+func createPendingOutput(path string) (*pendingOutput, error) {
+	dir, base := filepath.Dir(path), filepath.Base(path)
+	for i := 0; ; i++ {
+		name := filepath.Join(dir, fmt.Sprintf(".%s.%d-%d.tmp", base, os.Getpid(), i))
+		file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+		if errors.Is(err, fs.ErrExist) && i < 100 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("create output: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			if err := file.Chmod(info.Mode().Perm()); err != nil {
+				_ = file.Close()
+				_ = os.Remove(name)
+				return nil, fmt.Errorf("create output: %w", err)
+			}
+		}
+		return &pendingOutput{file: file, path: path}, nil
+	}
+}
+
+// commit 关闭临时文件并 rename 到目标路径。
+func (p *pendingOutput) commit() error {
+	if err := p.file.Close(); err != nil {
+		_ = os.Remove(p.file.Name())
+		return err
+	}
+	if err := os.Rename(p.file.Name(), p.path); err != nil {
+		_ = os.Remove(p.file.Name())
+		return err
+	}
+	return nil
+}
+
+// discard 关闭并删除临时文件，目标文件不受影响；commit 之后调用为空操作。
+func (p *pendingOutput) discard() {
+	_ = p.file.Close()
+	_ = os.Remove(p.file.Name())
+}
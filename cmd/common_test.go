@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -15,7 +17,7 @@ func TestPrepareRun_EmailCleaning_TrimWhitespace(t *testing.T) {
 	writeReposFile(t, home, []string{filepath.Join(home, "repo-1")})
 	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
 
-	runCtx, err := prepareRun([]string{" test@example.com "}, 0, "2025-01-01", "2025-12-31")
+	runCtx, err := prepareRun([]string{" test@example.com "}, 0, "2025-01-01", "2025-12-31", calendarFlags{})
 	require.NoError(t, err)
 	assert.Equal(t, []string{"test@example.com"}, runCtx.Emails)
 }
@@ -25,7 +27,7 @@ func TestPrepareRun_EmailCleaning_FilterBlankValues(t *testing.T) {
 	writeReposFile(t, home, []string{filepath.Join(home, "repo-1")})
 	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
 
-	runCtx, err := prepareRun([]string{"  "}, 0, "2025-01-01", "2025-12-31")
+	runCtx, err := prepareRun([]string{"  "}, 0, "2025-01-01", "2025-12-31", calendarFlags{})
 	require.NoError(t, err)
 	assert.Empty(t, runCtx.Emails)
 }
@@ -35,11 +37,61 @@ func TestPrepareRun_EmailCleaning_FallbackToTrimmedConfigEmail(t *testing.T) {
 	writeReposFile(t, home, []string{filepath.Join(home, "repo-1")})
 	setTestConfig(t, config.Config{Email: " config@example.com ", Months: config.DefaultMonths})
 
-	runCtx, err := prepareRun(nil, 0, "2025-01-01", "2025-12-31")
+	runCtx, err := prepareRun(nil, 0, "2025-01-01", "2025-12-31", calendarFlags{})
 	require.NoError(t, err)
 	assert.Equal(t, []string{"config@example.com"}, runCtx.Emails)
 }
 
+func TestPrepareRun_EmailAuto_UsesGitConfigAsOneIdentity(t *testing.T) {
+	home := withGitConfig(t, "[user]\n\temail = me@personal.dev\n")
+	repoPath := filepath.Join(home, "work", "api")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, ".git", "config"), []byte("[user]\n\temail = me@corp.com\n"), 0o644))
+	writeReposFile(t, home, []string{repoPath})
+	setTestConfig(t, config.Config{
+		Email:   "auto",
+		Months:  config.DefaultMonths,
+		Aliases: []config.Alias{{Name: "Bob", Emails: []string{"bob@example.com", "bob@gmail.com"}}},
+	})
+
+	runCtx, err := prepareRun(nil, 0, "2025-01-01", "2025-12-31", calendarFlags{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"me@personal.dev", "me@corp.com"}, runCtx.Emails)
+	require.NotNil(t, runCtx.NormalizeEmail)
+	assert.Equal(t, "me@personal.dev", runCtx.NormalizeEmail("ME@corp.com"))
+	assert.Equal(t, "bob@example.com", runCtx.NormalizeEmail("bob@gmail.com"))
+	// 隐式别名组不写回配置
+	assert.Len(t, runCtx.Config.Aliases, 1)
+
+	// 显式 -e 优先于自动识别
+	runCtx, err = prepareRun([]string{"other@example.com"}, 0, "2025-01-01", "2025-12-31", calendarFlags{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other@example.com"}, runCtx.Emails)
+	assert.Equal(t, "me@corp.com", runCtx.NormalizeEmail("me@corp.com"))
+}
+
+func TestPrepareRun_EmailAuto_NothingDetected_ReturnsError(t *testing.T) {
+	home := withGitConfig(t, "")
+	writeReposFile(t, home, []string{filepath.Join(home, "repo-1")})
+	setTestConfig(t, config.Config{Email: "auto", Months: config.DefaultMonths})
+
+	_, err := prepareRun(nil, 0, "2025-01-01", "2025-12-31", calendarFlags{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no user.email was found")
+}
+
+// withGitConfig 使用临时 HOME，并以 content 作为唯一的全局 git 配置（屏蔽系统级配置）。
+func withGitConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	home := withTempHome(t)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(content), 0o644))
+	return home
+}
+
 func setTestConfig(t *testing.T, cfg config.Config) {
 	t.Helper()
 
@@ -53,3 +105,50 @@ func setTestConfig(t *testing.T, cfg config.Config) {
 		require.NoError(t, config.Save(original))
 	})
 }
+
+func TestResolveColor(t *testing.T) {
+	orig := isTerminalFn
+	t.Cleanup(func() { isTerminalFn = orig })
+	isTerminalFn = func(int) bool { return true }
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	color, err := resolveColor("auto", os.Stdout)
+	require.NoError(t, err)
+	assert.True(t, color, "auto colors a terminal")
+
+	color, err = resolveColor("auto", &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.False(t, color, "auto does not color non-file writers")
+
+	t.Setenv("NO_COLOR", "1")
+	color, err = resolveColor("auto", os.Stdout)
+	require.NoError(t, err)
+	assert.False(t, color, "NO_COLOR disables auto color")
+
+	color, err = resolveColor("always", &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.True(t, color, "always overrides NO_COLOR and TTY detection")
+
+	color, err = resolveColor("never", os.Stdout)
+	require.NoError(t, err)
+	assert.False(t, color)
+
+	_, err = resolveColor("sometimes", os.Stdout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported color mode")
+}
+
+func TestTerminalWidth(t *testing.T) {
+	origTerm, origSize := isTerminalFn, terminalSizeFn
+	t.Cleanup(func() { isTerminalFn, terminalSizeFn = origTerm, origSize })
+	isTerminalFn = func(int) bool { return true }
+	terminalSizeFn = func(int) (int, int, error) { return 100, 40, nil }
+	t.Setenv("COLUMNS", "")
+
+	assert.Equal(t, 100, terminalWidth(os.Stdout))
+	assert.Equal(t, 0, terminalWidth(&bytes.Buffer{}), "width is unknown when not writing to a terminal")
+
+	t.Setenv("COLUMNS", "72")
+	assert.Equal(t, 72, terminalWidth(&bytes.Buffer{}))
+}
@@ -18,6 +18,12 @@ var (
 	compareYears   []int    // 要对比的年份列表（--period YYYY 的快捷方式）
 	compareFormat  string   // 输出格式：table/json/csv
 	compareNoCache bool     // 是否禁用缓存
+	compareLines   bool     // 是否统计代码行变更
+
+	compareNoMerges   bool   // 是否跳过合并提交
+	compareMergesOnly bool   // 是否只统计合并提交
+	compareCoAuthors  bool   // 是否计入 Co-authored-by 共同作者
+	compareTZ         string // 按天划分使用的时区，空表示使用配置或本地时区
 )
 
 // compareCmd 实现 compare 子命令，用于对比多个邮箱或多个时间段的贡献统计。
@@ -39,6 +45,10 @@ func init() {
 	compareCmd.Flags().IntSliceVar(&compareYears, "year", nil, "Years to compare (repeatable; shortcut for --period YYYY)")
 	compareCmd.Flags().StringVarP(&compareFormat, "format", "f", "table", "Output format: table/json/csv")
 	compareCmd.Flags().BoolVar(&compareNoCache, "no-cache", false, "Disable cache, force full scan")
+	compareCmd.Flags().BoolVar(&compareLines, "lines", false, "Also compare added/deleted lines and files changed (slower)")
+	addMergeFlags(compareCmd, &compareNoMerges, &compareMergesOnly)
+	addCoAuthorsFlag(compareCmd, &compareCoAuthors)
+	addTimezoneFlag(compareCmd, &compareTZ)
 
 	compareCmd.MarkFlagsMutuallyExclusive("email", "period")
 	compareCmd.MarkFlagsMutuallyExclusive("email", "year")
@@ -75,7 +85,7 @@ func runCompare(cmd *cobra.Command, _ []string) error {
 		prepareUntil = ""
 	}
 
-	runCtx, err := prepareRun(compareEmails, 0, prepareSince, prepareUntil)
+	runCtx, err := prepareRun(compareEmails, 0, prepareSince, prepareUntil, calendarFlags{timezone: compareTZ})
 	if err != nil {
 		if errors.Is(err, errNoRepositoriesAdded) {
 			fmt.Fprintln(out, "no repositories added")
@@ -90,7 +100,12 @@ func runCompare(cmd *cobra.Command, _ []string) error {
 			return fmt.Errorf("at least 2 emails are required to compare")
 		}
 
-		items, collectErr, allFailed := collectCompareByEmail(runCtx.Repos, emails, runCtx.Since, runCtx.Until, runCtx.NormalizeEmail, !compareNoCache)
+		opts := runCtx.collectOptions(stats.BranchOption{}, !compareNoCache)
+		opts.Emails = emails
+		opts.LineStats = compareLines
+		opts.Merges = mergeModeFromFlags(compareNoMerges, compareMergesOnly)
+		opts.CoAuthors = opts.CoAuthors || compareCoAuthors
+		items, collectErr, allFailed := collectCompareByEmail(opts)
 		if collectErr != nil {
 			if allFailed {
 				return fmt.Errorf("all repositories failed to collect stats: %w", collectErr)
@@ -116,14 +131,18 @@ func runCompare(cmd *cobra.Command, _ []string) error {
 
 		periods := make([]stats.Period, 0, len(periodArgs))
 		for _, p := range periodArgs {
-			period, err := stats.ParsePeriod(p)
+			period, err := stats.ParsePeriod(p, runCtx.Timezone.Location)
 			if err != nil {
 				return err
 			}
 			periods = append(periods, period)
 		}
 
-		items, collectErr, allFailed := collectCompareByPeriod(runCtx.Repos, periods, runCtx.Emails, runCtx.NormalizeEmail, !compareNoCache)
+		opts := runCtx.collectOptions(stats.BranchOption{}, !compareNoCache)
+		opts.LineStats = compareLines
+		opts.Merges = mergeModeFromFlags(compareNoMerges, compareMergesOnly)
+		opts.CoAuthors = opts.CoAuthors || compareCoAuthors
+		items, collectErr, allFailed := collectCompareByPeriod(opts, periods)
 		if collectErr != nil {
 			if allFailed {
 				return fmt.Errorf("all repositories failed to collect stats: %w", collectErr)
@@ -147,36 +166,38 @@ func runCompare(cmd *cobra.Command, _ []string) error {
 	}
 }
 
-// collectCompareByEmail 按邮箱收集对比数据。
-func collectCompareByEmail(repos []string, emails []string, start, end time.Time, normalizeEmail func(string) string, useCache bool) ([]emailCompareItem, error, bool) {
-	byEmail, err := stats.CollectStatsByEmails(repos, emails, start, end, stats.BranchOption{}, normalizeEmail, useCache)
+// collectCompareByEmail 按邮箱收集对比数据，opts.Emails 为待对比的邮箱列表。
+func collectCompareByEmail(opts stats.CollectOptions) ([]emailCompareItem, error, bool) {
+	byEmail, err := stats.CollectActivityByEmails(opts)
 	allFailed := err != nil && byEmail == nil
 
-	items := make([]emailCompareItem, 0, len(emails))
-	for _, email := range emails {
+	items := make([]emailCompareItem, 0, len(opts.Emails))
+	for _, email := range opts.Emails {
 		lookupEmail := email
-		if normalizeEmail != nil {
-			lookupEmail = normalizeEmail(email)
+		if opts.NormalizeEmail != nil {
+			lookupEmail = opts.NormalizeEmail(email)
 		}
 		daily := byEmail[lookupEmail]
 		if daily == nil {
-			daily = make(map[time.Time]int)
+			daily = make(map[time.Time]stats.DayActivity)
 		}
 		items = append(items, emailCompareItem{
 			Email:   email,
-			Metrics: stats.CalculateCompareMetrics(daily),
+			Metrics: compareMetrics(daily, opts.LineStats),
 		})
 	}
 	return items, err, allFailed
 }
 
-// collectCompareByPeriod 按时间段收集对比数据。
-func collectCompareByPeriod(repos []string, periods []stats.Period, emails []string, normalizeEmail func(string) string, useCache bool) ([]periodCompareItem, error, bool) {
+// collectCompareByPeriod 按时间段收集对比数据，opts.Since/opts.Until 会被各时间段覆盖。
+func collectCompareByPeriod(opts stats.CollectOptions, periods []stats.Period) ([]periodCompareItem, error, bool) {
 	items := make([]periodCompareItem, 0, len(periods))
 	var errs []error
 	allFailed := true
 	for _, period := range periods {
-		perRepo, err := stats.CollectStatsPerRepo(repos, emails, period.Start, period.End, stats.BranchOption{}, normalizeEmail, useCache)
+		opts.Since = period.Start
+		opts.Until = period.End
+		perRepo, err := stats.CollectActivityPerRepo(opts)
 		if err != nil {
 			errs = append(errs, err)
 		}
@@ -186,17 +207,25 @@ func collectCompareByPeriod(repos []string, periods []stats.Period, emails []str
 		daily := mergePerRepoStats(perRepo)
 		items = append(items, periodCompareItem{
 			Period:  period,
-			Metrics: stats.CalculateCompareMetrics(daily),
+			Metrics: compareMetrics(daily, opts.LineStats),
 		})
 	}
 	return items, errors.Join(errs...), allFailed
 }
 
-func mergePerRepoStats(perRepo map[string]map[time.Time]int) map[time.Time]int {
-	merged := make(map[time.Time]int)
+// compareMetrics 计算对比指标，仅在开启 --lines 时携带代码行统计。
+func compareMetrics(daily map[time.Time]stats.DayActivity, lineStats bool) stats.CompareMetrics {
+	if lineStats {
+		return stats.CalculateCompareMetricsActivity(daily)
+	}
+	return stats.CalculateCompareMetrics(stats.ActivityCounts(daily))
+}
+
+func mergePerRepoStats(perRepo map[string]map[time.Time]stats.DayActivity) map[time.Time]stats.DayActivity {
+	merged := make(map[time.Time]stats.DayActivity)
 	for _, daily := range perRepo {
-		for day, count := range daily {
-			merged[day] += count
+		for day, act := range daily {
+			merged[day] = merged[day].Add(act)
 		}
 	}
 	return merged
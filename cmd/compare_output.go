@@ -16,8 +16,14 @@ func writeCompareEmailTable(out io.Writer, items []emailCompareItem) error {
 	if len(items) == 0 {
 		return nil
 	}
+	headers, metricLabels, values := compareEmailMatrix(items)
+	return writeCompareMatrixTable(out, headers, metricLabels, values)
+}
+
+// compareEmailMatrix 构建邮箱对比矩阵（列=邮箱，行=指标），items 不能为空。
+func compareEmailMatrix(items []emailCompareItem) (headers []string, metricLabels []string, values [][]string) {
 
-	metricLabels := []string{
+	metricLabels = []string{
 		"Total commits",
 		"Active days",
 		"Avg commits/day",
@@ -25,7 +31,7 @@ func writeCompareEmailTable(out io.Writer, items []emailCompareItem) error {
 		"Longest streak",
 	}
 
-	values := make([][]string, len(metricLabels))
+	values = make([][]string, len(metricLabels))
 	for i := range values {
 		values[i] = make([]string, 0, len(items))
 	}
@@ -38,12 +44,23 @@ func writeCompareEmailTable(out io.Writer, items []emailCompareItem) error {
 		values[4] = append(values[4], streakLabel(it.Metrics.LongestStreakDays))
 	}
 
-	headers := make([]string, 0, len(items))
+	headers = make([]string, 0, len(items))
 	for _, it := range items {
 		headers = append(headers, it.Email)
 	}
 
-	return writeCompareMatrixTable(out, headers, metricLabels, values)
+	if items[0].Metrics.Lines != nil {
+		for _, row := range lineMetricRows() {
+			metricLabels = append(metricLabels, row.label)
+			rowValues := make([]string, 0, len(items))
+			for _, it := range items {
+				rowValues = append(rowValues, fmt.Sprintf("%d", row.val(it.Metrics)))
+			}
+			values = append(values, rowValues)
+		}
+	}
+
+	return headers, metricLabels, values
 }
 
 // writeComparePeriodTable 以表格格式输出时间段对比结果。
@@ -51,19 +68,24 @@ func writeComparePeriodTable(out io.Writer, items []periodCompareItem) error {
 	if len(items) == 0 {
 		return nil
 	}
+	headers, metricLabels, values := comparePeriodMatrix(items)
+	return writeCompareMatrixTable(out, headers, metricLabels, values)
+}
 
-	metricLabels := []string{
+// comparePeriodMatrix 构建时间段对比矩阵（列=时间段与相邻变化，行=指标），items 不能为空。
+func comparePeriodMatrix(items []periodCompareItem) (headers []string, metricLabels []string, values [][]string) {
+	metricLabels = []string{
 		"Total commits",
 		"Active days",
 		"Avg commits/day",
 	}
 
-	values := make([][]string, len(metricLabels))
+	values = make([][]string, len(metricLabels))
 	for i := range values {
 		values[i] = make([]string, 0, len(items)+len(items)-1)
 	}
 
-	headers := make([]string, 0, len(items)+len(items)-1)
+	headers = make([]string, 0, len(items)+len(items)-1)
 
 	// Columns are interleaved as: P1, P2, Change, P3, Change, ...
 	for i, it := range items {
@@ -101,7 +123,38 @@ func writeComparePeriodTable(out io.Writer, items []periodCompareItem) error {
 		prev = cur
 	}
 
-	return writeCompareMatrixTable(out, headers, metricLabels, values)
+	if items[0].Metrics.Lines != nil {
+		for _, row := range lineMetricRows() {
+			metricLabels = append(metricLabels, row.label)
+			rowValues := []string{fmt.Sprintf("%d", row.val(items[0].Metrics))}
+			for i := 1; i < len(items); i++ {
+				from, to := row.val(items[i-1].Metrics), row.val(items[i].Metrics)
+				rowValues = append(rowValues,
+					fmt.Sprintf("%d", to),
+					percentLabel(stats.CalculatePercentChange(float64(from), float64(to))),
+				)
+			}
+			values = append(values, rowValues)
+		}
+	}
+
+	return headers, metricLabels, values
+}
+
+// lineMetric 描述一行代码行变更指标（表格标签、CSV 键与取值函数）。
+type lineMetric struct {
+	label string
+	key   string
+	val   func(m stats.CompareMetrics) int
+}
+
+// lineMetricRows 返回代码行变更相关的指标行，调用方需保证 Metrics.Lines 非 nil。
+func lineMetricRows() []lineMetric {
+	return []lineMetric{
+		{"Lines added", "additions", func(m stats.CompareMetrics) int { return m.Lines.Additions }},
+		{"Lines deleted", "deletions", func(m stats.CompareMetrics) int { return m.Lines.Deletions }},
+		{"Files changed", "files", func(m stats.CompareMetrics) int { return m.Lines.Files }},
+	}
 }
 
 // writeCompareMatrixTable 输出矩阵形式的对比表格（行=指标，列=对比项）。
@@ -208,15 +261,16 @@ type compareJSONOutput struct {
 
 // compareJSONItem 是 JSON 输出中的单个对比项。
 type compareJSONItem struct {
-	Label              string  `json:"label"`
-	Start              string  `json:"start,omitempty"`
-	End                string  `json:"end,omitempty"`
-	TotalCommits       int     `json:"totalCommits"`
-	ActiveDays         int     `json:"activeDays"`
-	AvgCommitsPerDay   float64 `json:"avgCommitsPerDay"`
-	MostActiveDay      string  `json:"mostActiveDay,omitempty"`
-	LongestStreakDays  int     `json:"longestStreakDays,omitempty"`
-	LongestStreakLabel string  `json:"longestStreak,omitempty"`
+	Label              string            `json:"label"`
+	Start              string            `json:"start,omitempty"`
+	End                string            `json:"end,omitempty"`
+	TotalCommits       int               `json:"totalCommits"`
+	ActiveDays         int               `json:"activeDays"`
+	AvgCommitsPerDay   float64           `json:"avgCommitsPerDay"`
+	MostActiveDay      string            `json:"mostActiveDay,omitempty"`
+	LongestStreakDays  int               `json:"longestStreakDays,omitempty"`
+	LongestStreakLabel string            `json:"longestStreak,omitempty"`
+	Lines              *stats.LineTotals `json:"lines,omitempty"`
 }
 
 // compareJSONDelta 是 JSON 输出中相邻时间段之间的变化量。
@@ -230,6 +284,13 @@ type compareJSONDelta struct {
 
 // writeCompareEmailJSON 以 JSON 格式输出邮箱对比结果。
 func writeCompareEmailJSON(out io.Writer, items []emailCompareItem) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(compareEmailJSON(items))
+}
+
+// compareEmailJSON 构建邮箱对比的 JSON 输出结构。
+func compareEmailJSON(items []emailCompareItem) compareJSONOutput {
 	outItems := make([]compareJSONItem, 0, len(items))
 	for _, it := range items {
 		outItems = append(outItems, compareJSONItem{
@@ -240,19 +301,25 @@ func writeCompareEmailJSON(out io.Writer, items []emailCompareItem) error {
 			MostActiveDay:      mostActiveDayLabel(it.Metrics),
 			LongestStreakDays:  it.Metrics.LongestStreakDays,
 			LongestStreakLabel: streakLabel(it.Metrics.LongestStreakDays),
+			Lines:              it.Metrics.Lines,
 		})
 	}
 
-	enc := json.NewEncoder(out)
-	enc.SetIndent("", "  ")
-	return enc.Encode(compareJSONOutput{
+	return compareJSONOutput{
 		Dimension: "email",
 		Items:     outItems,
-	})
+	}
 }
 
 // writeComparePeriodJSON 以 JSON 格式输出时间段对比结果。
 func writeComparePeriodJSON(out io.Writer, items []periodCompareItem) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(comparePeriodJSON(items))
+}
+
+// comparePeriodJSON 构建时间段对比的 JSON 输出结构（含相邻时间段的变化量）。
+func comparePeriodJSON(items []periodCompareItem) compareJSONOutput {
 	outItems := make([]compareJSONItem, 0, len(items))
 	for _, it := range items {
 		outItems = append(outItems, compareJSONItem{
@@ -265,6 +332,7 @@ func writeComparePeriodJSON(out io.Writer, items []periodCompareItem) error {
 			MostActiveDay:      mostActiveDayLabel(it.Metrics),
 			LongestStreakDays:  it.Metrics.LongestStreakDays,
 			LongestStreakLabel: streakLabel(it.Metrics.LongestStreakDays),
+			Lines:              it.Metrics.Lines,
 		})
 	}
 
@@ -286,13 +354,11 @@ func writeComparePeriodJSON(out io.Writer, items []periodCompareItem) error {
 		})
 	}
 
-	enc := json.NewEncoder(out)
-	enc.SetIndent("", "  ")
-	return enc.Encode(compareJSONOutput{
+	return compareJSONOutput{
 		Dimension: "period",
 		Items:     outItems,
 		Changes:   deltas,
-	})
+	}
 }
 
 // percentPtr 将 PercentChange 转换为指针，未定义时返回 nil。
@@ -330,6 +396,16 @@ func writeCompareEmailCSV(out io.Writer, items []emailCompareItem) error {
 		{"longestStreakDays", func(m stats.CompareMetrics) string { return fmt.Sprintf("%d", m.LongestStreakDays) }},
 	}
 
+	if items[0].Metrics.Lines != nil {
+		for _, lm := range lineMetricRows() {
+			val := lm.val
+			rows = append(rows, struct {
+				label string
+				val   func(m stats.CompareMetrics) string
+			}{lm.key, func(m stats.CompareMetrics) string { return fmt.Sprintf("%d", val(m)) }})
+		}
+	}
+
 	for _, row := range rows {
 		r := []string{row.label}
 		for _, it := range items {
@@ -399,6 +475,21 @@ func writeComparePeriodCSV(out io.Writer, items []periodCompareItem) error {
 	if err := writeMetric("avgCommitsPerDay", avgValues); err != nil {
 		return err
 	}
+	if items[0].Metrics.Lines != nil {
+		for _, lm := range lineMetricRows() {
+			lineValues := []string{fmt.Sprintf("%d", lm.val(items[0].Metrics))}
+			for i := 1; i < len(items); i++ {
+				from, to := lm.val(items[i-1].Metrics), lm.val(items[i].Metrics)
+				lineValues = append(lineValues,
+					fmt.Sprintf("%d", to),
+					percentLabel(stats.CalculatePercentChange(float64(from), float64(to))),
+				)
+			}
+			if err := writeMetric(lm.key, lineValues); err != nil {
+				return err
+			}
+		}
+	}
 
 	w.Flush()
 	return w.Error()
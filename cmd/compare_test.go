@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"git-visible/internal/config"
+	"git-visible/internal/stats"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -21,8 +22,11 @@ import (
 )
 
 type commitSpec struct {
-	Email string
-	When  time.Time
+	Name    string // 作者姓名，默认 "Test"
+	Email   string
+	When    time.Time
+	File    string // 相对仓库根目录的文件路径，默认 file.txt
+	Message string // 提交信息，默认 "test commit"
 }
 
 func TestCompare_TwoEmails_TableColumns(t *testing.T) {
@@ -220,6 +224,38 @@ func TestCompare_JSONOutput_Structure(t *testing.T) {
 	assert.Equal(t, "b@example.com", got.Items[1].Label)
 }
 
+func TestCompare_LinesJSON(t *testing.T) {
+	home := withTempHome(t)
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	specs := []commitSpec{
+		{Email: "a@example.com", When: timeNowLocal().AddDate(0, 0, -3)},
+		{Email: "b@example.com", When: timeNowLocal().AddDate(0, 0, -2)},
+	}
+	createRepoWithCommitSpecs(t, repoPath, specs)
+	writeReposFile(t, home, []string{repoPath})
+
+	resetCompareFlags()
+	compareEmails = []string{"a@example.com", "b@example.com"}
+	compareFormat = "json"
+	compareLines = true
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+
+	require.NoError(t, runCompare(c, nil))
+
+	var got compareJSONOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output=%s", out.String())
+	require.Len(t, got.Items, 2)
+
+	require.NotNil(t, got.Items[0].Lines)
+	assert.Equal(t, stats.LineTotals{Additions: 1, Deletions: 0, Files: 1}, *got.Items[0].Lines)
+	require.NotNil(t, got.Items[1].Lines)
+	assert.Equal(t, stats.LineTotals{Additions: 1, Deletions: 1, Files: 1}, *got.Items[1].Lines)
+}
+
 func TestCompare_AllRepositoriesFail_ReturnsError(t *testing.T) {
 	home := withTempHome(t)
 	writeReposFile(t, home, []string{filepath.Join(home, "missing-repo")})
@@ -313,12 +349,63 @@ func TestCompare_EmailFlagsWithWhitespace_AreTrimmed(t *testing.T) {
 	assert.Equal(t, 1, got.Items[1].TotalCommits)
 }
 
+func TestCompare_CoAuthorsCreditsPairedCommits(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{
+		Months:  config.DefaultMonths,
+		Aliases: []config.Alias{{Name: "Bob", Emails: []string{"bob@company.com", "bob@gmail.com"}}},
+	})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := timeNowLocal().AddDate(0, 0, -3).Add(12 * time.Hour)
+	createRepoWithCommitSpecs(t, repoPath, []commitSpec{
+		{Email: "alice@company.com", When: base},
+		{
+			Email:   "alice@company.com",
+			When:    base.Add(time.Minute),
+			Message: "pair on parser\n\nCo-authored-by: Bob <BOB@gmail.com>\nco-authored-by: Alice <alice@company.com>",
+		},
+	})
+	writeReposFile(t, home, []string{repoPath})
+
+	run := func(coAuthors bool) compareJSONOutput {
+		resetCompareFlags()
+		compareEmails = []string{"alice@company.com", "bob@company.com"}
+		compareFormat = "json"
+		compareCoAuthors = coAuthors
+
+		var out, errBuf bytes.Buffer
+		c := &cobra.Command{}
+		c.SetOut(&out)
+		c.SetErr(&errBuf)
+		require.NoError(t, runCompare(c, nil), "stderr=%s", errBuf.String())
+
+		var got compareJSONOutput
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output=%s", out.String())
+		require.Len(t, got.Items, 2)
+		return got
+	}
+
+	got := run(false)
+	assert.Equal(t, 2, got.Items[0].TotalCommits)
+	assert.Equal(t, 0, got.Items[1].TotalCommits)
+
+	// 共同作者经别名规范化后计入 bob 的桶；作者自身重复出现在 trailer 中不重复计数
+	got = run(true)
+	assert.Equal(t, 2, got.Items[0].TotalCommits)
+	assert.Equal(t, 1, got.Items[1].TotalCommits)
+}
+
 func resetCompareFlags() {
 	compareEmails = nil
 	comparePeriods = nil
 	compareYears = nil
 	compareFormat = "table"
 	compareNoCache = false
+	compareLines = false
+	compareNoMerges = false
+	compareMergesOnly = false
+	compareCoAuthors = false
 }
 
 func addCompareFlagsForTest(cmd *cobra.Command) {
@@ -327,6 +414,9 @@ func addCompareFlagsForTest(cmd *cobra.Command) {
 	cmd.Flags().IntSliceVar(&compareYears, "year", nil, "Years to compare (repeatable; shortcut for --period YYYY)")
 	cmd.Flags().StringVarP(&compareFormat, "format", "f", "table", "Output format: table/json/csv")
 	cmd.Flags().BoolVar(&compareNoCache, "no-cache", false, "Disable cache, force full scan")
+	cmd.Flags().BoolVar(&compareLines, "lines", false, "Also compare added/deleted lines and files changed (slower)")
+	addMergeFlags(cmd, &compareNoMerges, &compareMergesOnly)
+	addCoAuthorsFlag(cmd, &compareCoAuthors)
 
 	cmd.MarkFlagsMutuallyExclusive("email", "period")
 	cmd.MarkFlagsMutuallyExclusive("email", "year")
@@ -344,20 +434,33 @@ func createRepoWithCommitSpecs(tb testing.TB, path string, specs []commitSpec) {
 	require.NoError(tb, err)
 
 	for i, spec := range specs {
-		fileName := filepath.Join(path, "file.txt")
+		file := spec.File
+		if file == "" {
+			file = "file.txt"
+		}
+		fileName := filepath.Join(path, filepath.FromSlash(file))
+		require.NoError(tb, os.MkdirAll(filepath.Dir(fileName), 0o755))
 		content := []byte(fmt.Sprintf("commit %d\n", i))
 		require.NoError(tb, os.WriteFile(fileName, content, 0o644))
 
-		_, err := wt.Add("file.txt")
+		_, err := wt.Add(file)
 		require.NoError(tb, err)
 
+		name := spec.Name
+		if name == "" {
+			name = "Test"
+		}
 		sig := &object.Signature{
-			Name:  "Test",
+			Name:  name,
 			Email: spec.Email,
 			When:  spec.When,
 		}
 
-		_, err = wt.Commit("test commit", &git.CommitOptions{
+		message := spec.Message
+		if message == "" {
+			message = "test commit"
+		}
+		_, err = wt.Commit(message, &git.CommitOptions{
 			Author:    sig,
 			Committer: sig,
 		})
@@ -410,7 +513,12 @@ func BenchmarkCollectCompare_SinglePass(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		items, err, allFailed := collectCompareByEmail(repos, emails, start, end, nil, false)
+		items, err, allFailed := collectCompareByEmail(stats.CollectOptions{
+			Repos:  repos,
+			Emails: emails,
+			Since:  start,
+			Until:  end,
+		})
 		if err != nil {
 			b.Fatalf("collect compare failed: %v", err)
 		}
@@ -1,17 +1,21 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"git-visible/internal/config"
 	"git-visible/internal/repo"
+	"git-visible/internal/stats"
 
 	"github.com/spf13/cobra"
 )
 
 // doctorCmd 实现 doctor 子命令，一站式诊断环境和配置问题。
-// 依次执行 5 项检查：配置合法性、仓库路径有效性、分支可达性、读权限、性能预警。
+// 依次执行 7 项检查：配置合法性、仓库路径有效性、身份识别、分支可达性、读权限、mailmap、性能预警。
 // 有错误时返回非零退出码，仅警告时返回 0。
 // 用法: git-visible doctor
 var doctorCmd = &cobra.Command{
@@ -26,12 +30,14 @@ func init() {
 	rootCmd.AddCommand(doctorCmd)
 }
 
-// runDoctor 是 doctor 命令的核心逻辑，按顺序执行 5 项诊断检查：
+// runDoctor 是 doctor 命令的核心逻辑，按顺序执行 7 项诊断检查：
 //  1. 配置合法性（months、email 格式）
 //  2. 仓库路径有效性（路径存在且包含 .git）
-//  3. 分支可达性（HEAD 和指定分支有提交且可解析）
-//  4. 读权限（.git/HEAD 可读）
-//  5. 性能预警（仓库数量 >50 或 .git 体积 >1GB）
+//  3. 身份识别（配置的 email，或从 git 配置识别出的邮箱及其来源）
+//  4. 分支可达性（HEAD 和指定分支有提交且可解析）
+//  5. 读权限（.git/HEAD 可读）
+//  6. mailmap（全局 mailmap_file 与各仓库 .mailmap 可读且可解析）
+//  7. 性能预警（仓库数量 >50 或 .git 体积 >1GB）
 //
 // 输出使用 ✅/⚠️/❌ 分类显示，有错误时返回 error（exit 非零）。
 func runDoctor(cmd *cobra.Command, _ []string) error {
@@ -48,6 +54,11 @@ func runDoctor(cmd *cobra.Command, _ []string) error {
 		fmt.Fprintf(out, "❌ Config: %v\n", cfgErr)
 	} else {
 		issues := config.ValidateConfig(cfg)
+		// 主题名称需结合内置主题校验，档位、周起始日与时区需使用运行时的解析函数，config 包无法完成
+		if issue := checkThemeName(cfg); issue != "" {
+			issues = append(issues, issue)
+		}
+		issues = append(issues, checkParsedSettings(cfg)...)
 		if len(issues) == 0 {
 			fmt.Fprintln(out, "✅ Config: OK")
 		} else {
@@ -75,7 +86,12 @@ func runDoctor(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
-	// 3. 分支可达性检查（需要有效仓库）
+	// 3. 身份识别：email 为 auto 却识别不到邮箱时，统计命令会直接报错，因此计为错误
+	if !checkIdentity(out, cfg, validRepos) {
+		hasError = true
+	}
+
+	// 4. 分支可达性检查（需要有效仓库）
 	if len(validRepos) == 0 {
 		fmt.Fprintln(out, "⚠️  Branch reachability: skipped (no valid repositories)")
 	} else {
@@ -94,7 +110,7 @@ func runDoctor(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
-	// 4. 读权限检查（需要有效仓库）
+	// 5. 读权限检查（需要有效仓库）
 	if len(validRepos) == 0 {
 		fmt.Fprintln(out, "⚠️  Permissions: skipped (no valid repositories)")
 	} else {
@@ -113,7 +129,16 @@ func runDoctor(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
-	// 5. 性能预警（仓库数量、.git 体积）
+	// 6. mailmap 检查（解析失败的行在统计时会被忽略，仅警告）
+	mailmapIssues := checkMailmaps(cfg, validRepos)
+	if len(mailmapIssues) == 0 {
+		fmt.Fprintln(out, "✅ Mailmap: OK")
+	} else {
+		fmt.Fprintf(out, "⚠️  Mailmap: %d issue(s)\n", len(mailmapIssues))
+		printLines(out, mailmapIssues)
+	}
+
+	// 7. 性能预警（仓库数量、.git 体积）
 	performanceWarnings := repo.CheckPerformance(validRepos)
 	if len(performanceWarnings) == 0 {
 		fmt.Fprintln(out, "✅ Performance: OK")
@@ -128,9 +153,97 @@ func runDoctor(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+// checkIdentity 输出统计使用的身份：显式配置的 email，或 email 为 auto / 未配置时从 git 配置识别出的邮箱及其来源。
+// email 为 auto 但识别不到任何邮箱时返回 false（与 prepareRun 的报错一致）。
+func checkIdentity(out io.Writer, cfg *config.Config, repos []string) bool {
+	email := ""
+	if cfg != nil {
+		email = strings.TrimSpace(cfg.Email)
+	}
+	if email != "" && !cfg.AutoEmail() {
+		fmt.Fprintf(out, "✅ Identity: %s\n", email)
+		return true
+	}
+
+	detected := detectEmailsFn(repos)
+	lines := make([]string, 0, len(detected)+1)
+	for _, d := range detected {
+		lines = append(lines, fmt.Sprintf("%s (from %s)", d.Email, strings.Join(d.Sources, ", ")))
+	}
+	switch {
+	case email != "" && len(detected) > 0:
+		fmt.Fprintf(out, "✅ Identity: auto, %d email(s) detected\n", len(detected))
+	case email != "":
+		fmt.Fprintln(out, "❌ Identity: auto, but no user.email found in git config")
+		printLines(out, []string{"set one with: git config --global user.email you@example.com"})
+		return false
+	default:
+		fmt.Fprintln(out, "⚠️  Identity: no email configured, counting all authors")
+		if len(detected) > 0 {
+			lines = append(lines, "run `git-visible set email auto` to count only these emails")
+		}
+	}
+	printLines(out, lines)
+	return true
+}
+
+// checkMailmaps 检查全局 mailmap 文件（已配置时必须存在）与各仓库的 .mailmap（存在时）能否解析。
+func checkMailmaps(cfg *config.Config, repos []string) []string {
+	issues := make([]string, 0)
+	if path := cfg.MailmapPath(); path != "" {
+		if _, err := stats.LoadMailmap(path); err != nil {
+			issues = append(issues, fmt.Sprintf("mailmap_file: %v", err))
+		}
+	}
+	for _, repoPath := range repos {
+		path := stats.RepoMailmapPath(repoPath)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if _, err := stats.LoadMailmap(path); err != nil {
+			issues = append(issues, fmt.Sprintf("%s: %v", repoPath, err))
+		}
+	}
+	return issues
+}
+
 // printLines 将字符串列表以缩进列表形式输出，每行前加 "   - " 前缀。
 func printLines(out io.Writer, lines []string) {
 	for _, line := range lines {
 		fmt.Fprintf(out, "   - %s\n", line)
 	}
 }
+
+// checkParsedSettings 使用各命令运行时的解析函数校验 thresholds、week_start 与 timezone，保证两处规则一致。
+func checkParsedSettings(cfg *config.Config) []string {
+	var issues []string
+	if _, err := stats.ParseThresholds(cfg.Thresholds); err != nil {
+		issues = append(issues, fmt.Sprintf("thresholds must be fixed, quantile, or three increasing numbers like 1,5,10, got %q", cfg.Thresholds))
+	}
+	if strings.TrimSpace(cfg.WeekStart) != "" {
+		if _, err := stats.ParseWeekStart(cfg.WeekStart); err != nil {
+			issues = append(issues, fmt.Sprintf("week_start must be a weekday like monday or sunday, got %q", cfg.WeekStart))
+		}
+	}
+	if _, err := stats.ParseTimezone(cfg.Timezone); err != nil {
+		issues = append(issues, fmt.Sprintf("timezone must be local, author, or an IANA name like UTC, got %q", cfg.Timezone))
+	}
+	return issues
+}
+
+// checkThemeName 检查配置的 theme 是否为内置主题或 themes 中定义的自定义主题，合法时返回空字符串。
+func checkThemeName(cfg *config.Config) string {
+	name := strings.TrimSpace(cfg.Theme)
+	if name == "" {
+		return ""
+	}
+	if _, ok := stats.BuiltinTheme(name); ok {
+		return ""
+	}
+	for custom := range cfg.Themes {
+		if strings.EqualFold(custom, name) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("theme %q is neither a built-in theme (%s) nor defined in themes", name, strings.Join(stats.BuiltinThemeNames(), ", "))
+}
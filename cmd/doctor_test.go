@@ -3,10 +3,13 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"git-visible/internal/config"
+
 	"github.com/go-git/go-git/v5"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -36,9 +39,89 @@ func TestDoctor_NormalRepository_AllOK(t *testing.T) {
 	assert.Contains(t, s, "✅ Repositories: 1/1 valid")
 	assert.Contains(t, s, "✅ Branch reachability: OK")
 	assert.Contains(t, s, "✅ Permissions: OK")
+	assert.Contains(t, s, "✅ Mailmap: OK")
 	assert.Contains(t, s, "✅ Performance: OK")
 }
 
+func TestDoctor_Identity(t *testing.T) {
+	home := withGitConfig(t, "[user]\n\temail = me@example.com\n")
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 1, "me@example.com", time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local))
+	writeReposFile(t, home, []string{repoPath})
+	gitconfig := filepath.Join(home, ".gitconfig")
+
+	runDoctorOutput := func() string {
+		var out bytes.Buffer
+		c := &cobra.Command{}
+		c.SetOut(&out)
+		require.NoError(t, runDoctor(c, nil))
+		return out.String()
+	}
+
+	setTestConfig(t, config.Config{Months: config.DefaultMonths})
+	s := runDoctorOutput()
+	assert.Contains(t, s, "⚠️  Identity: no email configured, counting all authors")
+	assert.Contains(t, s, "   - me@example.com (from "+gitconfig+")")
+	assert.Contains(t, s, "git-visible set email auto")
+
+	setTestConfig(t, config.Config{Email: "auto", Months: config.DefaultMonths})
+	s = runDoctorOutput()
+	assert.Contains(t, s, "✅ Identity: auto, 1 email(s) detected")
+	assert.Contains(t, s, "   - me@example.com (from "+gitconfig+")")
+
+	setTestConfig(t, config.Config{Email: "me@work.com", Months: config.DefaultMonths})
+	assert.Contains(t, runDoctorOutput(), "✅ Identity: me@work.com\n")
+
+	// auto 但识别不到邮箱：统计命令会报错，doctor 同样以非零退出
+	require.NoError(t, os.WriteFile(gitconfig, nil, 0o644))
+	setTestConfig(t, config.Config{Email: "auto", Months: config.DefaultMonths})
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	require.Error(t, runDoctor(c, nil))
+	assert.Contains(t, out.String(), "❌ Identity: auto, but no user.email found in git config")
+}
+
+func TestCheckParsedSettings(t *testing.T) {
+	t.Run("valid settings pass", func(t *testing.T) {
+		for _, cfg := range []config.Config{
+			{},
+			{Thresholds: "quantile", WeekStart: "Mon", Timezone: "author"},
+			{Thresholds: "1, 10, 20", WeekStart: "sunday", Timezone: "America/New_York"},
+		} {
+			assert.Empty(t, checkParsedSettings(&cfg), "%+v", cfg)
+		}
+	})
+
+	t.Run("invalid settings are reported", func(t *testing.T) {
+		issues := checkParsedSettings(&config.Config{Thresholds: "5,5,10", WeekStart: "weekend", Timezone: "Moon/Base"})
+		require.Len(t, issues, 3)
+		assert.Contains(t, issues[0], "thresholds")
+		assert.Contains(t, issues[1], "week_start")
+		assert.Contains(t, issues[2], "timezone")
+	})
+}
+
+func TestDoctor_InvalidMailmap_WarnOnly(t *testing.T) {
+	home := withTempHome(t)
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 1, "test@example.com", time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local))
+	writeReposFile(t, home, []string{repoPath})
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, ".mailmap"), []byte("Alice <alice@example.com>\nbroken line\n"), 0o644))
+	setTestConfig(t, config.Config{Months: config.DefaultMonths, MailmapFile: filepath.Join(home, "missing.mailmap")})
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+
+	require.NoError(t, runDoctor(c, nil))
+	s := out.String()
+	assert.Contains(t, s, "⚠️  Mailmap: 2 issue(s)")
+	assert.Contains(t, s, "mailmap_file:")
+	assert.Contains(t, s, "line 2")
+}
+
 func TestDoctor_BrokenRepository_ReturnsError(t *testing.T) {
 	home := withTempHome(t)
 
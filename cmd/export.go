@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// 命令行标志变量
+var (
+	exportEmails     []string // 要过滤的邮箱列表
+	exportMonths     int      // 统计的月份数
+	exportSince      string   // 起始日期
+	exportUntil      string   // 结束日期
+	exportBranch     string   // 指定分支名
+	exportAllBranch  bool     // 是否遍历所有本地分支（去重）
+	exportNoMerges   bool     // 是否跳过合并提交
+	exportMergesOnly bool     // 是否只导出合并提交
+	exportPaths      []string // 路径过滤规则（支持 glob 与 ! 排除）
+	exportCoAuthors  bool     // 是否按 Co-authored-by 共同作者匹配并输出
+	exportOutput     string   // 输出文件路径，空表示 stdout
+	exportTZ         string   // --since/--until 按天划分使用的时区，空表示使用配置或本地时区
+)
+
+// exportCmd 实现 export 子命令，以 NDJSON 逐行输出命中过滤条件的提交。
+var exportCmd = newExportCmd()
+
+// newExportCmd 构建 export 命令，便于在测试中复用。
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export matching commits as NDJSON",
+		Long: `Stream one JSON object per matching commit (newline-delimited JSON).
+
+Each object holds the repository path, commit hash, author name/email (raw and
+normalized through .mailmap and aliases), author and committer time with their
+UTC offsets, parent count and the branch the commit was reached from.
+Filters are the same as "show": email, time range, branch, merges and paths.`,
+		Example: `  git-visible export > commits.ndjson
+  git-visible export --since 2025-01 -e me@example.com --output commits.ndjson
+  git-visible export --all-branches --no-merges --path services/api`,
+		Args: cobra.NoArgs,
+		RunE: runExport,
+	}
+
+	cmd.Flags().StringArrayVarP(&exportEmails, "email", "e", nil, "Email filter (repeatable)")
+	cmd.Flags().IntVarP(&exportMonths, "months", "m", 0, "Months to include (default: config value; ignored when --since/--until is set)")
+	cmd.Flags().StringVar(&exportSince, "since", "", "Start date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
+	cmd.Flags().StringVar(&exportUntil, "until", "", "End date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
+	cmd.Flags().StringVarP(&exportBranch, "branch", "b", "", "Branch to include (default: HEAD)")
+	cmd.Flags().BoolVar(&exportAllBranch, "all-branches", false, "Include all local branches (deduplicated by commit hash)")
+	cmd.MarkFlagsMutuallyExclusive("branch", "all-branches")
+	addMergeFlags(cmd, &exportNoMerges, &exportMergesOnly)
+	addPathFlag(cmd, &exportPaths)
+	addCoAuthorsFlag(cmd, &exportCoAuthors)
+	addTimezoneFlag(cmd, &exportTZ)
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to file instead of stdout")
+	return cmd
+}
+
+// init 注册 export 命令。
+func init() {
+	rootCmd.AddCommand(exportCmd)
+}
+
+// runExport 是 export 命令的核心逻辑：边遍历边写出，不在内存中累积提交。
+func runExport(cmd *cobra.Command, _ []string) error {
+	runCtx, err := prepareRun(exportEmails, exportMonths, exportSince, exportUntil, calendarFlags{timezone: exportTZ})
+	if err != nil {
+		if errors.Is(err, errNoRepositoriesAdded) {
+			fmt.Fprintln(cmd.ErrOrStderr(), "no repositories added")
+			return nil
+		}
+		return err
+	}
+
+	opts := runCtx.collectOptions(stats.BranchOption{
+		Branch:      strings.TrimSpace(exportBranch),
+		AllBranches: exportAllBranch,
+	}, false)
+	opts.Merges = mergeModeFromFlags(exportNoMerges, exportMergesOnly)
+	opts.Paths = exportPaths
+	opts.CoAuthors = opts.CoAuthors || exportCoAuthors
+
+	// 写入同目录下的临时文件，成功后再 rename，避免收集失败时截断已有的导出文件
+	out := cmd.OutOrStdout()
+	var pending *pendingOutput
+	if path := strings.TrimSpace(exportOutput); path != "" {
+		if pending, err = createPendingOutput(path); err != nil {
+			return err
+		}
+		defer pending.discard() // commit 成功后为空操作
+		out = pending.file
+	}
+
+	// 写出错误单独记录：ExportCommits 会因 emit 出错而终止，需要与仓库收集失败区分
+	var writeErr error
+	commits := 0
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	done, collectErr := stats.ExportCommits(opts, func(c stats.ExportCommit) error {
+		if err := enc.Encode(c); err != nil {
+			writeErr = err
+			return err
+		}
+		commits++
+		return nil
+	})
+	if writeErr == nil {
+		writeErr = w.Flush()
+	}
+	if writeErr != nil {
+		return fmt.Errorf("write export: %w", writeErr)
+	}
+	if collectErr != nil {
+		if len(done) == 0 {
+			return fmt.Errorf("all repositories failed to export: %w", collectErr)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: some repositories failed, export is partial:", collectErr)
+	}
+	if pending != nil {
+		if err := pending.commit(); err != nil {
+			return fmt.Errorf("write export: %w", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "exported %d commits to %s\n", commits, pending.path)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+	"git-visible/internal/stats"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport_NDJSONToOutputFile(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := timeNowLocal().AddDate(0, 0, -3).Add(12 * time.Hour)
+	createRepoWithCommitSpecs(t, repoPath, []commitSpec{
+		{Email: "me@example.com", When: base},
+		{Email: "other@example.com", When: base.Add(time.Minute)},
+		{Email: "me@example.com", When: base.Add(2 * time.Minute)},
+	})
+	writeReposFile(t, home, []string{repoPath})
+
+	outPath := filepath.Join(home, "commits.ndjson")
+	cmd := newExportCmd()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"-e", "me@example.com", "--output", outPath})
+	require.NoError(t, cmd.Execute(), "stderr=%s", stderr.String())
+
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "exported 2 commits")
+
+	// 新文件的权限与 os.Create 一致（受 umask 影响），不强制 0644
+	ref, err := os.Create(filepath.Join(home, "reference"))
+	require.NoError(t, err)
+	require.NoError(t, ref.Close())
+	refInfo, err := os.Stat(ref.Name())
+	require.NoError(t, err)
+	outInfo, err := os.Stat(outPath)
+	require.NoError(t, err)
+	assert.Equal(t, refInfo.Mode().Perm(), outInfo.Mode().Perm())
+
+	f, err := os.Open(outPath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []stats.ExportCommit
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec stats.ExportCommit
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec), "line=%s", scanner.Text())
+		records = append(records, rec)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, records, 2)
+	for _, rec := range records {
+		assert.Equal(t, repoPath, rec.Repo)
+		assert.Equal(t, "me@example.com", rec.AuthorEmail)
+		assert.Equal(t, "master", rec.Branch)
+		assert.Len(t, rec.Hash, 40)
+		_, err := time.Parse(time.RFC3339, rec.AuthorTime)
+		assert.NoError(t, err)
+	}
+}
+
+func TestExport_StdoutByDefault(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 3, "me@example.com", timeNowLocal().AddDate(0, 0, -1))
+	writeReposFile(t, home, []string{repoPath})
+
+	cmd := newExportCmd()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute(), "stderr=%s", stderr.String())
+
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+}
+
+func TestExport_FailedExportKeepsExistingOutput(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+	writeReposFile(t, home, []string{filepath.Join(home, "code", "missing")})
+
+	outPath := filepath.Join(home, "commits.ndjson")
+	require.NoError(t, os.WriteFile(outPath, []byte("previous\n"), 0o644))
+
+	cmd := newExportCmd()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--output", outPath})
+	require.ErrorContains(t, cmd.Execute(), "all repositories failed to export")
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Equal(t, "previous\n", string(data))
+	entries, err := os.ReadDir(home)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".tmp", "temporary output must be removed")
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// 命令行标志变量
+var (
+	logEmails     []string // 要过滤的邮箱列表
+	logDate       string   // 单日
+	logWeek       string   // 该日期所在的一周
+	logSince      string   // 起始日期
+	logUntil      string   // 结束日期
+	logBranch     string   // 指定分支名
+	logAllBranch  bool     // 是否遍历所有本地分支（去重）
+	logNoMerges   bool     // 是否跳过合并提交
+	logMergesOnly bool     // 是否只列出合并提交
+	logPaths      []string // 路径过滤规则（支持 glob 与 ! 排除）
+	logCoAuthors  bool     // 是否按 Co-authored-by 共同作者匹配
+	logFormat     string   // 输出格式：table/json
+	logWeekStart  string   // --week 的每周第一天，空表示使用配置或周日（与热力图一致）
+	logTZ         string   // 按天划分使用的时区，空表示使用配置或本地时区
+)
+
+// logCmd 实现 log 子命令，列出热力图某一天（或一段时间）背后的提交。
+var logCmd = newLogCmd()
+
+// newLogCmd 构建 log 命令，便于在测试中复用。
+func newLogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "List the commits behind a heatmap cell",
+		Long: `List matching commits across all registered repositories, newest first.
+Each line shows the repository, short hash, author time and subject.
+
+Select a single day with --date, the week containing a date with --week, or a
+range with --since/--until (default: today). Email, alias, mailmap, branch,
+merge and path filters are the same as "show", so the listed commits are
+exactly the ones counted in the heatmap cell.`,
+		Example: `  git-visible log --date 2025-03-14
+  git-visible log --week 2025-03-14 --week-start monday
+  git-visible log --since 2025-03-01 --until 2025-03-31 -f json`,
+		Args: cobra.NoArgs,
+		RunE: runLog,
+	}
+
+	cmd.Flags().StringArrayVarP(&logEmails, "email", "e", nil, "Email filter (repeatable)")
+	cmd.Flags().StringVar(&logDate, "date", "", "Single day to list (YYYY-MM-DD or relative like 1w)")
+	cmd.Flags().StringVar(&logWeek, "week", "", "List the week containing this date (aligned to --week-start)")
+	cmd.Flags().StringVar(&logSince, "since", "", "Start date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
+	cmd.Flags().StringVar(&logUntil, "until", "", "End date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
+	cmd.MarkFlagsMutuallyExclusive("date", "week", "since")
+	cmd.MarkFlagsMutuallyExclusive("date", "week", "until")
+	cmd.Flags().StringVarP(&logBranch, "branch", "b", "", "Branch to include (default: HEAD)")
+	cmd.Flags().BoolVar(&logAllBranch, "all-branches", false, "Include all local branches (deduplicated by commit hash)")
+	cmd.MarkFlagsMutuallyExclusive("branch", "all-branches")
+	cmd.Flags().StringVarP(&logFormat, "format", "f", "table", "Output format: table/json")
+	addMergeFlags(cmd, &logNoMerges, &logMergesOnly)
+	addPathFlag(cmd, &logPaths)
+	addCoAuthorsFlag(cmd, &logCoAuthors)
+	addWeekStartFlag(cmd, &logWeekStart)
+	addTimezoneFlag(cmd, &logTZ)
+	return cmd
+}
+
+// init 注册 log 命令。
+func init() {
+	rootCmd.AddCommand(logCmd)
+}
+
+// runLog 是 log 命令的核心逻辑：解析日期范围，收集提交并按时间从新到旧输出。
+func runLog(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	format := strings.ToLower(strings.TrimSpace(logFormat))
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: table, json)", logFormat)
+	}
+
+	since, until := strings.TrimSpace(logSince), strings.TrimSpace(logUntil)
+	switch {
+	case strings.TrimSpace(logDate) != "":
+		since, until = logDate, logDate
+	case strings.TrimSpace(logWeek) != "":
+		since, until = logWeek, logWeek
+	}
+
+	runCtx, err := prepareRun(logEmails, 0, since, until, calendarFlags{timezone: logTZ})
+	if err != nil {
+		if errors.Is(err, errNoRepositoriesAdded) {
+			fmt.Fprintln(out, "no repositories added")
+			return nil
+		}
+		return err
+	}
+
+	switch {
+	case strings.TrimSpace(logWeek) != "":
+		// 与热力图的列一致：未设置 week_start 时从周日开始
+		weekStart, err := resolveWeekStart(runCtx.Config, logWeekStart, time.Sunday)
+		if err != nil {
+			return err
+		}
+		runCtx.Since, runCtx.Until = stats.WeekRange(runCtx.Since, weekStart)
+	case since == "" && until == "":
+		// 未指定任何日期时只列出今天
+		runCtx.Since = runCtx.Until
+	}
+
+	opts := runCtx.collectOptions(stats.BranchOption{
+		Branch:      strings.TrimSpace(logBranch),
+		AllBranches: logAllBranch,
+	}, false)
+	opts.Merges = mergeModeFromFlags(logNoMerges, logMergesOnly)
+	opts.Paths = logPaths
+	opts.CoAuthors = opts.CoAuthors || logCoAuthors
+
+	commits, done, collectErr := stats.CollectCommits(opts)
+	if collectErr != nil {
+		if len(done) == 0 {
+			return fmt.Errorf("all repositories failed to collect commits: %w", collectErr)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: some repositories failed, showing partial results:", collectErr)
+	}
+
+	if format == "json" {
+		return writeLogJSON(out, runCtx.Since, runCtx.Until, commits)
+	}
+	writeLogTable(out, runCtx.Since, runCtx.Until, commits)
+	return nil
+}
+
+// logRangeLabel 返回日期范围的显示标签，单日时只显示一个日期。
+func logRangeLabel(since, until time.Time) string {
+	if since.Equal(until) {
+		return since.Format("2006-01-02")
+	}
+	return fmt.Sprintf("%s to %s", since.Format("2006-01-02"), until.Format("2006-01-02"))
+}
+
+// writeLogTable 以对齐的文本行输出提交：仓库、短哈希、时间、标题；单日范围只显示时刻。
+func writeLogTable(out io.Writer, since, until time.Time, commits []stats.LogCommit) {
+	if len(commits) == 0 {
+		fmt.Fprintf(out, "no commits found (%s)\n", logRangeLabel(since, until))
+		return
+	}
+
+	timeLayout := "2006-01-02 15:04"
+	if since.Equal(until) {
+		timeLayout = "15:04"
+	}
+
+	repoWidth := 0
+	repos := make(map[string]struct{})
+	for _, c := range commits {
+		repoWidth = max(repoWidth, len(displayRepoPath(c.Repo)))
+		repos[c.Repo] = struct{}{}
+	}
+
+	fmt.Fprintf(out, "%s: %d %s in %d %s\n", logRangeLabel(since, until),
+		len(commits), stats.Pluralize(len(commits), "commit", "commits"),
+		len(repos), stats.Pluralize(len(repos), "repository", "repositories"))
+	for _, c := range commits {
+		fmt.Fprintf(out, "%-*s  %s  %s  %s\n", repoWidth, displayRepoPath(c.Repo), c.ShortHash(), c.Time.Format(timeLayout), c.Subject)
+	}
+}
+
+// logJSONOutput 是 log 命令 JSON 输出的顶层结构。
+type logJSONOutput struct {
+	Since   string            `json:"since"`
+	Until   string            `json:"until"`
+	Total   int               `json:"total"`
+	Commits []stats.LogCommit `json:"commits"`
+}
+
+// writeLogJSON 以 JSON 格式输出提交列表。
+func writeLogJSON(out io.Writer, since, until time.Time, commits []stats.LogCommit) error {
+	if commits == nil {
+		commits = []stats.LogCommit{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(logJSONOutput{
+		Since:   since.Format("2006-01-02"),
+		Until:   until.Format("2006-01-02"),
+		Total:   len(commits),
+		Commits: commits,
+	})
+}
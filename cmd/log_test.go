@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_DateListsCommitsAcrossRepos(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "me@example.com", Months: config.DefaultMonths})
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	api := filepath.Join(home, "code", "api")
+	createRepoWithCommitSpecs(t, api, []commitSpec{
+		{Email: "me@example.com", When: day.Add(9 * time.Hour), Message: "Fix login"},
+		{Email: "other@example.com", When: day.Add(10 * time.Hour), Message: "Not mine"},
+		{Email: "me@example.com", When: day.AddDate(0, 0, 1).Add(9 * time.Hour), Message: "Next day"},
+	})
+	web := filepath.Join(home, "code", "web")
+	createRepoWithCommitSpecs(t, web, []commitSpec{
+		{Email: "me@example.com", When: day.Add(14*time.Hour + 5*time.Minute), Message: "Add button\n\nbody"},
+	})
+	writeReposFile(t, home, []string{api, web})
+
+	out, err := executeLogCommand(t, "--date", "2025-03-14")
+	require.NoError(t, err, out)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3, out)
+	assert.Equal(t, "2025-03-14: 2 commits in 2 repositories", lines[0])
+	assert.Regexp(t, `^~/code/web  [0-9a-f]{7}  14:05  Add button$`, lines[1])
+	assert.Regexp(t, `^~/code/api  [0-9a-f]{7}  09:00  Fix login$`, lines[2])
+}
+
+func TestLog_WeekJSON(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	friday := time.Date(2025, 3, 14, 12, 0, 0, 0, time.Local)
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommitSpecs(t, repoPath, []commitSpec{
+		{Email: "a@example.com", When: friday.AddDate(0, 0, -5), Message: "Sunday before"},
+		{Email: "a@example.com", When: friday.AddDate(0, 0, -4), Message: "Monday"},
+		{Email: "b@example.com", When: friday.AddDate(0, 0, 2), Message: "Sunday"},
+		{Email: "a@example.com", When: friday.AddDate(0, 0, 3), Message: "Next Monday"},
+	})
+	writeReposFile(t, home, []string{repoPath})
+
+	out, err := executeLogCommand(t, "--week", "2025-03-14", "-f", "json")
+	require.NoError(t, err, out)
+
+	var got logJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &got), out)
+	assert.Equal(t, "2025-03-09", got.Since, "weeks default to the heatmap's Sunday start")
+	assert.Equal(t, "2025-03-15", got.Until)
+	require.Equal(t, 2, got.Total)
+	assert.Equal(t, "Monday", got.Commits[0].Subject)
+	assert.Equal(t, "Sunday before", got.Commits[1].Subject)
+
+	out, err = executeLogCommand(t, "--week", "2025-03-14", "--week-start", "monday", "-f", "json")
+	require.NoError(t, err, out)
+	got = logJSONOutput{}
+	require.NoError(t, json.Unmarshal([]byte(out), &got), out)
+	assert.Equal(t, "2025-03-10", got.Since)
+	assert.Equal(t, "2025-03-16", got.Until)
+	require.Equal(t, 2, got.Total)
+	assert.Equal(t, "Sunday", got.Commits[0].Subject)
+	assert.Equal(t, "b@example.com", got.Commits[0].Author)
+	assert.Equal(t, "Monday", got.Commits[1].Subject)
+}
+
+func TestLog_NoCommits(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 1, "me@example.com", time.Date(2025, 3, 14, 12, 0, 0, 0, time.Local))
+	writeReposFile(t, home, []string{repoPath})
+
+	out, err := executeLogCommand(t, "--since", "2025-04-01", "--until", "2025-04-30")
+	require.NoError(t, err, out)
+	assert.Equal(t, "no commits found (2025-04-01 to 2025-04-30)\n", out)
+}
+
+func TestLog_DateAndSinceAreExclusive(t *testing.T) {
+	withTempHome(t)
+
+	_, err := executeLogCommand(t, "--date", "2025-03-14", "--since", "2025-03-01")
+	require.Error(t, err)
+}
+
+func executeLogCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := newLogCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// 命令行标志变量
+var (
+	reportHTML       string   // HTML 报告输出路径
+	reportTitle      string   // 报告标题
+	reportEmails     []string // 要过滤的邮箱列表（>=2 个时附带按邮箱对比）
+	reportPeriods    []string // 要对比的时间段列表
+	reportMonths     int      // 统计的月份数
+	reportSince      string   // 起始日期
+	reportUntil      string   // 结束日期
+	reportNumber     int      // 排行与迷你热力图包含的仓库数量
+	reportNoCache    bool     // 是否禁用缓存
+	reportNoMerges   bool     // 是否跳过合并提交
+	reportMergesOnly bool     // 是否只统计合并提交
+	reportPaths      []string // 路径过滤规则（支持 glob 与 ! 排除）
+	reportCoAuthors  bool     // 是否计入 Co-authored-by 共同作者
+	reportTheme      string   // 配色主题，空表示使用配置或默认主题
+	reportThresholds string   // 档位划分，空表示使用配置或默认值
+	reportWeekStart  string   // 每周第一天，空表示使用配置或周日
+	reportTZ         string   // 按天划分使用的时区，空表示使用配置或本地时区
+)
+
+// reportMiniCellSize 是每仓库迷你热力图的单元格边长（像素）。
+const reportMiniCellSize = 7
+
+// reportCmd 实现 report 子命令，生成可离线查看的单文件 HTML 报告。
+var reportCmd = newReportCmd()
+
+// newReportCmd 构建 report 命令，便于在测试中复用。
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate a self-contained HTML report",
+		Long: `Write a single offline HTML file containing the contribution heatmap, the
+summary metrics, the top repositories table and a mini heatmap per repository.
+With two or more --email values (or --period values) the matching compare
+tables are included as well. CSS and JS are inlined and the underlying data is
+embedded as JSON.`,
+		Example: `  git-visible report --html report.html
+  git-visible report --html sprint.html --since 2w -e alice@company.com -e bob@company.com
+  git-visible report --html yearly.html --period 2024 --period 2025`,
+		Args: cobra.NoArgs,
+		RunE: runReport,
+	}
+
+	cmd.Flags().StringVar(&reportHTML, "html", "", "Output HTML file (required)")
+	cmd.Flags().StringVar(&reportTitle, "title", "git-visible report", "Report title")
+	cmd.Flags().StringArrayVarP(&reportEmails, "email", "e", nil, "Email filter (repeatable; 2+ emails add a compare table)")
+	cmd.Flags().StringArrayVar(&reportPeriods, "period", nil, "Periods to compare (repeatable): YYYY, YYYY-HN, YYYY-QN, YYYY-MM")
+	cmd.Flags().IntVarP(&reportMonths, "months", "m", 0, "Months to include (default: config value; ignored when --since/--until is set)")
+	cmd.Flags().StringVar(&reportSince, "since", "", "Start date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
+	cmd.Flags().StringVar(&reportUntil, "until", "", "End date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
+	cmd.Flags().IntVarP(&reportNumber, "number", "n", 10, "Number of repositories in the ranking and mini heatmaps")
+	cmd.Flags().BoolVar(&reportNoCache, "no-cache", false, "Disable cache, force full scan")
+	addMergeFlags(cmd, &reportNoMerges, &reportMergesOnly)
+	addPathFlag(cmd, &reportPaths)
+	addCoAuthorsFlag(cmd, &reportCoAuthors)
+	addThemeFlags(cmd, &reportTheme, &reportThresholds)
+	addWeekStartFlag(cmd, &reportWeekStart)
+	addTimezoneFlag(cmd, &reportTZ)
+	_ = cmd.MarkFlagRequired("html")
+	return cmd
+}
+
+// init 注册 report 命令。
+func init() {
+	rootCmd.AddCommand(reportCmd)
+}
+
+// runReport 是 report 命令的核心逻辑：收集 show/top/compare 所需数据并写出 HTML。
+func runReport(cmd *cobra.Command, _ []string) error {
+	path := strings.TrimSpace(reportHTML)
+	if path == "" {
+		return fmt.Errorf("--html is required")
+	}
+	if reportNumber <= 0 {
+		return fmt.Errorf("number must be > 0, got %d", reportNumber)
+	}
+
+	runCtx, err := prepareRun(reportEmails, reportMonths, reportSince, reportUntil, calendarFlags{weekStart: reportWeekStart, timezone: reportTZ})
+	if err != nil {
+		if errors.Is(err, errNoRepositoriesAdded) {
+			fmt.Fprintln(cmd.ErrOrStderr(), "no repositories added")
+			return nil
+		}
+		return err
+	}
+
+	theme, err := resolveTheme(runCtx.Config, reportTheme)
+	if err != nil {
+		return err
+	}
+	thresholds, err := resolveThresholds(runCtx.Config, reportThresholds)
+	if err != nil {
+		return err
+	}
+
+	periods := make([]stats.Period, 0, len(reportPeriods))
+	for _, p := range cleanNonEmpty(reportPeriods) {
+		period, err := stats.ParsePeriod(p, runCtx.Timezone.Location)
+		if err != nil {
+			return err
+		}
+		periods = append(periods, period)
+	}
+	if len(periods) == 1 {
+		return fmt.Errorf("at least 2 periods are required to compare")
+	}
+
+	opts := runCtx.collectOptions(stats.BranchOption{}, !reportNoCache)
+	opts.Merges = mergeModeFromFlags(reportNoMerges, reportMergesOnly)
+	opts.Paths = reportPaths
+	opts.CoAuthors = opts.CoAuthors || reportCoAuthors
+
+	perRepo, collectErr := stats.CollectActivityPerRepo(opts)
+	if collectErr != nil {
+		if len(perRepo) == 0 {
+			return fmt.Errorf("all repositories failed to collect stats: %w", collectErr)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: some repositories failed, report is partial:", collectErr)
+	}
+
+	r := reportInput{
+		Title:      strings.TrimSpace(reportTitle),
+		Since:      runCtx.Since,
+		Until:      runCtx.Until,
+		Emails:     runCtx.Emails,
+		Daily:      mergePerRepoStats(perRepo),
+		PerRepo:    perRepo,
+		Ranking:    stats.RankRepositories(activityCountsPerRepo(perRepo), reportNumber),
+		Merges:     opts.Merges,
+		Colors:     imageColors(runCtx, theme),
+		Thresholds: thresholds,
+		WeekStart:  runCtx.WeekStart,
+	}
+
+	// 对比部分只在显式给出多个身份/时间段时生成，收集失败仅告警
+	if emails := cleanNonEmpty(reportEmails); len(emails) >= 2 {
+		emailOpts := opts
+		emailOpts.Emails = emails
+		items, err, allFailed := collectCompareByEmail(emailOpts)
+		if err != nil {
+			fmt.Fprintln(cmd.ErrOrStderr(), "warning: email comparison is partial:", err)
+		}
+		if !allFailed {
+			r.EmailCompare = items
+		}
+	}
+	if len(periods) >= 2 {
+		items, err, allFailed := collectCompareByPeriod(opts, periods)
+		if err != nil {
+			fmt.Fprintln(cmd.ErrOrStderr(), "warning: period comparison is partial:", err)
+		}
+		if !allFailed {
+			r.PeriodCompare = items
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create report: %w", err)
+	}
+	if err := writeHTMLReport(file, r); err != nil {
+		file.Close()
+		return fmt.Errorf("write report: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "report written to %s\n", path)
+	return nil
+}
+
+// reportInput 汇总生成报告所需的全部统计结果。
+type reportInput struct {
+	Title         string
+	Since         time.Time
+	Until         time.Time
+	Emails        []string
+	Daily         map[time.Time]stats.DayActivity
+	PerRepo       map[string]map[time.Time]stats.DayActivity
+	Ranking       stats.RepoRanking
+	Merges        stats.MergeMode
+	Colors        stats.SVGColors
+	Thresholds    stats.Thresholds
+	WeekStart     time.Weekday
+	EmailCompare  []emailCompareItem
+	PeriodCompare []periodCompareItem
+}
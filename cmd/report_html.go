@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"git-visible/internal/stats"
+)
+
+// reportNow 抽象报告生成时间，便于在测试中注入固定值。
+var reportNow = time.Now
+
+// reportJSON 是嵌入 HTML 报告的原始数据，字段与 show/top/compare 的 JSON 输出保持一致。
+type reportJSON struct {
+	Title       string              `json:"title"`
+	GeneratedAt string              `json:"generatedAt"`
+	Since       string              `json:"since"`
+	Until       string              `json:"until"`
+	Emails      []string            `json:"emails,omitempty"`
+	Days        []dayStat           `json:"days"`
+	Summary     summaryOut          `json:"summary"`
+	Top         stats.RepoRanking   `json:"top"`
+	Repos       []reportRepoJSON    `json:"repos"`
+	Compare     []compareJSONOutput `json:"compare,omitempty"`
+}
+
+// reportRepoJSON 是嵌入数据中单个仓库的逐日提交数。
+type reportRepoJSON struct {
+	Repository string    `json:"repository"`
+	Days       []dayStat `json:"days"`
+}
+
+// reportView 是 HTML 模板的渲染数据。
+type reportView struct {
+	Title       string
+	RangeLabel  string
+	GeneratedAt string
+	Emails      []string
+	Heatmap     template.HTML
+	Metrics     []reportMetric
+	Top         []stats.RepoRank
+	TopTotal    int
+	Repos       []reportRepoView
+	Compare     []reportTable
+	Data        reportJSON
+}
+
+// reportMetric 是摘要卡片中的一项指标。
+type reportMetric struct {
+	Label string
+	Value string
+}
+
+// reportRepoView 是单个仓库的迷你热力图。
+type reportRepoView struct {
+	Repository string
+	Commits    int
+	Heatmap    template.HTML
+}
+
+// reportTable 是对比矩阵（行=指标，列=对比项）。
+type reportTable struct {
+	Title   string
+	Headers []string
+	Rows    []reportTableRow
+}
+
+// reportTableRow 是对比矩阵中的一行。
+type reportTableRow struct {
+	Label string
+	Cells []string
+}
+
+// writeHTMLReport 将报告渲染为单个 HTML 文件（CSS/JS 内联，热力图为内联 SVG，原始数据以 JSON 嵌入）。
+func writeHTMLReport(out io.Writer, r reportInput) error {
+	counts := stats.ActivityCounts(r.Daily)
+	summary := buildSummaryOut(counts, r.Daily, false)
+
+	view := reportView{
+		Title:       r.Title,
+		RangeLabel:  fmt.Sprintf("%s to %s", r.Since.Format("2006-01-02"), r.Until.Format("2006-01-02")),
+		GeneratedAt: reportNow().Format("2006-01-02 15:04"),
+		Emails:      r.Emails,
+		Heatmap: template.HTML(stats.RenderHeatmapSVG(counts, stats.SVGOptions{
+			ShowLegend: true,
+			Since:      r.Since,
+			Until:      r.Until,
+			Colors:     r.Colors,
+			Thresholds: r.Thresholds,
+			WeekStart:  r.WeekStart,
+		})),
+		Metrics:  reportMetrics(summary, r.Merges),
+		TopTotal: r.Ranking.TotalCommits,
+	}
+	if view.Title == "" {
+		view.Title = "git-visible report"
+	}
+
+	// 报告可能被分享，仓库路径统一显示为 ~/... 形式
+	top := stats.RepoRanking{TotalCommits: r.Ranking.TotalCommits}
+	repos := make([]reportRepoJSON, 0, len(r.Ranking.Repositories))
+	for _, rank := range r.Ranking.Repositories {
+		daily := stats.ActivityCounts(r.PerRepo[rank.Repository])
+		display := displayRepoPath(rank.Repository)
+
+		rank.Repository = display
+		top.Repositories = append(top.Repositories, rank)
+		repos = append(repos, reportRepoJSON{Repository: display, Days: buildDayStats(daily, nil, false)})
+		view.Repos = append(view.Repos, reportRepoView{
+			Repository: display,
+			Commits:    rank.Commits,
+			Heatmap: template.HTML(stats.RenderHeatmapSVG(daily, stats.SVGOptions{
+				Since:      r.Since,
+				Until:      r.Until,
+				CellSize:   reportMiniCellSize,
+				Colors:     r.Colors,
+				Thresholds: r.Thresholds,
+				WeekStart:  r.WeekStart,
+			})),
+		})
+	}
+	view.Top = top.Repositories
+
+	var compare []compareJSONOutput
+	if len(r.EmailCompare) > 0 {
+		headers, labels, values := compareEmailMatrix(r.EmailCompare)
+		view.Compare = append(view.Compare, newReportTable("Compare by email", headers, labels, values))
+		compare = append(compare, compareEmailJSON(r.EmailCompare))
+	}
+	if len(r.PeriodCompare) > 0 {
+		headers, labels, values := comparePeriodMatrix(r.PeriodCompare)
+		view.Compare = append(view.Compare, newReportTable("Compare by period", headers, labels, values))
+		compare = append(compare, comparePeriodJSON(r.PeriodCompare))
+	}
+
+	view.Data = reportJSON{
+		Title:       view.Title,
+		GeneratedAt: reportNow().Format(time.RFC3339),
+		Since:       r.Since.Format("2006-01-02"),
+		Until:       r.Until.Format("2006-01-02"),
+		Emails:      r.Emails,
+		Days:        buildDayStats(counts, nil, false),
+		Summary:     summary,
+		Top:         top,
+		Repos:       repos,
+		Compare:     compare,
+	}
+
+	return reportTemplate.Execute(out, view)
+}
+
+// reportMetrics 将摘要转换为卡片展示的指标列表，排除合并提交时不显示合并提交数。
+func reportMetrics(s summaryOut, merges stats.MergeMode) []reportMetric {
+	longest := streakLabel(s.LongestStreak.Days)
+	if s.LongestStreak.Start != "" && s.LongestStreak.End != "" {
+		longest = fmt.Sprintf("%s (%s – %s)", longest, s.LongestStreak.Start, s.LongestStreak.End)
+	}
+	mostActive := "-"
+	if s.MostActiveWeekday.Commits > 0 {
+		mostActive = fmt.Sprintf("%s (%d commits)", s.MostActiveWeekday.Weekday, s.MostActiveWeekday.Commits)
+	}
+	peak := "-"
+	if s.PeakDay.Commits > 0 && s.PeakDay.Date != "" {
+		peak = fmt.Sprintf("%s (%d commits)", s.PeakDay.Date, s.PeakDay.Commits)
+	}
+
+	metrics := []reportMetric{
+		{"Total commits", fmt.Sprintf("%d", s.TotalCommits)},
+		{"Active days", fmt.Sprintf("%d", s.ActiveDays)},
+		{"Current streak", streakLabel(s.CurrentStreak)},
+		{"Longest streak", longest},
+		{"Most active day", mostActive},
+		{"Peak day", peak},
+	}
+	if merges != stats.MergesExclude {
+		metrics = append(metrics, reportMetric{"Merge commits", fmt.Sprintf("%d", s.MergeCommits)})
+	}
+	return metrics
+}
+
+// newReportTable 将对比矩阵转换为模板表格。
+func newReportTable(title string, headers, labels []string, values [][]string) reportTable {
+	t := reportTable{Title: title, Headers: headers}
+	for i, label := range labels {
+		t.Rows = append(t.Rows, reportTableRow{Label: label, Cells: values[i]})
+	}
+	return t
+}
+
+// reportTemplate 是报告页面模板；嵌入的 JSON 由 html/template 在 <script> 中安全转义。
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="git-visible">
+<title>{{.Title}}</title>
+<style>
+:root { --fg: #1f2328; --muted: #57606a; --border: #d0d7de; --card: #f6f8fa; }
+* { box-sizing: border-box; }
+body { margin: 0 auto; max-width: 1100px; padding: 24px; color: var(--fg); font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+header { display: flex; justify-content: space-between; align-items: baseline; flex-wrap: wrap; gap: 8px; }
+h1 { margin: 0; font-size: 24px; }
+h2 { margin: 32px 0 12px; font-size: 18px; border-bottom: 1px solid var(--border); padding-bottom: 6px; }
+.muted { color: var(--muted); }
+.heatmap { overflow-x: auto; }
+.metrics { display: grid; grid-template-columns: repeat(auto-fill, minmax(180px, 1fr)); gap: 12px; }
+.metric { background: var(--card); border: 1px solid var(--border); border-radius: 6px; padding: 10px 12px; }
+.metric .label { color: var(--muted); font-size: 12px; }
+.metric .value { font-size: 16px; font-weight: 600; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid var(--border); padding: 6px 10px; text-align: right; white-space: nowrap; }
+th:first-child, td:first-child { text-align: left; }
+table.sortable th { cursor: pointer; user-select: none; }
+table.sortable th[data-dir="asc"]::after { content: " ▲"; }
+table.sortable th[data-dir="desc"]::after { content: " ▼"; }
+tfoot td { font-weight: 600; }
+.repos { display: grid; grid-template-columns: repeat(auto-fill, minmax(320px, 1fr)); gap: 16px; }
+.repo { border: 1px solid var(--border); border-radius: 6px; padding: 10px; overflow-x: auto; }
+.repo h3 { margin: 0 0 6px; font-size: 13px; font-weight: 600; word-break: break-all; }
+button { font: inherit; padding: 4px 10px; border: 1px solid var(--border); border-radius: 6px; background: var(--card); cursor: pointer; }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.Title}}</h1>
+  <div class="muted">{{.RangeLabel}}{{if .Emails}} · {{range $i, $e := .Emails}}{{if $i}}, {{end}}{{$e}}{{end}}{{end}} · generated {{.GeneratedAt}} <button type="button" id="download-json">Download JSON</button></div>
+</header>
+
+<h2>Contributions</h2>
+<div class="heatmap">{{.Heatmap}}</div>
+
+<h2>Summary</h2>
+<div class="metrics">
+{{- range .Metrics}}
+  <div class="metric"><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div></div>
+{{- end}}
+</div>
+
+<h2>Top repositories</h2>
+{{if .Top -}}
+<table class="sortable" id="top-table">
+  <thead><tr><th>#</th><th>Repository</th><th>Commits</th><th>%</th></tr></thead>
+  <tbody>
+{{- range $i, $r := .Top}}
+    <tr><td>{{inc $i}}</td><td>{{$r.Repository}}</td><td>{{$r.Commits}}</td><td>{{printf "%.1f%%" $r.Percent}}</td></tr>
+{{- end}}
+  </tbody>
+  <tfoot><tr><td></td><td>Total</td><td>{{.TopTotal}}</td><td>100.0%</td></tr></tfoot>
+</table>
+{{- else -}}
+<p class="muted">No commits found.</p>
+{{- end}}
+{{range .Compare}}
+<h2>{{.Title}}</h2>
+<table>
+  <thead><tr><th></th>{{range .Headers}}<th>{{.}}</th>{{end}}</tr></thead>
+  <tbody>
+{{- range .Rows}}
+    <tr><td>{{.Label}}</td>{{range .Cells}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+  </tbody>
+</table>
+{{end}}
+{{- if .Repos}}
+<h2>Repositories</h2>
+<div class="repos">
+{{- range .Repos}}
+  <div class="repo"><h3>{{.Repository}} <span class="muted">· {{.Commits}} commits</span></h3>{{.Heatmap}}</div>
+{{- end}}
+</div>
+{{- end}}
+
+<script type="application/json" id="report-data">{{.Data}}</script>
+<script>
+(function () {
+  "use strict";
+  var data = JSON.parse(document.getElementById("report-data").textContent);
+
+  document.getElementById("download-json").addEventListener("click", function () {
+    var blob = new Blob([JSON.stringify(data, null, 2)], { type: "application/json" });
+    var a = document.createElement("a");
+    a.href = URL.createObjectURL(blob);
+    a.download = "git-visible-report.json";
+    a.click();
+    URL.revokeObjectURL(a.href);
+  });
+
+  // 点击表头排序（数字列按数值，其余按文本）
+  document.querySelectorAll("table.sortable").forEach(function (table) {
+    var headers = table.querySelectorAll("thead th");
+    headers.forEach(function (th, col) {
+      th.addEventListener("click", function () {
+        var dir = th.getAttribute("data-dir") === "desc" ? "asc" : "desc";
+        headers.forEach(function (h) { h.removeAttribute("data-dir"); });
+        th.setAttribute("data-dir", dir);
+        var body = table.tBodies[0];
+        var rows = Array.prototype.slice.call(body.rows);
+        rows.sort(function (a, b) {
+          var x = a.cells[col].textContent, y = b.cells[col].textContent;
+          var nx = parseFloat(x), ny = parseFloat(y);
+          var cmp = (!isNaN(nx) && !isNaN(ny)) ? nx - ny : x.localeCompare(y);
+          return dir === "asc" ? cmp : -cmp;
+        });
+        rows.forEach(function (row) { body.appendChild(row); });
+      });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
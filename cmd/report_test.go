@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_WritesSelfContainedHTML(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoA := filepath.Join(home, "code", "repo-a")
+	repoB := filepath.Join(home, "code", "repo-b")
+	base := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	createRepoWithCommitSpecs(t, repoA, []commitSpec{
+		{Email: "alice@example.com", When: base},
+		{Email: "alice@example.com", When: base.Add(time.Minute)},
+		{Email: "bob@example.com", When: base.AddDate(0, 0, 1)},
+	})
+	createRepoWithCommits(t, repoB, 1, "bob@example.com", base.AddDate(0, 0, 2))
+	writeReposFile(t, home, []string{repoA, repoB})
+
+	outPath := filepath.Join(home, "report.html")
+	cmd := newReportCmd()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{
+		"--html", outPath,
+		"--title", "Sprint </script> review",
+		"--since", "2025-06-01", "--until", "2025-06-30",
+		"-e", "alice@example.com", "-e", "bob@example.com",
+		"--period", "2025-05", "--period", "2025-06",
+	})
+	require.NoError(t, cmd.Execute(), "stderr=%s", stderr.String())
+	assert.Contains(t, stderr.String(), "report written to")
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	html := string(data)
+
+	assert.Contains(t, html, "<style>")
+	assert.NotContains(t, html, "<link ", "no external stylesheets")
+	assert.NotContains(t, html, "<script src", "no external scripts")
+	assert.Contains(t, html, "Sprint &lt;/script&gt; review")
+	assert.Contains(t, html, "<title>2025-06-02: 2 commits</title>", "main heatmap")
+	assert.Contains(t, html, "Total commits")
+	assert.Contains(t, html, `id="top-table"`)
+	assert.Contains(t, html, "~/code/repo-a")
+	assert.Contains(t, html, "Compare by email")
+	assert.Contains(t, html, "Compare by period")
+	assert.Equal(t, 3, strings.Count(html, "<svg "), "main heatmap plus one mini heatmap per repository")
+
+	// 嵌入的 JSON 可以直接解析
+	const open = `<script type="application/json" id="report-data">`
+	start := strings.Index(html, open)
+	require.NotEqual(t, -1, start)
+	rest := html[start+len(open):]
+	end := strings.Index(rest, "</script>")
+	require.NotEqual(t, -1, end)
+
+	var got reportJSON
+	require.NoError(t, json.Unmarshal([]byte(rest[:end]), &got), "json=%s", rest[:end])
+	assert.Equal(t, "Sprint </script> review", got.Title)
+	assert.Equal(t, "2025-06-01", got.Since)
+	assert.Equal(t, 4, got.Summary.TotalCommits)
+	require.Len(t, got.Top.Repositories, 2)
+	assert.Equal(t, "~/code/repo-a", got.Top.Repositories[0].Repository)
+	assert.Equal(t, 3, got.Top.Repositories[0].Commits)
+	require.Len(t, got.Repos, 2)
+	require.Len(t, got.Compare, 2)
+	assert.Equal(t, "email", got.Compare[0].Dimension)
+	assert.Equal(t, 2, got.Compare[0].Items[0].TotalCommits)
+	assert.Equal(t, "period", got.Compare[1].Dimension)
+	assert.Equal(t, 4, got.Compare[1].Items[1].TotalCommits)
+}
+
+func TestReport_RequiresHTMLFlag(t *testing.T) {
+	withTempHome(t)
+
+	cmd := newReportCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "html")
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"git-visible/internal/config"
+	"git-visible/internal/stats"
 
 	"github.com/spf13/cobra"
 )
@@ -14,7 +15,7 @@ import (
 // setCmd 实现 set 子命令，用于查看或修改默认配置。
 // 支持两种模式：
 // 1. git-visible set - 显示当前配置
-// 2. git-visible set <key> <value> - 设置配置项（支持 email 和 months）
+// 2. git-visible set <key> <value> - 设置配置项（支持 email、months、cache_max_mb、co_authors、mailmap_file、theme、thresholds、week_start 和 timezone）
 var setCmd = newSetCmd()
 
 // newSetCmd 构建 set 命令，便于在测试中复用。
@@ -22,14 +23,24 @@ func newSetCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "set [key] [value]",
 		Short: "Set or show default configuration",
-		Long: `View or modify default configuration (email, months, aliases).
+		Long: `View or modify default configuration (email, months, cache_max_mb, co_authors, mailmap_file, theme, thresholds, week_start, timezone, aliases).
 
 Without arguments, displays the current configuration.
 With key/value, sets the specified option.
+"set email auto" detects your emails from git config (user.email in the
+global, system and per-repository config, including includeIf overrides).
 Use "set alias" subcommands to manage email alias groups.`,
 		Example: `  git-visible set
   git-visible set email your@email.com
+  git-visible set email auto
   git-visible set months 12
+  git-visible set cache_max_mb 200
+  git-visible set co_authors true
+  git-visible set mailmap_file ~/.mailmap
+  git-visible set theme colorblind-safe
+  git-visible set thresholds quantile
+  git-visible set week_start monday
+  git-visible set timezone UTC
   git-visible set alias add Alice alice@company.com alice@gmail.com
   git-visible set alias list`,
 		Args: validateSetArgs,
@@ -47,12 +58,12 @@ func validateSetArgs(cmd *cobra.Command, args []string) error {
 	}
 	// 设置配置需要正好两个参数
 	if len(args) != 2 {
-		return fmt.Errorf("usage: git-visible set [email|months] <value>")
+		return fmt.Errorf("usage: git-visible set [email|months|cache_max_mb|co_authors|mailmap_file|theme|thresholds|week_start|timezone] <value>")
 	}
 	return nil
 }
 
-// runSet 执行 set 顶层逻辑（显示或设置 email/months）。
+// runSet 执行 set 顶层逻辑（显示或设置 email/months/cache_max_mb/co_authors/mailmap_file/theme/thresholds/week_start/timezone）。
 func runSet(cmd *cobra.Command, args []string) error {
 	// 加载当前配置
 	cfg, err := config.Load()
@@ -63,7 +74,43 @@ func runSet(cmd *cobra.Command, args []string) error {
 	// 无参数时显示当前配置
 	if len(args) == 0 {
 		out := cmd.OutOrStdout()
-		fmt.Fprintf(out, "email: %s\nmonths: %d\n", cfg.Email, cfg.Months)
+		if cfg.AutoEmail() {
+			fmt.Fprintf(out, "email: %s (detected from git config)\n", config.EmailAuto)
+		} else {
+			fmt.Fprintf(out, "email: %s\n", cfg.Email)
+		}
+		fmt.Fprintf(out, "months: %d\n", cfg.Months)
+		if cfg.CacheMaxMB > 0 {
+			fmt.Fprintf(out, "cache_max_mb: %d\n", cfg.CacheMaxMB)
+		} else {
+			fmt.Fprintln(out, "cache_max_mb: 0 (unlimited)")
+		}
+		fmt.Fprintf(out, "co_authors: %t\n", cfg.CoAuthors)
+		if cfg.MailmapFile != "" {
+			fmt.Fprintf(out, "mailmap_file: %s\n", cfg.MailmapFile)
+		} else {
+			fmt.Fprintln(out, "mailmap_file: (none)")
+		}
+		if cfg.Theme != "" {
+			fmt.Fprintf(out, "theme: %s\n", cfg.Theme)
+		} else {
+			fmt.Fprintf(out, "theme: %s (default)\n", stats.DefaultThemeName)
+		}
+		if cfg.Thresholds != "" {
+			fmt.Fprintf(out, "thresholds: %s\n", cfg.Thresholds)
+		} else {
+			fmt.Fprintln(out, "thresholds: fixed (default)")
+		}
+		if cfg.WeekStart != "" {
+			fmt.Fprintf(out, "week_start: %s\n", cfg.WeekStart)
+		} else {
+			fmt.Fprintln(out, "week_start: (default: sunday for heatmaps, ISO monday for weekly buckets)")
+		}
+		if cfg.Timezone != "" {
+			fmt.Fprintf(out, "timezone: %s\n", cfg.Timezone)
+		} else {
+			fmt.Fprintln(out, "timezone: local (default)")
+		}
 		printAliases(out, cfg.Aliases, "aliases: (none)")
 		return nil
 	}
@@ -74,7 +121,10 @@ func runSet(cmd *cobra.Command, args []string) error {
 	// 根据 key 修改对应配置项
 	switch key {
 	case "email":
-		cfg.Email = val
+		cfg.Email = strings.TrimSpace(val)
+		if strings.EqualFold(cfg.Email, config.EmailAuto) {
+			cfg.Email = config.EmailAuto
+		}
 	case "months":
 		months, err := strconv.Atoi(val)
 		if err != nil {
@@ -84,8 +134,52 @@ func runSet(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("months must be > 0, got %d", months)
 		}
 		cfg.Months = months
+	case "cache_max_mb":
+		maxMB, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("invalid cache_max_mb %q: %w", val, err)
+		}
+		if maxMB < 0 {
+			return fmt.Errorf("cache_max_mb must be >= 0, got %d", maxMB)
+		}
+		cfg.CacheMaxMB = maxMB
+	case "co_authors":
+		enabled, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid co_authors %q: %w", val, err)
+		}
+		cfg.CoAuthors = enabled
+	case "mailmap_file":
+		cfg.MailmapFile = strings.TrimSpace(val)
+	case "theme":
+		theme, err := resolveTheme(cfg, val)
+		if err != nil {
+			return err
+		}
+		cfg.Theme = theme.Name
+	case "thresholds":
+		thresholds, err := stats.ParseThresholds(val)
+		if err != nil {
+			return err
+		}
+		cfg.Thresholds = thresholds.String()
+	case "week_start":
+		weekStart, err := stats.ParseWeekStart(val)
+		if err != nil {
+			return err
+		}
+		cfg.WeekStart = strings.ToLower(weekStart.String())
+	case "timezone":
+		tz, err := stats.ParseTimezone(val)
+		if err != nil {
+			return err
+		}
+		cfg.Timezone = tz.String()
+		if cfg.Timezone == "local" {
+			cfg.Timezone = ""
+		}
 	default:
-		return fmt.Errorf("unsupported key %q (supported: email, months)", key)
+		return fmt.Errorf("unsupported key %q (supported: email, months, cache_max_mb, co_authors, mailmap_file, theme, thresholds, week_start, timezone)", key)
 	}
 
 	// 保存修改后的配置
@@ -217,6 +217,23 @@ func TestSet_SetEmail_HappyPath(t *testing.T) {
 	assert.Equal(t, "alice@company.com", cfg.Email)
 }
 
+func TestSet_SetEmailAuto(t *testing.T) {
+	withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	_, err := executeSetCommand(t, "email", "AUTO")
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, config.EmailAuto, cfg.Email)
+	assert.True(t, cfg.AutoEmail())
+
+	out, err := executeSetCommand(t)
+	require.NoError(t, err)
+	assert.Contains(t, out, "email: auto (detected from git config)\n")
+}
+
 func TestSet_SetMonths_HappyPath(t *testing.T) {
 	withTempHome(t)
 	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
@@ -229,6 +246,22 @@ func TestSet_SetMonths_HappyPath(t *testing.T) {
 	assert.Equal(t, 12, cfg.Months)
 }
 
+func TestSet_SetCoAuthors_HappyPathAndInvalid(t *testing.T) {
+	withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	_, err := executeSetCommand(t, "co_authors", "true")
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.CoAuthors)
+
+	_, err = executeSetCommand(t, "co_authors", "maybe")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid co_authors")
+}
+
 func TestSet_SetMonths_InvalidValue_ReturnsError(t *testing.T) {
 	withTempHome(t)
 	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
@@ -267,3 +300,77 @@ func TestSetAliasAdd_CaseInsensitiveUpdate(t *testing.T) {
 	assert.Equal(t, "alice", cfg.Aliases[0].Name)
 	assert.Equal(t, []string{"new@company.com"}, cfg.Aliases[0].Emails)
 }
+
+func TestSet_SetThemeAndThresholds(t *testing.T) {
+	withTempHome(t)
+	setTestConfig(t, config.Config{
+		Email:  "",
+		Months: config.DefaultMonths,
+		Themes: map[string]config.ThemeColors{"ocean": {High: "#023e8a"}},
+	})
+
+	_, err := executeSetCommand(t, "theme", "Colorblind-Safe")
+	require.NoError(t, err)
+	_, err = executeSetCommand(t, "thresholds", "1, 10, 20")
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "colorblind-safe", cfg.Theme)
+	assert.Equal(t, "1,10,20", cfg.Thresholds)
+
+	_, err = executeSetCommand(t, "theme", "ocean")
+	require.NoError(t, err)
+	assert.Equal(t, "ocean", cfg.Theme, "custom themes from config are accepted")
+
+	_, err = executeSetCommand(t, "theme", "sunset")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown theme")
+
+	_, err = executeSetCommand(t, "thresholds", "auto")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid thresholds")
+}
+
+func TestSet_SetWeekStart(t *testing.T) {
+	withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	_, err := executeSetCommand(t, "week_start", "Mon")
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "monday", cfg.WeekStart)
+
+	out, err := executeSetCommand(t)
+	require.NoError(t, err)
+	assert.Contains(t, out, "week_start: monday")
+
+	_, err = executeSetCommand(t, "week_start", "someday")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid week start")
+}
+
+func TestSet_SetTimezone(t *testing.T) {
+	withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	_, err := executeSetCommand(t, "timezone", "Europe/Berlin")
+	require.NoError(t, err)
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", cfg.Timezone)
+
+	_, err = executeSetCommand(t, "timezone", "AUTHOR")
+	require.NoError(t, err)
+	assert.Equal(t, "author", cfg.Timezone)
+
+	_, err = executeSetCommand(t, "timezone", "local")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Timezone)
+
+	_, err = executeSetCommand(t, "timezone", "Mars/Olympus")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid timezone")
+}
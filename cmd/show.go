@@ -17,18 +17,36 @@ import (
 
 // 命令行标志变量
 var (
-	showEmails    []string // 要过滤的邮箱列表
-	showMonths    int      // 统计的月份数
-	showSince     string   // 起始日期：YYYY-MM-DD / YYYY-MM / 2m/1w/1y
-	showUntil     string   // 结束日期：YYYY-MM-DD / YYYY-MM / 2m/1w/1y
-	showBranch    string   // 指定分支名（仅统计该分支）
-	showAllBranch bool     // 是否统计所有分支（去重）
-	showFormat    string   // 输出格式：table/json/csv
-	showNoLegend  bool     // 是否隐藏图例（仅 table 输出）
-	showLegend    bool     // 是否显示图例（仅 table 输出）
-	showNoSummary bool     // 是否隐藏摘要信息
-	showSummary   bool     // 是否显示摘要信息
-	showNoCache   bool     // 是否禁用缓存
+	showEmails      []string // 要过滤的邮箱列表
+	showMonths      int      // 统计的月份数
+	showSince       string   // 起始日期：YYYY-MM-DD / YYYY-MM / 2m/1w/1y
+	showUntil       string   // 结束日期：YYYY-MM-DD / YYYY-MM / 2m/1w/1y
+	showBranch      string   // 指定分支名（仅统计该分支）
+	showAllBranch   bool     // 是否统计所有分支（去重）
+	showFormat      string   // 输出格式：table/json/csv/svg/png
+	showNoLegend    bool     // 是否隐藏图例（仅 table 输出）
+	showLegend      bool     // 是否显示图例（仅 table 输出）
+	showNoSummary   bool     // 是否隐藏摘要信息
+	showSummary     bool     // 是否显示摘要信息
+	showNoCache     bool     // 是否禁用缓存
+	showLines       bool     // 是否统计代码行变更
+	showView        string   // 视图：heatmap/punchcard/trend
+	showNoMerges    bool     // 是否跳过合并提交
+	showMergesOnly  bool     // 是否只统计合并提交
+	showPaths       []string // 路径过滤规则（支持 glob 与 ! 排除）
+	showCoAuthors   bool     // 是否计入 Co-authored-by 共同作者
+	showOutput      string   // 输出文件路径，空表示 stdout
+	showCellSize    int      // SVG/PNG 单元格边长（像素），0 表示使用配置或默认值
+	showTheme       string   // 配色主题，空表示使用配置或默认主题
+	showThresholds  string   // 档位划分：fixed/quantile/a,b,c，空表示使用配置或默认值
+	showColor       string   // 是否着色：auto/always/never
+	showCharset     string   // 单元格字符集：unicode/ascii
+	showLayout      string   // 终端热力图排版：auto/wide/compact/stacked/vertical
+	showYears       string   // 按年堆叠显示的年份列表：2023,2024 或 2023-2025
+	showByYear      bool     // 是否将统计范围按日历年拆分堆叠显示
+	showGranularity string   // 聚合粒度：day/week/month/quarter，空表示 day（trend 视图为 week）
+	showWeekStart   string   // 每周第一天，空表示使用配置（未配置时热力图为周日、按周聚合为 ISO 周一）
+	showTZ          string   // 按天划分使用的时区：local/author/IANA 名称，空表示使用配置
 )
 
 // showCmd 实现 show 子命令，用于显示贡献热力图。
@@ -59,17 +77,44 @@ func addShowFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&showBranch, "branch", "b", "", "Branch to include (default: HEAD)")
 	cmd.Flags().BoolVar(&showAllBranch, "all-branches", false, "Include all local branches (deduplicated by commit hash)")
 	cmd.MarkFlagsMutuallyExclusive("branch", "all-branches")
-	cmd.Flags().StringVarP(&showFormat, "format", "f", "table", "Output format: table/json/csv")
+	cmd.Flags().StringVarP(&showFormat, "format", "f", "table", "Output format: table/json/csv/svg/png (png requires --output)")
+	cmd.Flags().StringVarP(&showOutput, "output", "o", "", "Write output to file instead of stdout")
+	cmd.Flags().IntVar(&showCellSize, "cell-size", 0, "Cell size in pixels for svg/png output (default: config svg.cell_size or 11)")
+	addThemeFlags(cmd, &showTheme, &showThresholds)
+	addColorFlags(cmd, &showColor, &showCharset)
+	cmd.Flags().StringVar(&showYears, "years", "", "Render one Jan-Dec block per year, e.g. 2023,2024,2025 or 2023-2025 (sets the range)")
+	cmd.Flags().BoolVar(&showByYear, "by-year", false, "Split the range into one Jan-Dec block per calendar year (table output)")
+	cmd.MarkFlagsMutuallyExclusive("years", "since")
+	cmd.MarkFlagsMutuallyExclusive("years", "until")
+	cmd.MarkFlagsMutuallyExclusive("years", "months")
+	cmd.Flags().StringVar(&showLayout, "layout", "auto", "Table heatmap layout: auto (fit terminal width), wide, compact, stacked, vertical")
 	cmd.Flags().BoolVar(&showNoLegend, "no-legend", false, "Hide legend in table output")
 	cmd.Flags().BoolVar(&showNoSummary, "no-summary", false, "Hide summary")
 	cmd.Flags().BoolVar(&showNoCache, "no-cache", false, "Disable cache, force full scan")
+	cmd.Flags().BoolVar(&showLines, "lines", false, "Also collect added/deleted lines and files changed (slower)")
+	addMergeFlags(cmd, &showNoMerges, &showMergesOnly)
+	addPathFlag(cmd, &showPaths)
+	addCoAuthorsFlag(cmd, &showCoAuthors)
+	cmd.Flags().StringVar(&showView, "view", "heatmap", "View: heatmap (daily calendar), punchcard (hour x weekday) or trend (totals per week/month/quarter)")
+	cmd.Flags().StringVar(&showGranularity, "granularity", "", "Aggregate table/json/csv output: day, week, month, quarter (default: day; week for --view trend)")
+	addWeekStartFlag(cmd, &showWeekStart)
+	addTimezoneFlag(cmd, &showTZ)
 }
 
 // runShow 是 show 命令的核心逻辑。
 // 它从配置和已添加的仓库中收集提交统计，然后以指定格式输出。
-func runShow(cmd *cobra.Command, _ []string) error {
+func runShow(cmd *cobra.Command, _ []string) (err error) {
 	out := cmd.OutOrStdout()
-	runCtx, err := prepareRun(showEmails, showMonths, showSince, showUntil)
+	since, until := showSince, showUntil
+	var years []int
+	if strings.TrimSpace(showYears) != "" {
+		if years, err = stats.ParseYears(showYears); err != nil {
+			return err
+		}
+		since = fmt.Sprintf("%d-01-01", years[0])
+		until = fmt.Sprintf("%d-12-31", years[len(years)-1])
+	}
+	runCtx, err := prepareRun(showEmails, showMonths, since, until, calendarFlags{weekStart: showWeekStart, timezone: showTZ})
 	if err != nil {
 		if errors.Is(err, errNoRepositoriesAdded) {
 			fmt.Fprintln(out, "no repositories added")
@@ -77,13 +122,110 @@ func runShow(cmd *cobra.Command, _ []string) error {
 		}
 		return err
 	}
+	if strings.EqualFold(strings.TrimSpace(showFormat), "png") && strings.TrimSpace(showOutput) == "" {
+		return fmt.Errorf("--format png requires --output")
+	}
+	if showCellSize < 0 {
+		return fmt.Errorf("cell-size must be >= 0, got %d", showCellSize)
+	}
+	theme, err := resolveTheme(runCtx.Config, showTheme)
+	if err != nil {
+		return err
+	}
+	thresholds, err := resolveThresholds(runCtx.Config, showThresholds)
+	if err != nil {
+		return err
+	}
+	charset, err := stats.ParseCharset(showCharset)
+	if err != nil {
+		return err
+	}
+	layout, err := stats.ParseLayout(showLayout)
+	if err != nil {
+		return err
+	}
+	granularity, err := stats.ParseGranularity(showGranularity)
+	if err != nil {
+		return err
+	}
+	// 未设置 week_start 时按周聚合沿用 ISO 8601（周一开始）
+	weekStart, err := resolveWeekStart(runCtx.Config, showWeekStart, time.Monday)
+	if err != nil {
+		return err
+	}
+	if showByYear && years == nil {
+		years = stats.YearsBetween(runCtx.Since, runCtx.Until)
+	}
+
+	// 输出先写入临时文件，全部成功后才替换目标：格式错误或收集失败时不截断已有文件
+	if path := strings.TrimSpace(showOutput); path != "" {
+		pending, createErr := createPendingOutput(path)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if err != nil {
+				pending.discard()
+				return
+			}
+			if commitErr := pending.commit(); commitErr != nil {
+				err = fmt.Errorf("write output: %w", commitErr)
+			}
+		}()
+		out = pending.file
+	}
+	color, err := resolveColor(showColor, out)
+	if err != nil {
+		return err
+	}
+	// 终端输出只需 ASCII 时，摘要中的制表符同样替换
+	text := func(s string) string {
+		if charset == stats.CharsetASCII {
+			return stats.ToASCII(s)
+		}
+		return s
+	}
 
 	// 收集所有仓库的提交统计
 	branchOpt := stats.BranchOption{
 		Branch:      strings.TrimSpace(showBranch),
 		AllBranches: showAllBranch,
 	}
-	st, collectErr := stats.CollectStats(runCtx.Repos, runCtx.Emails, runCtx.Since, runCtx.Until, branchOpt, runCtx.NormalizeEmail, !showNoCache)
+	opts := runCtx.collectOptions(branchOpt, !showNoCache)
+	opts.Merges = mergeModeFromFlags(showNoMerges, showMergesOnly)
+	opts.Paths = showPaths
+	opts.CoAuthors = opts.CoAuthors || showCoAuthors
+
+	trend := false
+	switch strings.ToLower(strings.TrimSpace(showView)) {
+	case "", "heatmap":
+	case "trend":
+		trend = true
+		if strings.TrimSpace(showGranularity) == "" {
+			granularity = stats.GranularityWeek
+		}
+	case "punchcard":
+		// 打卡图按小时 × 星期聚合，不使用热力图档位、按年分块与聚合粒度
+		switch {
+		case showLines:
+			return fmt.Errorf("--lines is not supported with --view punchcard")
+		case strings.TrimSpace(showThresholds) != "":
+			return fmt.Errorf("--thresholds is not supported with --view punchcard")
+		case granularity != stats.GranularityDay:
+			return fmt.Errorf("--granularity is not supported with --view punchcard")
+		case strings.TrimSpace(showYears) != "":
+			return fmt.Errorf("--years is not supported with --view punchcard")
+		case showByYear:
+			return fmt.Errorf("--by-year is not supported with --view punchcard")
+		}
+		return runShowPunchcard(cmd, out, opts, stats.PunchcardOptions{Theme: theme, NoColor: !color, Charset: charset, WeekStart: runCtx.WeekStart})
+	default:
+		return fmt.Errorf("unsupported view %q (supported: heatmap, punchcard, trend)", showView)
+	}
+
+	opts.LineStats = showLines
+	activity, collectErr := stats.CollectActivity(opts)
+	st := stats.ActivityCounts(activity)
 	if collectErr != nil {
 		if len(st) == 0 {
 			return fmt.Errorf("all repositories failed to collect stats: %w", collectErr)
@@ -94,29 +236,138 @@ func runShow(cmd *cobra.Command, _ []string) error {
 	showLegend = !showNoLegend
 	showSummary = !showNoSummary
 
+	// 未开启 --lines 时不输出代码行字段
+	lines := activity
+	if !showLines {
+		lines = nil
+	}
+
+	if trend || granularity != stats.GranularityDay {
+		buckets := stats.AggregateActivity(activity, runCtx.Since, runCtx.Until, granularity, weekStart)
+		return writeBuckets(out, buckets, granularity, weekStart, st, activity, stats.TrendOptions{
+			ShowSummary: showSummary,
+			Charset:     charset,
+			Width:       terminalWidth(out),
+		})
+	}
+
 	// 根据指定格式输出结果
 	switch strings.ToLower(strings.TrimSpace(showFormat)) {
 	case "", "table":
-		fmt.Fprint(out, stats.RenderHeatmapWithOptions(st, stats.HeatmapOptions{
+		heatmapOpts := stats.HeatmapOptions{
 			ShowLegend:  showLegend,
 			ShowSummary: showSummary,
 			Since:       runCtx.Since,
 			Until:       runCtx.Until,
-		}))
+			Theme:       theme,
+			Thresholds:  thresholds,
+			NoColor:     !color,
+			Charset:     charset,
+			Layout:      layout,
+			Width:       terminalWidth(out),
+			WeekStart:   runCtx.WeekStart,
+		}
+		if years != nil {
+			fmt.Fprint(out, stats.RenderYearsHeatmap(st, years, heatmapOpts))
+		} else {
+			fmt.Fprint(out, stats.RenderHeatmapWithOptions(st, heatmapOpts))
+		}
+		if showSummary {
+			total := stats.SumActivity(activity)
+			if opts.Merges != stats.MergesExclude {
+				fmt.Fprint(out, text(stats.RenderMergeTotals(total.Merges, total.Commits)))
+			}
+			if showLines {
+				fmt.Fprint(out, text(stats.RenderLineTotals(total.Lines)))
+			}
+		}
 		return nil
 	case "json":
-		return writeJSON(out, st, showSummary)
+		return writeJSON(out, st, activity, showLines, showSummary)
 	case "csv":
-		return writeCSV(out, st)
+		return writeCSV(out, st, lines)
+	case "svg":
+		_, err := io.WriteString(out, stats.RenderHeatmapSVG(st, svgOptions(runCtx, activity, opts.Merges, theme, thresholds)))
+		return err
+	case "png":
+		return stats.RenderHeatmapPNG(out, st, svgOptions(runCtx, activity, opts.Merges, theme, thresholds))
 	default:
-		return fmt.Errorf("unsupported format %q (supported: table, json, csv)", showFormat)
+		return fmt.Errorf("unsupported format %q (supported: table, json, csv, svg, png)", showFormat)
+	}
+}
+
+// writeBuckets 以指定格式输出按时间段聚合的统计：table 为趋势视图，json/csv 为每个时间段一行。
+func writeBuckets(out io.Writer, buckets []stats.Bucket, g stats.Granularity, weekStart time.Weekday, st map[time.Time]int, activity map[time.Time]stats.DayActivity, trendOpts stats.TrendOptions) error {
+	switch strings.ToLower(strings.TrimSpace(showFormat)) {
+	case "", "table":
+		_, err := io.WriteString(out, stats.RenderTrend(buckets, g, trendOpts))
+		return err
+	case "json":
+		return writeBucketJSON(out, buckets, g, weekStart, st, activity, showLines, showSummary)
+	case "csv":
+		return writeBucketCSV(out, buckets, showLines)
+	default:
+		return fmt.Errorf("unsupported format %q for aggregated output (supported: table, json, csv)", showFormat)
+	}
+}
+
+// svgOptions 基于配置与命令行标志构建 SVG/PNG 渲染参数，摘要附带与 table 输出相同的合并提交/代码行合计。
+func svgOptions(runCtx *RunContext, activity map[time.Time]stats.DayActivity, merges stats.MergeMode, theme stats.Theme, thresholds stats.Thresholds) stats.SVGOptions {
+	svgCfg := runCtx.Config.SVG
+	cellSize := svgCfg.CellSize
+	if showCellSize > 0 {
+		cellSize = showCellSize
+	}
+
+	var footer strings.Builder
+	if showSummary {
+		total := stats.SumActivity(activity)
+		if merges != stats.MergesExclude {
+			footer.WriteString(stats.RenderMergeTotals(total.Merges, total.Commits))
+		}
+		if showLines {
+			footer.WriteString(stats.RenderLineTotals(total.Lines))
+		}
+	}
+
+	return stats.SVGOptions{
+		ShowLegend:    showLegend,
+		ShowSummary:   showSummary,
+		Since:         runCtx.Since,
+		Until:         runCtx.Until,
+		CellSize:      cellSize,
+		Colors:        imageColors(runCtx, theme),
+		Thresholds:    thresholds,
+		WeekStart:     runCtx.WeekStart,
+		SummaryFooter: footer.String(),
+	}
+}
+
+// imageColors 返回 SVG/PNG/HTML 使用的配色：以主题配色为基础，配置中的 svg.colors 优先。
+func imageColors(runCtx *RunContext, theme stats.Theme) stats.SVGColors {
+	return theme.SVGColors().Override(svgColorsFromConfig(runCtx))
+}
+
+// svgColorsFromConfig 将配置中的 svg.colors 转换为渲染配色，未设置项由渲染器补全默认值。
+func svgColorsFromConfig(runCtx *RunContext) stats.SVGColors {
+	c := runCtx.Config.SVG.Colors
+	return stats.SVGColors{
+		Background: c.Background,
+		Text:       c.Text,
+		Empty:      c.Empty,
+		Low:        c.Low,
+		Medium:     c.Medium,
+		High:       c.High,
+		Today:      c.Today,
 	}
 }
 
 // dayStat 表示单日的提交统计，用于 JSON 输出。
+// 开启 --lines 时内嵌的 LineTotals 会展开为 additions/deletions/files 字段。
 type dayStat struct {
 	Date  string `json:"date"`  // 日期，格式为 YYYY-MM-DD
 	Count int    `json:"count"` // 当日提交数
+	*stats.LineTotals
 }
 
 // summaryStreak 表示 JSON 输出中的连续提交天数信息。
@@ -140,12 +391,14 @@ type summaryPeakDay struct {
 
 // summaryOut 表示 JSON 输出中的统计摘要。
 type summaryOut struct {
-	TotalCommits      int            `json:"totalCommits"`
-	ActiveDays        int            `json:"activeDays"`
-	CurrentStreak     int            `json:"currentStreak"`
-	LongestStreak     summaryStreak  `json:"longestStreak"`
-	MostActiveWeekday summaryWeekday `json:"mostActiveWeekday"`
-	PeakDay           summaryPeakDay `json:"peakDay"`
+	TotalCommits      int               `json:"totalCommits"`
+	ActiveDays        int               `json:"activeDays"`
+	CurrentStreak     int               `json:"currentStreak"`
+	LongestStreak     summaryStreak     `json:"longestStreak"`
+	MostActiveWeekday summaryWeekday    `json:"mostActiveWeekday"`
+	PeakDay           summaryPeakDay    `json:"peakDay"`
+	MergeCommits      int               `json:"mergeCommits"`
+	Lines             *stats.LineTotals `json:"lines,omitempty"`
 }
 
 // jsonOutput 是 show 命令 JSON 格式的顶层输出结构。
@@ -155,62 +408,79 @@ type jsonOutput struct {
 }
 
 // writeJSON 将统计数据以 JSON 格式输出。
-// 输出包含 days 数组与可选 summary 字段。
-func writeJSON(out io.Writer, st map[time.Time]int, includeSummary bool) error {
-	// 按日期排序
+// 输出包含 days 数组与可选 summary 字段（含合并提交数）；lineStats 为 true 时附带代码行统计。
+func writeJSON(out io.Writer, st map[time.Time]int, activity map[time.Time]stats.DayActivity, lineStats bool, includeSummary bool) error {
+	outObj := jsonOutput{Days: buildDayStats(st, activity, lineStats)}
+	if includeSummary {
+		so := buildSummaryOut(st, activity, lineStats)
+		outObj.Summary = &so
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(outObj)
+}
+
+// buildDayStats 将按天统计转换为按日期排序的 JSON 行；lineStats 为 true 时附带代码行统计。
+func buildDayStats(st map[time.Time]int, activity map[time.Time]stats.DayActivity, lineStats bool) []dayStat {
 	keys := make([]time.Time, 0, len(st))
 	for k := range st {
 		keys = append(keys, k)
 	}
 	sort.Slice(keys, func(i, j int) bool { return keys[i].Before(keys[j]) })
 
-	// 转换为输出结构
 	rows := make([]dayStat, 0, len(keys))
 	for _, k := range keys {
-		rows = append(rows, dayStat{
+		row := dayStat{
 			Date:  k.Format("2006-01-02"),
 			Count: st[k],
-		})
-	}
-
-	outObj := jsonOutput{Days: rows}
-	if includeSummary {
-		s := stats.CalculateSummary(st)
-
-		so := summaryOut{
-			TotalCommits:  s.TotalCommits,
-			ActiveDays:    s.ActiveDays,
-			CurrentStreak: s.CurrentStreak,
-			LongestStreak: summaryStreak{Days: s.LongestStreak.Days},
-			MostActiveWeekday: summaryWeekday{
-				Weekday: stats.WeekdayAbbrev(s.MostActiveWeekday.Weekday),
-				Commits: s.MostActiveWeekday.Commits,
-			},
-			PeakDay: summaryPeakDay{
-				Commits: s.PeakDay.Commits,
-			},
-		}
-		if !s.LongestStreak.Start.IsZero() {
-			so.LongestStreak.Start = s.LongestStreak.Start.Format("2006-01-02")
-		}
-		if !s.LongestStreak.End.IsZero() {
-			so.LongestStreak.End = s.LongestStreak.End.Format("2006-01-02")
 		}
-		if !s.PeakDay.Date.IsZero() {
-			so.PeakDay.Date = s.PeakDay.Date.Format("2006-01-02")
+		if lineStats {
+			l := activity[k].Lines
+			row.LineTotals = &l
 		}
-
-		outObj.Summary = &so
+		rows = append(rows, row)
 	}
+	return rows
+}
 
-	enc := json.NewEncoder(out)
-	enc.SetIndent("", "  ")
-	return enc.Encode(outObj)
+// buildSummaryOut 计算 JSON 输出中的统计摘要（含合并提交数，lineStats 为 true 时附带代码行合计）。
+func buildSummaryOut(st map[time.Time]int, activity map[time.Time]stats.DayActivity, lineStats bool) summaryOut {
+	s := stats.CalculateSummary(st)
+
+	so := summaryOut{
+		TotalCommits:  s.TotalCommits,
+		ActiveDays:    s.ActiveDays,
+		CurrentStreak: s.CurrentStreak,
+		LongestStreak: summaryStreak{Days: s.LongestStreak.Days},
+		MostActiveWeekday: summaryWeekday{
+			Weekday: stats.WeekdayAbbrev(s.MostActiveWeekday.Weekday),
+			Commits: s.MostActiveWeekday.Commits,
+		},
+		PeakDay: summaryPeakDay{
+			Commits: s.PeakDay.Commits,
+		},
+	}
+	if !s.LongestStreak.Start.IsZero() {
+		so.LongestStreak.Start = s.LongestStreak.Start.Format("2006-01-02")
+	}
+	if !s.LongestStreak.End.IsZero() {
+		so.LongestStreak.End = s.LongestStreak.End.Format("2006-01-02")
+	}
+	if !s.PeakDay.Date.IsZero() {
+		so.PeakDay.Date = s.PeakDay.Date.Format("2006-01-02")
+	}
+	total := stats.SumActivity(activity)
+	so.MergeCommits = total.Merges
+	if lineStats {
+		so.Lines = &total.Lines
+	}
+	return so
 }
 
 // writeCSV 将统计数据以 CSV 格式输出。
-// 输出包含表头 date,count，数据按日期排序。
-func writeCSV(out io.Writer, st map[time.Time]int) error {
+// 输出包含表头 date,count（lines 非 nil 时追加 additions,deletions,files），数据按日期排序。
+func writeCSV(out io.Writer, st map[time.Time]int, lines map[time.Time]stats.DayActivity) error {
 	// 按日期排序
 	keys := make([]time.Time, 0, len(st))
 	for k := range st {
@@ -220,12 +490,104 @@ func writeCSV(out io.Writer, st map[time.Time]int) error {
 
 	w := csv.NewWriter(out)
 	// 写入表头
-	if err := w.Write([]string{"date", "count"}); err != nil {
+	header := []string{"date", "count"}
+	if lines != nil {
+		header = append(header, "additions", "deletions", "files")
+	}
+	if err := w.Write(header); err != nil {
 		return err
 	}
 	// 写入数据行
 	for _, k := range keys {
-		if err := w.Write([]string{k.Format("2006-01-02"), fmt.Sprintf("%d", st[k])}); err != nil {
+		row := []string{k.Format("2006-01-02"), fmt.Sprintf("%d", st[k])}
+		if lines != nil {
+			l := lines[k].Lines
+			row = append(row, fmt.Sprintf("%d", l.Additions), fmt.Sprintf("%d", l.Deletions), fmt.Sprintf("%d", l.Files))
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// bucketStat 表示一个聚合时间段的提交统计，用于 JSON 输出。
+// 开启 --lines 时内嵌的 LineTotals 会展开为 additions/deletions/files 字段。
+type bucketStat struct {
+	Period     string `json:"period"`     // 时间段标签：2025-W23 / 2025-06 / 2025-Q2 / 2025-06-02
+	Start      string `json:"start"`      // 时间段第一天，格式为 YYYY-MM-DD
+	End        string `json:"end"`        // 时间段最后一天，格式为 YYYY-MM-DD
+	Count      int    `json:"count"`      // 时间段内提交数
+	ActiveDays int    `json:"activeDays"` // 时间段内有提交的天数
+	*stats.LineTotals
+}
+
+// bucketJSONOutput 是 show 命令按时间段聚合时 JSON 格式的顶层输出结构。
+type bucketJSONOutput struct {
+	Granularity string       `json:"granularity"`
+	WeekStart   string       `json:"weekStart,omitempty"` // 仅按周聚合时输出
+	Buckets     []bucketStat `json:"buckets"`
+	Summary     *summaryOut  `json:"summary,omitempty"`
+}
+
+// writeBucketJSON 将聚合统计以 JSON 格式输出，summary 与按天输出一致。
+func writeBucketJSON(out io.Writer, buckets []stats.Bucket, g stats.Granularity, weekStart time.Weekday, st map[time.Time]int, activity map[time.Time]stats.DayActivity, lineStats bool, includeSummary bool) error {
+	outObj := bucketJSONOutput{
+		Granularity: g.String(),
+		Buckets:     make([]bucketStat, 0, len(buckets)),
+	}
+	if g == stats.GranularityWeek {
+		outObj.WeekStart = strings.ToLower(weekStart.String())
+	}
+	for _, b := range buckets {
+		row := bucketStat{
+			Period:     b.Label,
+			Start:      b.Start.Format("2006-01-02"),
+			End:        b.End.Format("2006-01-02"),
+			Count:      b.Activity.Commits,
+			ActiveDays: b.ActiveDays,
+		}
+		if lineStats {
+			l := b.Activity.Lines
+			row.LineTotals = &l
+		}
+		outObj.Buckets = append(outObj.Buckets, row)
+	}
+	if includeSummary {
+		so := buildSummaryOut(st, activity, lineStats)
+		outObj.Summary = &so
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(outObj)
+}
+
+// writeBucketCSV 将聚合统计以 CSV 格式输出。
+// 输出包含表头 period,start,end,count,active_days（lineStats 为 true 时追加 additions,deletions,files）。
+func writeBucketCSV(out io.Writer, buckets []stats.Bucket, lineStats bool) error {
+	w := csv.NewWriter(out)
+	header := []string{"period", "start", "end", "count", "active_days"}
+	if lineStats {
+		header = append(header, "additions", "deletions", "files")
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		row := []string{
+			b.Label,
+			b.Start.Format("2006-01-02"),
+			b.End.Format("2006-01-02"),
+			fmt.Sprintf("%d", b.Activity.Commits),
+			fmt.Sprintf("%d", b.ActiveDays),
+		}
+		if lineStats {
+			l := b.Activity.Lines
+			row = append(row, fmt.Sprintf("%d", l.Additions), fmt.Sprintf("%d", l.Deletions), fmt.Sprintf("%d", l.Files))
+		}
+		if err := w.Write(row); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// runShowPunchcard 输出 show --view punchcard 的星期 × 小时分布，style 提供 table 输出的主题、着色与字符集。
+func runShowPunchcard(cmd *cobra.Command, out io.Writer, opts stats.CollectOptions, style stats.PunchcardOptions) error {
+	p, collectErr := stats.CollectPunchcard(opts)
+	if collectErr != nil {
+		if p.Total() == 0 {
+			return fmt.Errorf("all repositories failed to collect stats: %w", collectErr)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: some repositories failed, showing partial results:", collectErr)
+	}
+
+	showLegend = !showNoLegend
+	showSummary = !showNoSummary
+
+	switch strings.ToLower(strings.TrimSpace(showFormat)) {
+	case "", "table":
+		style.ShowLegend = showLegend
+		style.ShowSummary = showSummary
+		fmt.Fprint(out, stats.RenderPunchcardWithOptions(p, style))
+		return nil
+	case "json":
+		return writePunchcardJSON(out, p, style.WeekStart, showSummary)
+	case "csv":
+		return writePunchcardCSV(out, p, style.WeekStart)
+	default:
+		return fmt.Errorf("unsupported format %q (supported: table, json, csv)", showFormat)
+	}
+}
+
+// punchcardRow 表示 JSON 输出中单个星期几的 24 小时提交数。
+type punchcardRow struct {
+	Weekday string `json:"weekday"`
+	Hours   []int  `json:"hours"` // 下标 0-23 对应小时
+	Total   int    `json:"total"`
+}
+
+// punchcardPeak 表示 JSON 输出中提交最多的时段。
+type punchcardPeak struct {
+	Weekday string `json:"weekday,omitempty"`
+	Hour    int    `json:"hour"`
+	Commits int    `json:"commits"`
+}
+
+// punchcardSummaryOut 表示 punchcard JSON 输出中的摘要。
+type punchcardSummaryOut struct {
+	TotalCommits      int           `json:"totalCommits"`
+	Peak              punchcardPeak `json:"peak"`
+	AfterHoursCommits int           `json:"afterHoursCommits"`
+	AfterHoursPercent float64       `json:"afterHoursPercent"`
+}
+
+// punchcardJSONOutput 是 punchcard JSON 格式的顶层输出结构。
+type punchcardJSONOutput struct {
+	Weekdays []punchcardRow       `json:"weekdays"`
+	Summary  *punchcardSummaryOut `json:"summary,omitempty"`
+}
+
+// writePunchcardJSON 将 punchcard 以 JSON 格式输出，星期从 weekStart 开始（与 table 输出一致）。
+func writePunchcardJSON(out io.Writer, p stats.Punchcard, weekStart time.Weekday, includeSummary bool) error {
+	outObj := punchcardJSONOutput{Weekdays: make([]punchcardRow, 0, len(p))}
+	for i := range p {
+		wd := (weekStart + time.Weekday(i)) % 7
+		row := punchcardRow{
+			Weekday: stats.WeekdayAbbrev(wd),
+			Hours:   append([]int(nil), p[wd][:]...),
+		}
+		for _, c := range p[wd] {
+			row.Total += c
+		}
+		outObj.Weekdays = append(outObj.Weekdays, row)
+	}
+
+	if includeSummary {
+		s := stats.CalculatePunchcardSummary(p)
+		so := punchcardSummaryOut{
+			TotalCommits:      s.TotalCommits,
+			Peak:              punchcardPeak{Hour: s.PeakHour, Commits: s.PeakCommits},
+			AfterHoursCommits: s.AfterHours,
+			AfterHoursPercent: s.AfterHoursPercent,
+		}
+		if s.PeakCommits > 0 {
+			so.Peak.Weekday = stats.WeekdayAbbrev(s.PeakWeekday)
+		}
+		outObj.Summary = &so
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(outObj)
+}
+
+// writePunchcardCSV 将 punchcard 以 CSV 格式输出。
+// 输出包含表头 weekday,hour,count，共 7×24 行，星期从 weekStart 开始（与 table 输出一致）。
+func writePunchcardCSV(out io.Writer, p stats.Punchcard, weekStart time.Weekday) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"weekday", "hour", "count"}); err != nil {
+		return err
+	}
+	for i := range p {
+		wd := (weekStart + time.Weekday(i)) % 7
+		for h, c := range p[wd] {
+			row := []string{stats.WeekdayAbbrev(wd), fmt.Sprintf("%d", h), fmt.Sprintf("%d", c)}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -3,7 +3,10 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"image/png"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -121,6 +124,43 @@ func TestShow_EmailFlagWithWhitespace_IsTrimmed(t *testing.T) {
 	assert.Equal(t, 2, total)
 }
 
+func TestShow_LinesJSON(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 2, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	resetShowFlags()
+	showFormat = "json"
+	showSince = "2025-06-01"
+	showUntil = "2025-06-01"
+	showLines = true
+	showSummary = true
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	require.NoError(t, runShow(c, nil))
+
+	var got jsonOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output=%s", out.String())
+	require.Len(t, got.Days, 1)
+	require.NotNil(t, got.Days[0].LineTotals)
+	// 首次提交新增 1 行，第二次提交替换该行
+	assert.Equal(t, 2, got.Days[0].Additions)
+	assert.Equal(t, 1, got.Days[0].Deletions)
+	assert.Equal(t, 2, got.Days[0].Files)
+
+	require.NotNil(t, got.Summary)
+	require.NotNil(t, got.Summary.Lines)
+	assert.Equal(t, 2, got.Summary.Lines.Additions)
+}
+
 func resetShowFlags() {
 	showEmails = nil
 	showMonths = 0
@@ -134,4 +174,638 @@ func resetShowFlags() {
 	showNoSummary = false
 	showSummary = false
 	showNoCache = false
+	showLines = false
+	showView = "heatmap"
+	showNoMerges = false
+	showMergesOnly = false
+	showPaths = nil
+	showCoAuthors = false
+	showOutput = ""
+	showCellSize = 0
+	showTheme = ""
+	showThresholds = ""
+	showColor = "auto"
+	showCharset = "unicode"
+	showLayout = "auto"
+	showYears = ""
+	showByYear = false
+	showGranularity = ""
+	showWeekStart = ""
+	showTZ = ""
+}
+
+func TestShow_PunchcardJSON(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	// 2025-06-03 是周二
+	base := time.Date(2025, 6, 3, 21, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 2, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	resetShowFlags()
+	showFormat = "json"
+	showView = "punchcard"
+	showSince = "2025-06-01"
+	showUntil = "2025-06-30"
+	showSummary = true
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	require.NoError(t, runShow(c, nil))
+
+	var got punchcardJSONOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output=%s", out.String())
+	require.Len(t, got.Weekdays, 7)
+	assert.Equal(t, "Sun", got.Weekdays[0].Weekday)
+	assert.Equal(t, "Tue", got.Weekdays[2].Weekday)
+	require.Len(t, got.Weekdays[2].Hours, 24)
+	assert.Equal(t, 2, got.Weekdays[2].Hours[21])
+	assert.Equal(t, 2, got.Weekdays[2].Total)
+
+	require.NotNil(t, got.Summary)
+	assert.Equal(t, 2, got.Summary.TotalCommits)
+	assert.Equal(t, 2, got.Summary.AfterHoursCommits)
+	assert.Equal(t, "Tue", got.Summary.Peak.Weekday)
+	assert.Equal(t, 21, got.Summary.Peak.Hour)
+
+	// --week-start 对 JSON 与 CSV 同样生效，与 table 输出的行顺序一致
+	showWeekStart = "monday"
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	got = punchcardJSONOutput{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output=%s", out.String())
+	assert.Equal(t, "Mon", got.Weekdays[0].Weekday)
+	assert.Equal(t, "Sun", got.Weekdays[6].Weekday)
+	assert.Equal(t, 2, got.Weekdays[1].Hours[21])
+
+	showFormat = "csv"
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, "Mon,0,0", lines[1])
+	assert.Equal(t, "Tue,21,2", lines[1+24+21])
+	assert.Equal(t, "Sun,23,0", lines[7*24])
+}
+
+func TestShow_PunchcardRejectsLines(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 1, "user@example.com", time.Now())
+	writeReposFile(t, home, []string{repoPath})
+
+	resetShowFlags()
+	showView = "punchcard"
+	showLines = true
+
+	c := &cobra.Command{}
+	c.SetOut(&bytes.Buffer{})
+
+	err := runShow(c, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--lines")
+}
+
+func TestShow_PunchcardRejectsHeatmapOnlyFlags(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 1, "user@example.com", time.Now())
+	writeReposFile(t, home, []string{repoPath})
+
+	tests := []struct {
+		flag string
+		set  func()
+	}{
+		{"--thresholds", func() { showThresholds = "quantile" }},
+		{"--granularity", func() { showGranularity = "week" }},
+		{"--years", func() { showYears = "2024,2025" }},
+		{"--by-year", func() { showByYear = true }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			resetShowFlags()
+			showView = "punchcard"
+			tt.set()
+
+			c := &cobra.Command{}
+			c.SetOut(&bytes.Buffer{})
+
+			err := runShow(c, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.flag+" is not supported with --view punchcard")
+		})
+	}
+}
+
+func TestShow_MergesOnlyJSON(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 2, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	resetShowFlags()
+	showFormat = "json"
+	showSince = "2025-06-01"
+	showUntil = "2025-06-01"
+	showSummary = true
+	showMergesOnly = true
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	require.NoError(t, runShow(c, nil))
+
+	var got jsonOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output=%s", out.String())
+	assert.Empty(t, got.Days, "linear history has no merge commits")
+	require.NotNil(t, got.Summary)
+	assert.Equal(t, 0, got.Summary.MergeCommits)
+}
+
+func TestShow_SVGToOutputFile(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{
+		Email:  "",
+		Months: config.DefaultMonths,
+		SVG:    config.SVGConfig{CellSize: 14, Colors: config.SVGColors{High: "#0a0a0a"}},
+	})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 10, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	resetShowFlags()
+	showFormat = "svg"
+	showSince = "2025-06-01"
+	showUntil = "2025-06-30"
+	showOutput = filepath.Join(home, "heatmap.svg")
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	require.NoError(t, runShow(c, nil))
+	assert.Empty(t, out.String(), "output goes to the file")
+
+	data, err := os.ReadFile(showOutput)
+	require.NoError(t, err)
+	svg := string(data)
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.Contains(t, svg, `width="14" height="14"`)
+	assert.Contains(t, svg, `fill="#0a0a0a"><title>2025-06-02: 10 commits</title>`)
+
+	// --cell-size 覆盖配置
+	resetShowFlags()
+	showFormat = "svg"
+	showSince = "2025-06-01"
+	showUntil = "2025-06-30"
+	showCellSize = 9
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	assert.Contains(t, out.String(), `width="9" height="9"`)
+}
+
+func TestShow_OutputReplacedOnlyOnSuccess(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 1, "user@example.com", time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local))
+	outPath := filepath.Join(home, "heatmap.svg")
+	require.NoError(t, os.WriteFile(outPath, []byte("previous"), 0o600))
+
+	c := &cobra.Command{}
+	c.SetOut(&bytes.Buffer{})
+	c.SetErr(&bytes.Buffer{})
+	assertUnchanged := func() {
+		t.Helper()
+		data, err := os.ReadFile(outPath)
+		require.NoError(t, err)
+		assert.Equal(t, "previous", string(data))
+		entries, err := os.ReadDir(home)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotContains(t, e.Name(), ".tmp", "temporary output must be removed")
+		}
+	}
+
+	// 格式错误
+	writeReposFile(t, home, []string{repoPath})
+	resetShowFlags()
+	showFormat = "bogus"
+	showSince = "2025-06-01"
+	showUntil = "2025-06-30"
+	showOutput = outPath
+	require.ErrorContains(t, runShow(c, nil), "unsupported format")
+	assertUnchanged()
+
+	// 全部仓库失败
+	writeReposFile(t, home, []string{filepath.Join(home, "code", "missing")})
+	showFormat = "svg"
+	require.ErrorContains(t, runShow(c, nil), "all repositories failed")
+	assertUnchanged()
+
+	// 成功时替换内容并沿用原文件权限
+	writeReposFile(t, home, []string{repoPath})
+	require.NoError(t, runShow(c, nil))
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "<svg "))
+	info, err := os.Stat(outPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestShow_PNGRequiresOutputAndWritesImage(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 2, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	resetShowFlags()
+	showFormat = "png"
+	showSince = "2025-06-01"
+	showUntil = "2025-06-30"
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	err := runShow(c, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--output")
+
+	showOutput = filepath.Join(home, "heatmap.png")
+	require.NoError(t, runShow(c, nil))
+
+	f, err := os.Open(showOutput)
+	require.NoError(t, err)
+	defer f.Close()
+	cfg, err := png.DecodeConfig(f)
+	require.NoError(t, err)
+	assert.Positive(t, cfg.Width)
+	assert.Positive(t, cfg.Height)
+}
+
+func TestShow_ThemeAndThresholdsFromConfigAndFlags(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{
+		Email:      "",
+		Months:     config.DefaultMonths,
+		Theme:      "ocean",
+		Themes:     map[string]config.ThemeColors{"ocean": {Low: "#caf0f8", High: "#023e8a"}},
+		Thresholds: "quantile",
+	})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 10, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	render := func(setFlags func()) (string, error) {
+		resetShowFlags()
+		showFormat = "svg"
+		showSince = "2025-06-01"
+		showUntil = "2025-06-30"
+		setFlags()
+
+		var out bytes.Buffer
+		c := &cobra.Command{}
+		c.SetOut(&out)
+		c.SetErr(&out)
+		err := runShow(c, nil)
+		return out.String(), err
+	}
+
+	// 分位档位下唯一的提交日落在最低档
+	svg, err := render(func() {})
+	require.NoError(t, err)
+	assert.Contains(t, svg, `fill="#caf0f8"><title>2025-06-02: 10 commits</title>`)
+	assert.Contains(t, svg, ">1-10</text>", "legend follows the computed thresholds")
+
+	// 命令行标志覆盖配置
+	svg, err = render(func() { showThresholds = "fixed" })
+	require.NoError(t, err)
+	assert.Contains(t, svg, `fill="#023e8a"><title>2025-06-02: 10 commits</title>`)
+
+	svg, err = render(func() { showTheme = "halloween"; showThresholds = "1,20,30" })
+	require.NoError(t, err)
+	assert.Contains(t, svg, `fill="#ffee4a"><title>2025-06-02: 10 commits</title>`)
+	assert.Contains(t, svg, ">1-19</text>")
+
+	_, err = render(func() { showTheme = "sunset" })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown theme "sunset"`)
+
+	_, err = render(func() { showThresholds = "10,5,1" })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid thresholds")
+}
+
+func TestShow_ColorAndCharsetFlags(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 3, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	render := func(color, charset string) (string, error) {
+		resetShowFlags()
+		showSince = "2025-06-01"
+		showUntil = "2025-06-30"
+		showColor = color
+		showCharset = charset
+
+		var out bytes.Buffer
+		c := &cobra.Command{}
+		c.SetOut(&out)
+		c.SetErr(&out)
+		err := runShow(c, nil)
+		return out.String(), err
+	}
+
+	// 输出不是终端时 auto 不着色
+	out, err := render("auto", "unicode")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "\x1b[")
+	assert.Contains(t, out, "▒▒")
+
+	out, err = render("always", "ascii")
+	require.NoError(t, err)
+	assert.Contains(t, out, "\x1b[")
+	assert.Contains(t, out, "oo")
+
+	out, err = render("never", "ascii")
+	require.NoError(t, err)
+	for _, r := range out {
+		require.Less(t, r, rune(0x80), "ascii output contains %q", r)
+	}
+	assert.Contains(t, out, "Merge commits: 0")
+
+	_, err = render("auto", "ebcdic")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported charset")
+}
+
+func TestShow_LayoutFlag(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	base := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	createRepoWithCommits(t, repoPath, 1, "user@example.com", base)
+	writeReposFile(t, home, []string{repoPath})
+
+	resetShowFlags()
+	showSince = "2025-01-01"
+	showUntil = "2025-12-31"
+	showNoLegend = true
+	showNoSummary = true
+	showLayout = "vertical"
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+	require.NoError(t, runShow(c, nil))
+	assert.True(t, strings.HasPrefix(out.String(), "    Su  Mo  Tu  We  Th  Fr  Sa\n"))
+
+	// 未知终端宽度时 auto 使用 wide；COLUMNS 提示宽度不足时自动改用更窄的版面
+	t.Setenv("COLUMNS", "120")
+	resetShowFlags()
+	showSince = "2025-01-01"
+	showUntil = "2025-12-31"
+	showNoLegend = true
+	showNoSummary = true
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	for _, line := range strings.Split(out.String(), "\n") {
+		assert.LessOrEqual(t, len([]rune(line)), 120, line)
+	}
+
+	resetShowFlags()
+	showLayout = "diagonal"
+	require.ErrorContains(t, runShow(c, nil), "unsupported layout")
+}
+
+func TestShow_YearsAndByYear(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 2, "user@example.com", time.Date(2023, 6, 2, 12, 0, 0, 0, time.Local))
+	writeReposFile(t, home, []string{repoPath})
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	resetShowFlags()
+	showYears = "2023,2024"
+	require.NoError(t, runShow(c, nil))
+	assert.True(t, strings.HasPrefix(out.String(), "2023\n"))
+	assert.Contains(t, out.String(), "\n2024\n")
+	// 2024-01-01 是周一，首列从 2023-12-31 开始，月份标题仍应取范围内的 1 月
+	_, block2024, _ := strings.Cut(out.String(), "\n2024\n")
+	assert.True(t, strings.HasPrefix(block2024, "    Jan "), "year block header: %q", strings.SplitN(block2024, "\n", 2)[0])
+	assert.Contains(t, out.String(), "Summary: 2 commits")
+	assert.Contains(t, out.String(), "Summary: 0 commits")
+
+	resetShowFlags()
+	showSince = "2022-11-01"
+	showUntil = "2023-12-31"
+	showByYear = true
+	showNoSummary = true
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	assert.True(t, strings.HasPrefix(out.String(), "2022\n"))
+	assert.Contains(t, out.String(), "\n2023\n")
+	assert.NotContains(t, out.String(), "Summary:")
+
+	resetShowFlags()
+	showYears = "2023-2021"
+	require.ErrorContains(t, runShow(c, nil), "invalid years")
+}
+
+func TestShow_GranularityAndTrend(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	// 2025-06-01 是周日：ISO 周（周一开始）属于 2025-W22，周日开始时属于 2025-W23
+	createRepoWithCommits(t, repoPath, 3, "user@example.com", time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local))
+	writeReposFile(t, home, []string{repoPath})
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	resetShowFlags()
+	showSince = "2025-05-26"
+	showUntil = "2025-06-08"
+	showGranularity = "week"
+	showFormat = "csv"
+	require.NoError(t, runShow(c, nil))
+	assert.Equal(t, "period,start,end,count,active_days\n"+
+		"2025-W22,2025-05-26,2025-06-01,3,1\n"+
+		"2025-W23,2025-06-02,2025-06-08,0,0\n", out.String())
+
+	resetShowFlags()
+	showSince = "2025-05-26"
+	showUntil = "2025-06-08"
+	showGranularity = "week"
+	showWeekStart = "sunday"
+	showFormat = "json"
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	var got struct {
+		Granularity string `json:"granularity"`
+		WeekStart   string `json:"weekStart"`
+		Buckets     []struct {
+			Period string `json:"period"`
+			Start  string `json:"start"`
+			Count  int    `json:"count"`
+		} `json:"buckets"`
+		Summary *struct {
+			TotalCommits int `json:"totalCommits"`
+		} `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "week", got.Granularity)
+	assert.Equal(t, "sunday", got.WeekStart)
+	require.Len(t, got.Buckets, 3)
+	assert.Equal(t, "2025-W22", got.Buckets[0].Period)
+	assert.Equal(t, "2025-05-25", got.Buckets[0].Start)
+	assert.Equal(t, "2025-W23", got.Buckets[1].Period)
+	assert.Equal(t, 3, got.Buckets[1].Count)
+	require.NotNil(t, got.Summary)
+	assert.Equal(t, 3, got.Summary.TotalCommits)
+
+	resetShowFlags()
+	showSince = "2025-01-01"
+	showUntil = "2025-12-31"
+	showView = "trend"
+	showGranularity = "quarter"
+	showCharset = "ascii"
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	assert.Contains(t, out.String(), "2025-Q2 "+strings.Repeat("#", 40)+" 3\n")
+	assert.Contains(t, out.String(), "2025-Q1 "+strings.Repeat(" ", 40)+" 0\n")
+	assert.Contains(t, out.String(), "Trend: _#__\n")
+	assert.Contains(t, out.String(), "Total: 3 commits | Average: 0.8 per quarter | Peak: 2025-Q2 (3 commits)")
+
+	resetShowFlags()
+	showGranularity = "week"
+	showFormat = "svg"
+	require.ErrorContains(t, runShow(c, nil), "unsupported format")
+
+	resetShowFlags()
+	showGranularity = "fortnight"
+	require.ErrorContains(t, runShow(c, nil), "unsupported granularity")
+}
+
+func TestShow_WeekStartFromConfigAndFlag(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths, WeekStart: "monday"})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	// 2025-06-01 是周日
+	createRepoWithCommits(t, repoPath, 2, "user@example.com", time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local))
+	writeReposFile(t, home, []string{repoPath})
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	resetShowFlags()
+	showSince = "2025-06-01"
+	showUntil = "2025-06-30"
+	showLayout = "vertical"
+	showNoLegend = true
+	showNoSummary = true
+	require.NoError(t, runShow(c, nil))
+	assert.True(t, strings.HasPrefix(out.String(), "    Mo  Tu  We  Th  Fr  Sa  Su\n"))
+
+	// 配置 week_start 同样作用于按周聚合；--week-start 优先于配置
+	resetShowFlags()
+	showSince = "2025-06-01"
+	showUntil = "2025-06-07"
+	showGranularity = "week"
+	showFormat = "csv"
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	assert.Contains(t, out.String(), "2025-W22,2025-05-26,2025-06-01,2,1\n")
+
+	resetShowFlags()
+	showSince = "2025-06-01"
+	showUntil = "2025-06-07"
+	showGranularity = "week"
+	showWeekStart = "sunday"
+	showFormat = "csv"
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	assert.Contains(t, out.String(), "2025-W23,2025-06-01,2025-06-07,2,1\n")
+
+	resetShowFlags()
+	showWeekStart = "funday"
+	require.ErrorContains(t, runShow(c, nil), "invalid week start")
+}
+
+func TestShow_TimezoneFromConfigAndFlag(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths, Timezone: "UTC"})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	// UTC 为 2025-03-11 04:00，作者所在时区（UTC-8）为 3/10 20:00
+	createRepoWithCommits(t, repoPath, 1, "user@example.com", time.Date(2025, 3, 10, 20, 0, 0, 0, time.FixedZone("", -8*3600)))
+	writeReposFile(t, home, []string{repoPath})
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&out)
+
+	resetShowFlags()
+	showSince = "2025-03-01"
+	showUntil = "2025-03-31"
+	showFormat = "csv"
+	require.NoError(t, runShow(c, nil))
+	assert.Equal(t, "date,count\n2025-03-11,1\n", out.String())
+
+	resetShowFlags()
+	showSince = "2025-03-01"
+	showUntil = "2025-03-31"
+	showFormat = "csv"
+	showTZ = "author"
+	out.Reset()
+	require.NoError(t, runShow(c, nil))
+	assert.Equal(t, "date,count\n2025-03-10,1\n", out.String())
+
+	resetShowFlags()
+	showTZ = "Nowhere/City"
+	require.ErrorContains(t, runShow(c, nil), "invalid timezone")
 }
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// 命令行标志变量
+var (
+	standupEmails     []string // 要过滤的邮箱列表，空表示使用配置 email
+	standupDays       int      // 向前回溯的工作日数
+	standupBranch     string   // 指定分支名
+	standupAllBranch  bool     // 是否遍历所有本地分支（去重）
+	standupNoMerges   bool     // 是否跳过合并提交
+	standupMergesOnly bool     // 是否只列出合并提交
+	standupCoAuthors  bool     // 是否计入 Co-authored-by 共同作者
+	standupFormat     string   // 输出格式：text/markdown/json
+	standupTZ         string   // 按天划分使用的时区，空表示使用配置或本地时区
+)
+
+// standupCmd 实现 standup 子命令，汇总自上一个工作日以来自己在所有仓库中的提交。
+var standupCmd = newStandupCmd()
+
+// newStandupCmd 构建 standup 命令，便于在测试中复用。
+func newStandupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "standup",
+		Short: "Summarize your commits since the last working day",
+		Long: `List your commits since the last working day (weekends are skipped, so on
+Monday it starts from Friday), grouped by repository and oldest first.
+
+Commits are matched with the configured email and aliases, like "show".
+Use --days to look further back and --format markdown to paste the result
+into chat.`,
+		Example: `  git-visible standup
+  git-visible standup --days 3
+  git-visible standup --format markdown | pbcopy`,
+		Args: cobra.NoArgs,
+		RunE: runStandup,
+	}
+
+	cmd.Flags().StringArrayVarP(&standupEmails, "email", "e", nil, "Email filter (repeatable; default: config email)")
+	cmd.Flags().IntVar(&standupDays, "days", 1, "Number of working days to look back (weekends are skipped)")
+	cmd.Flags().StringVarP(&standupBranch, "branch", "b", "", "Branch to include (default: HEAD)")
+	cmd.Flags().BoolVar(&standupAllBranch, "all-branches", false, "Include all local branches (deduplicated by commit hash)")
+	cmd.MarkFlagsMutuallyExclusive("branch", "all-branches")
+	cmd.Flags().StringVarP(&standupFormat, "format", "f", "text", "Output format: text/markdown/json")
+	addMergeFlags(cmd, &standupNoMerges, &standupMergesOnly)
+	addCoAuthorsFlag(cmd, &standupCoAuthors)
+	addTimezoneFlag(cmd, &standupTZ)
+	return cmd
+}
+
+// init 注册 standup 命令。
+func init() {
+	rootCmd.AddCommand(standupCmd)
+}
+
+// runStandup 是 standup 命令的核心逻辑：从今天回溯 --days 个工作日，按仓库分组输出提交。
+func runStandup(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	format := strings.ToLower(strings.TrimSpace(standupFormat))
+	switch format {
+	case "", "text", "markdown", "md", "json":
+	default:
+		return fmt.Errorf("unsupported format %q (supported: text, markdown, json)", standupFormat)
+	}
+	if standupDays <= 0 {
+		return fmt.Errorf("days must be > 0, got %d", standupDays)
+	}
+
+	runCtx, err := prepareRun(standupEmails, 0, "", "", calendarFlags{timezone: standupTZ})
+	if err != nil {
+		if errors.Is(err, errNoRepositoriesAdded) {
+			fmt.Fprintln(out, "no repositories added")
+			return nil
+		}
+		return err
+	}
+	if len(runCtx.Emails) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: no email configured, listing commits from all authors (set one with: git-visible set email <you@example.com>)")
+	}
+	// prepareRun 的结束日期即今天（按 --tz 时区）
+	runCtx.Since = stats.WorkingDaysBefore(runCtx.Until, standupDays)
+
+	opts := runCtx.collectOptions(stats.BranchOption{
+		Branch:      strings.TrimSpace(standupBranch),
+		AllBranches: standupAllBranch,
+	}, false)
+	opts.Merges = mergeModeFromFlags(standupNoMerges, standupMergesOnly)
+	opts.CoAuthors = opts.CoAuthors || standupCoAuthors
+
+	commits, done, collectErr := stats.CollectCommits(opts)
+	if collectErr != nil {
+		if len(done) == 0 {
+			return fmt.Errorf("all repositories failed to collect commits: %w", collectErr)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: some repositories failed, showing partial results:", collectErr)
+	}
+	groups := stats.GroupCommitsByRepo(commits)
+
+	switch format {
+	case "markdown", "md":
+		writeStandupMarkdown(out, runCtx.Since, groups)
+		return nil
+	case "json":
+		return writeStandupJSON(out, runCtx.Since, runCtx.Until, len(commits), groups)
+	default:
+		writeStandupText(out, runCtx.Since, len(commits), groups)
+		return nil
+	}
+}
+
+// standupTimeLayout 返回提交时间的显示格式：一周以内显示星期与时刻，更长范围显示日期。
+func standupTimeLayout(groups []stats.RepoCommits, since time.Time) string {
+	for _, g := range groups {
+		for _, c := range g.Commits {
+			if c.Time.Sub(since) >= 7*24*time.Hour {
+				return "2006-01-02 15:04"
+			}
+		}
+	}
+	return "Mon 15:04"
+}
+
+// writeStandupText 以纯文本输出：标题行后每个仓库一段，段内提交从旧到新。
+func writeStandupText(out io.Writer, since time.Time, total int, groups []stats.RepoCommits) {
+	header := "Since " + since.Format("Mon 2006-01-02")
+	if total == 0 {
+		fmt.Fprintf(out, "%s: no commits found\n", header)
+		return
+	}
+	fmt.Fprintf(out, "%s: %d %s in %d %s\n", header,
+		total, stats.Pluralize(total, "commit", "commits"),
+		len(groups), stats.Pluralize(len(groups), "repository", "repositories"))
+
+	layout := standupTimeLayout(groups, since)
+	for _, g := range groups {
+		fmt.Fprintf(out, "\n%s\n", displayRepoPath(g.Repo))
+		for _, c := range g.Commits {
+			fmt.Fprintf(out, "  %s  %s  %s\n", c.Time.Format(layout), c.ShortHash(), c.Subject)
+		}
+	}
+}
+
+// writeStandupMarkdown 以 Markdown 输出，便于粘贴到聊天工具：仓库名加粗，提交为列表项。
+func writeStandupMarkdown(out io.Writer, since time.Time, groups []stats.RepoCommits) {
+	fmt.Fprintf(out, "**Since %s**\n", since.Format("Mon 2006-01-02"))
+	if len(groups) == 0 {
+		fmt.Fprintln(out, "\n_No commits._")
+		return
+	}
+	for _, g := range groups {
+		fmt.Fprintf(out, "\n**%s**\n", filepath.Base(g.Repo))
+		for _, c := range g.Commits {
+			fmt.Fprintf(out, "- %s (`%s`)\n", c.Subject, c.ShortHash())
+		}
+	}
+}
+
+// standupJSONOutput 是 standup 命令 JSON 输出的顶层结构。
+type standupJSONOutput struct {
+	Since string              `json:"since"`
+	Until string              `json:"until"`
+	Total int                 `json:"total"`
+	Repos []stats.RepoCommits `json:"repos"`
+}
+
+// writeStandupJSON 以 JSON 格式输出按仓库分组的提交。
+func writeStandupJSON(out io.Writer, since, until time.Time, total int, groups []stats.RepoCommits) error {
+	if groups == nil {
+		groups = []stats.RepoCommits{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(standupJSONOutput{
+		Since: since.Format("2006-01-02"),
+		Until: until.Format("2006-01-02"),
+		Total: total,
+		Repos: groups,
+	})
+}
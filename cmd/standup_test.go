@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+	"git-visible/internal/stats"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupStandupRepos 创建两个仓库：上一个工作日与今天各有自己的提交，
+// 另有他人的提交与更早工作日的提交，返回上一个工作日。
+func setupStandupRepos(t *testing.T) time.Time {
+	t.Helper()
+
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{
+		Email:   "me@example.com",
+		Months:  config.DefaultMonths,
+		Aliases: []config.Alias{{Name: "me", Emails: []string{"me@example.com", "me@personal.dev"}}},
+	})
+
+	now := timeNowLocal()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	last := stats.WorkingDaysBefore(today, 1)
+	earlier := stats.WorkingDaysBefore(today, 2)
+
+	api := filepath.Join(home, "code", "api")
+	createRepoWithCommitSpecs(t, api, []commitSpec{
+		{Email: "me@example.com", When: earlier.Add(10 * time.Hour), Message: "Earlier work"},
+		{Email: "me@example.com", When: last.Add(10 * time.Hour), Message: "Fix login"},
+		{Email: "other@example.com", When: last.Add(11 * time.Hour), Message: "Not mine"},
+		{Email: "me@personal.dev", When: today.Add(30 * time.Minute), Message: "Add tests"},
+	})
+	web := filepath.Join(home, "code", "web")
+	createRepoWithCommitSpecs(t, web, []commitSpec{
+		{Email: "me@example.com", When: last.Add(15 * time.Hour), Message: "Tweak button"},
+	})
+	writeReposFile(t, home, []string{web, api})
+	return last
+}
+
+func TestStandup_TextGroupsByRepoOldestFirst(t *testing.T) {
+	last := setupStandupRepos(t)
+
+	out, err := executeStandupCommand(t)
+	require.NoError(t, err, out)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 8, out)
+	assert.Equal(t, "Since "+last.Format("Mon 2006-01-02")+": 3 commits in 2 repositories", lines[0])
+	assert.Equal(t, "~/code/api", lines[2])
+	assert.Regexp(t, `^  \w{3} 10:00  [0-9a-f]{7}  Fix login$`, lines[3])
+	assert.Regexp(t, `^  \w{3} 00:30  [0-9a-f]{7}  Add tests$`, lines[4], "aliases of the configured email are included")
+	assert.Equal(t, "~/code/web", lines[6])
+	assert.Regexp(t, `Tweak button$`, lines[7])
+}
+
+func TestStandup_DaysAndMarkdown(t *testing.T) {
+	setupStandupRepos(t)
+
+	out, err := executeStandupCommand(t, "--days", "2", "--format", "markdown")
+	require.NoError(t, err, out)
+
+	assert.Contains(t, out, "\n**api**\n- Earlier work (`")
+	assert.Contains(t, out, "\n**web**\n- Tweak button (`")
+	assert.Less(t, strings.Index(out, "Earlier work"), strings.Index(out, "Fix login"))
+	assert.NotContains(t, out, "Not mine")
+}
+
+func TestStandup_JSON(t *testing.T) {
+	last := setupStandupRepos(t)
+
+	out, err := executeStandupCommand(t, "-f", "json")
+	require.NoError(t, err, out)
+
+	var got standupJSONOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &got), out)
+	assert.Equal(t, last.Format("2006-01-02"), got.Since)
+	assert.Equal(t, 3, got.Total)
+	require.Len(t, got.Repos, 2)
+	assert.Len(t, got.Repos[0].Commits, 2)
+}
+
+func TestStandup_InvalidDays(t *testing.T) {
+	withTempHome(t)
+
+	_, err := executeStandupCommand(t, "--days", "0")
+	require.ErrorContains(t, err, "days must be > 0")
+}
+
+func executeStandupCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := newStandupCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
@@ -24,6 +24,14 @@ var (
 	topUntil   string   // 结束日期
 	topFormat  string   // 输出格式：table/json/csv
 	topNoCache bool     // 是否禁用缓存
+	topLines   bool     // 是否统计代码行变更
+
+	topNoMerges   bool     // 是否跳过合并提交
+	topMergesOnly bool     // 是否只统计合并提交
+	topPaths      []string // 路径过滤规则（支持 glob 与 ! 排除）
+	topCoAuthors  bool     // 是否计入 Co-authored-by 共同作者
+	topByPath     int      // 按仓库内目录排行的目录深度，0 表示按仓库排行
+	topTZ         string   // 按天划分使用的时区，空表示使用配置或本地时区
 
 	topNumber int  // 显示的仓库数量
 	topAll    bool // 是否显示所有仓库
@@ -50,6 +58,12 @@ func init() {
 	topCmd.Flags().StringVar(&topUntil, "until", "", "End date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
 	topCmd.Flags().StringVarP(&topFormat, "format", "f", "table", "Output format: table/json/csv")
 	topCmd.Flags().BoolVar(&topNoCache, "no-cache", false, "Disable cache, force full scan")
+	topCmd.Flags().BoolVar(&topLines, "lines", false, "Also collect added/deleted lines and files changed (slower)")
+	addMergeFlags(topCmd, &topNoMerges, &topMergesOnly)
+	addPathFlag(topCmd, &topPaths)
+	addCoAuthorsFlag(topCmd, &topCoAuthors)
+	addTimezoneFlag(topCmd, &topTZ)
+	topCmd.Flags().IntVar(&topByPath, "by-path", 0, "Rank directories inside repositories at this depth instead of repositories")
 
 	rootCmd.AddCommand(topCmd)
 }
@@ -57,7 +71,7 @@ func init() {
 // runTop 是 top 命令的核心逻辑，收集并输出仓库提交排行榜。
 func runTop(cmd *cobra.Command, _ []string) error {
 	out := cmd.OutOrStdout()
-	runCtx, err := prepareRun(topEmails, topMonths, topSince, topUntil)
+	runCtx, err := prepareRun(topEmails, topMonths, topSince, topUntil, calendarFlags{timezone: topTZ})
 	if err != nil {
 		if errors.Is(err, errNoRepositoriesAdded) {
 			fmt.Fprintln(out, "no repositories added")
@@ -69,12 +83,31 @@ func runTop(cmd *cobra.Command, _ []string) error {
 	if !topAll && topNumber <= 0 {
 		return fmt.Errorf("number must be > 0, got %d", topNumber)
 	}
+	if topByPath < 0 {
+		return fmt.Errorf("by-path depth must be > 0, got %d", topByPath)
+	}
+	if topByPath > 0 && topLines {
+		return fmt.Errorf("--lines is not supported with --by-path")
+	}
 
 	since := strings.TrimSpace(topSince)
 	until := strings.TrimSpace(topUntil)
 
 	// 按仓库分别收集提交统计
-	perRepo, collectErr := stats.CollectStatsPerRepo(runCtx.Repos, runCtx.Emails, runCtx.Since, runCtx.Until, stats.BranchOption{}, runCtx.NormalizeEmail, !topNoCache)
+	opts := runCtx.collectOptions(stats.BranchOption{}, !topNoCache)
+	opts.LineStats = topLines
+	opts.Merges = mergeModeFromFlags(topNoMerges, topMergesOnly)
+	opts.Paths = topPaths
+	opts.CoAuthors = opts.CoAuthors || topCoAuthors
+
+	// --by-path 时排行单位为 "仓库/目录"，排行与输出逻辑与按仓库完全一致
+	collect := stats.CollectActivityPerRepo
+	if topByPath > 0 {
+		collect = func(opts stats.CollectOptions) (map[string]map[time.Time]stats.DayActivity, error) {
+			return stats.CollectActivityByPath(opts, topByPath)
+		}
+	}
+	perRepo, collectErr := collect(opts)
 	if collectErr != nil {
 		if len(perRepo) == 0 {
 			return fmt.Errorf("all repositories failed to collect stats: %w", collectErr)
@@ -89,7 +122,12 @@ func runTop(cmd *cobra.Command, _ []string) error {
 	}
 
 	// 计算排行榜（按提交数降序，百分比保证合计 100.0%）
-	ranking := stats.RankRepositories(perRepo, limit)
+	var ranking stats.RepoRanking
+	if topLines {
+		ranking = stats.RankRepositoriesActivity(perRepo, limit)
+	} else {
+		ranking = stats.RankRepositories(activityCountsPerRepo(perRepo), limit)
+	}
 
 	// 根据指定格式输出结果
 	format := strings.ToLower(strings.TrimSpace(topFormat))
@@ -99,7 +137,7 @@ func runTop(cmd *cobra.Command, _ []string) error {
 			fmt.Fprintln(out, "no commits found")
 			return nil
 		}
-		return writeTopTable(out, ranking, topRangeLabel(since, until, runCtx.months, runCtx.Since, runCtx.Until))
+		return writeTopTable(out, ranking, topRangeLabel(since, until, runCtx.months, runCtx.Since, runCtx.Until), topByPath > 0)
 	case "json":
 		return writeTopJSON(out, ranking)
 	case "csv":
@@ -109,6 +147,15 @@ func runTop(cmd *cobra.Command, _ []string) error {
 	}
 }
 
+// activityCountsPerRepo 将按仓库的活动统计降维为按仓库的提交数。
+func activityCountsPerRepo(perRepo map[string]map[time.Time]stats.DayActivity) map[string]map[time.Time]int {
+	out := make(map[string]map[time.Time]int, len(perRepo))
+	for repoPath, daily := range perRepo {
+		out[repoPath] = stats.ActivityCounts(daily)
+	}
+	return out
+}
+
 // topRangeLabel 生成时间范围的显示标签。
 func topRangeLabel(since, until string, months int, start, end time.Time) string {
 	since = strings.TrimSpace(since)
@@ -119,11 +166,16 @@ func topRangeLabel(since, until string, months int, start, end time.Time) string
 	return fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
 }
 
-// writeTopTable 以表格格式输出排行榜。
-func writeTopTable(out io.Writer, ranking stats.RepoRanking, rangeLabel string) error {
+// writeTopTable 以表格格式输出排行榜，byPath 为 true 时排行单位为仓库内目录。
+func writeTopTable(out io.Writer, ranking stats.RepoRanking, rangeLabel string, byPath bool) error {
+	itemLabel, itemsLabel := "Repository", "repositories"
+	if byPath {
+		itemLabel, itemsLabel = "Path", "paths"
+	}
+
 	// 将绝对路径转换为 ~/... 的短路径，并计算仓库列宽度
 	displayPaths := make([]string, 0, len(ranking.Repositories))
-	repoWidth := len("Repository")
+	repoWidth := len(itemLabel)
 	for _, r := range ranking.Repositories {
 		p := displayRepoPath(r.Repository)
 		displayPaths = append(displayPaths, p)
@@ -150,22 +202,44 @@ func writeTopTable(out io.Writer, ranking stats.RepoRanking, rangeLabel string)
 
 	percentWidth := len("100.0%")
 
+	// 开启 --lines 时追加 +Lines / -Lines 两列
+	showLines := ranking.TotalLines != nil
+	addWidth := len("+Lines")
+	delWidth := len("-Lines")
+	if showLines {
+		addWidth = max(addWidth, len(fmt.Sprintf("+%d", ranking.TotalLines.Additions)))
+		delWidth = max(delWidth, len(fmt.Sprintf("-%d", ranking.TotalLines.Deletions)))
+	}
+	linesCols := func(l *stats.LineTotals) string {
+		if !showLines || l == nil {
+			return ""
+		}
+		return fmt.Sprintf(" %*s %*s", addWidth, fmt.Sprintf("+%d", l.Additions), delWidth, fmt.Sprintf("-%d", l.Deletions))
+	}
+
 	// 绘制表格：标题 → 分隔线 → 表头 → 分隔线 → 数据行 → 分隔线 → 汇总行
 	lineLen := rankWidth + 3 + repoWidth + 1 + commitWidth + 1 + percentWidth
+	if showLines {
+		lineLen += 1 + addWidth + 1 + delWidth
+	}
 	rule := strings.Repeat("─", lineLen)
 
-	fmt.Fprintf(out, "Top %d repositories (%s)\n", len(ranking.Repositories), rangeLabel)
+	fmt.Fprintf(out, "Top %d %s (%s)\n", len(ranking.Repositories), itemsLabel, rangeLabel)
 	fmt.Fprintln(out, rule)
-	fmt.Fprintf(out, "%*s   %-*s %*s %*s\n", rankWidth, "#", repoWidth, "Repository", commitWidth, "Commits", percentWidth, "%")
+	fmt.Fprintf(out, "%*s   %-*s %*s %*s", rankWidth, "#", repoWidth, itemLabel, commitWidth, "Commits", percentWidth, "%")
+	if showLines {
+		fmt.Fprintf(out, " %*s %*s", addWidth, "+Lines", delWidth, "-Lines")
+	}
+	fmt.Fprintln(out)
 	fmt.Fprintln(out, rule)
 
 	for i, r := range ranking.Repositories {
 		percentStr := fmt.Sprintf("%.1f%%", r.Percent)
-		fmt.Fprintf(out, "%*d   %-*s %*d %*s\n", rankWidth, i+1, repoWidth, displayPaths[i], commitWidth, r.Commits, percentWidth, percentStr)
+		fmt.Fprintf(out, "%*d   %-*s %*d %*s%s\n", rankWidth, i+1, repoWidth, displayPaths[i], commitWidth, r.Commits, percentWidth, percentStr, linesCols(r.Lines))
 	}
 
 	fmt.Fprintln(out, rule)
-	fmt.Fprintf(out, "%*s   %-*s %*d %*s\n", rankWidth, "", repoWidth, "Total", commitWidth, ranking.TotalCommits, percentWidth, "100.0%")
+	fmt.Fprintf(out, "%*s   %-*s %*d %*s%s\n", rankWidth, "", repoWidth, "Total", commitWidth, ranking.TotalCommits, percentWidth, "100.0%", linesCols(ranking.TotalLines))
 
 	return nil
 }
@@ -180,15 +254,24 @@ func writeTopJSON(out io.Writer, ranking stats.RepoRanking) error {
 // writeTopCSV 以 CSV 格式输出排行榜。
 func writeTopCSV(out io.Writer, ranking stats.RepoRanking) error {
 	w := csv.NewWriter(out)
-	if err := w.Write([]string{"repository", "commits", "percent"}); err != nil {
+	showLines := ranking.TotalLines != nil
+	header := []string{"repository", "commits", "percent"}
+	if showLines {
+		header = append(header, "additions", "deletions", "files")
+	}
+	if err := w.Write(header); err != nil {
 		return err
 	}
 	for _, r := range ranking.Repositories {
-		if err := w.Write([]string{
+		row := []string{
 			r.Repository,
 			fmt.Sprintf("%d", r.Commits),
 			fmt.Sprintf("%.1f", r.Percent),
-		}); err != nil {
+		}
+		if showLines && r.Lines != nil {
+			row = append(row, fmt.Sprintf("%d", r.Lines.Additions), fmt.Sprintf("%d", r.Lines.Deletions), fmt.Sprintf("%d", r.Lines.Files))
+		}
+		if err := w.Write(row); err != nil {
 			return err
 		}
 	}
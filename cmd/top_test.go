@@ -279,6 +279,57 @@ func TestTop_EmailFlagWithWhitespace_IsTrimmed(t *testing.T) {
 	assert.Equal(t, 2, got.Repositories[0].Commits)
 }
 
+func TestTop_ByPathJSON(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "mono")
+	createRepoWithCommitSpecs(t, repoPath, []commitSpec{
+		{File: "api/main.go", Email: "user@example.com", When: time.Date(2025, 6, 1, 10, 0, 0, 0, time.Local)},
+		{File: "api/util.go", Email: "user@example.com", When: time.Date(2025, 6, 1, 11, 0, 0, 0, time.Local)},
+		{File: "web/index.ts", Email: "user@example.com", When: time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)},
+	})
+	writeReposFile(t, home, []string{repoPath})
+
+	resetTopFlags()
+	c := &cobra.Command{Use: "top", Args: cobra.NoArgs, RunE: runTop}
+	addTopFlagsForTest(c)
+
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetErr(&out)
+	c.SetArgs([]string{"--since", "2025-06-01", "--until", "2025-06-30", "--format", "json", "--by-path", "1", "--path", "!web"})
+
+	require.NoError(t, c.Execute())
+
+	var got stats.RepoRanking
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output=%s", out.String())
+	require.Len(t, got.Repositories, 1)
+	assert.Equal(t, filepath.Join(repoPath, "api"), got.Repositories[0].Repository)
+	assert.Equal(t, 2, got.Repositories[0].Commits)
+	assert.Equal(t, 100.0, got.Repositories[0].Percent)
+}
+
+func TestTop_MergeFlagsMutuallyExclusive(t *testing.T) {
+	resetTopFlags()
+	c := &cobra.Command{
+		Use:  "top",
+		Args: cobra.NoArgs,
+		RunE: runTop,
+	}
+	addTopFlagsForTest(c)
+
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetErr(&out)
+	c.SetArgs([]string{"--no-merges", "--merges-only"})
+
+	err := c.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no-merges")
+	assert.Contains(t, err.Error(), "merges-only")
+}
+
 func resetTopFlags() {
 	topEmails = nil
 	topMonths = 0
@@ -288,6 +339,12 @@ func resetTopFlags() {
 	topNumber = 10
 	topAll = false
 	topNoCache = false
+	topLines = false
+	topNoMerges = false
+	topMergesOnly = false
+	topPaths = nil
+	topByPath = 0
+	topCoAuthors = false
 }
 
 func addTopFlagsForTest(cmd *cobra.Command) {
@@ -301,6 +358,11 @@ func addTopFlagsForTest(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&topUntil, "until", "", "End date (YYYY-MM-DD, YYYY-MM, or relative like 2m/1w/1y)")
 	cmd.Flags().StringVarP(&topFormat, "format", "f", "table", "Output format: table/json/csv")
 	cmd.Flags().BoolVar(&topNoCache, "no-cache", false, "Disable cache, force full scan")
+	cmd.Flags().BoolVar(&topLines, "lines", false, "Also collect added/deleted lines and files changed (slower)")
+	addMergeFlags(cmd, &topNoMerges, &topMergesOnly)
+	addPathFlag(cmd, &topPaths)
+	addCoAuthorsFlag(cmd, &topCoAuthors)
+	cmd.Flags().IntVar(&topByPath, "by-path", 0, "Rank directories inside repositories at this depth instead of repositories")
 }
 
 func withTempHome(t *testing.T) string {
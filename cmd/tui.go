@@ -0,0 +1,537 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"git-visible/internal/stats"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// 命令行标志变量
+var (
+	tuiEmails     []string // 初始身份的邮箱过滤，空表示使用配置 email
+	tuiMonths     int      // 初始统计月数
+	tuiAllBranch  bool     // 初始是否遍历所有本地分支
+	tuiTheme      string   // 配色主题，空表示使用配置或默认主题
+	tuiThresholds string   // 档位划分，空表示使用配置或默认值
+	tuiColor      string   // 是否着色：auto/always/never
+	tuiCharset    string   // 单元格字符集：unicode/ascii
+	tuiWeekStart  string   // 每周第一天，空表示使用配置或周日
+	tuiTZ         string   // 按天划分使用的时区，空表示使用配置或本地时区
+)
+
+// tuiMonthsCycle 是按 m 键循环切换的统计月数。
+var tuiMonthsCycle = []int{3, 6, 12}
+
+const (
+	tuiPanelWidth      = 36 // 侧边栏宽度（列）
+	tuiMinHeatmapWidth = 40 // 侧边栏放在右侧时热力图至少保留的宽度，不足时侧边栏移到下方
+	tuiTopRepos        = 3  // 侧边栏显示的仓库数量
+	tuiClearScreen     = "\033[H\033[2J"
+)
+
+// tuiCmd 实现 tui 子命令，提供可用方向键浏览的全屏热力图。
+var tuiCmd = newTUICmd()
+
+// newTUICmd 构建 tui 命令，便于在测试中复用。
+func newTUICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse the heatmap interactively",
+		Long: `Open a full-screen heatmap. Arrow keys (or h/j/k/l) move a cursor across days:
+left/right jump a week, up/down move a day. The side panel shows the selected
+day's commit count, top repositories and commit subjects.
+
+Keys: m cycles the time range (3/6/12 months), i switches identity among the
+configured email and alias groups, b toggles HEAD/all branches, t jumps back
+to today, q or Esc quits.`,
+		Example: `  git-visible tui
+  git-visible tui -m 12 --all-branches`,
+		Args: cobra.NoArgs,
+		RunE: runTUI,
+	}
+
+	cmd.Flags().StringArrayVarP(&tuiEmails, "email", "e", nil, "Email filter for the initial identity (repeatable; default: config email)")
+	cmd.Flags().IntVarP(&tuiMonths, "months", "m", 0, "Initial months to include (default: config value)")
+	cmd.Flags().BoolVar(&tuiAllBranch, "all-branches", false, "Start with all local branches instead of HEAD")
+	addThemeFlags(cmd, &tuiTheme, &tuiThresholds)
+	addColorFlags(cmd, &tuiColor, &tuiCharset)
+	addWeekStartFlag(cmd, &tuiWeekStart)
+	addTimezoneFlag(cmd, &tuiTZ)
+	return cmd
+}
+
+// init 注册 tui 命令。
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+// tuiTerminal 是 TUI 的输入输出端：按键从 in 读取，每帧整屏写入 out，size 返回当前屏幕列数与行数。
+// 测试中以脚本化输入与虚拟屏幕尺寸替换。
+type tuiTerminal struct {
+	in      io.Reader
+	out     io.Writer
+	size    func() (width, height int)
+	restore func() // 退出时恢复终端状态
+}
+
+// openTUITerminalFn 打开交互式终端，测试中可替换。
+var openTUITerminalFn = openTUITerminal
+
+// openTUITerminal 将标准输入切换为 raw 模式并进入备用屏幕。
+func openTUITerminal() (*tuiTerminal, error) {
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !isTerminalFn(inFd) || !isTerminalFn(outFd) {
+		return nil, errors.New("tui requires an interactive terminal")
+	}
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return nil, fmt.Errorf("enable raw mode: %w", err)
+	}
+	// 进入备用屏幕并隐藏光标，退出时按相反顺序恢复
+	fmt.Fprint(os.Stdout, "\033[?1049h\033[?25l")
+	return &tuiTerminal{
+		in:  os.Stdin,
+		out: os.Stdout,
+		size: func() (int, int) {
+			w, h, err := terminalSizeFn(outFd)
+			if err != nil || w <= 0 || h <= 0 {
+				return 80, 24
+			}
+			return w, h
+		},
+		restore: func() {
+			fmt.Fprint(os.Stdout, "\033[?25h\033[?1049l")
+			_ = term.Restore(inFd, state)
+		},
+	}, nil
+}
+
+// runTUI 是 tui 命令的核心逻辑：解析显示选项，构建数据加载函数并运行按键循环。
+func runTUI(cmd *cobra.Command, _ []string) error {
+	runCtx, err := prepareRun(tuiEmails, tuiMonths, "", "", calendarFlags{weekStart: tuiWeekStart, timezone: tuiTZ})
+	if err != nil {
+		if errors.Is(err, errNoRepositoriesAdded) {
+			fmt.Fprintln(cmd.OutOrStdout(), "no repositories added")
+			return nil
+		}
+		return err
+	}
+	theme, err := resolveTheme(runCtx.Config, tuiTheme)
+	if err != nil {
+		return err
+	}
+	thresholds, err := resolveThresholds(runCtx.Config, tuiThresholds)
+	if err != nil {
+		return err
+	}
+	charset, err := stats.ParseCharset(tuiCharset)
+	if err != nil {
+		return err
+	}
+
+	t, err := openTUITerminalFn()
+	if err != nil {
+		return err
+	}
+	if t.restore != nil {
+		defer t.restore()
+	}
+	color, err := resolveColor(tuiColor, t.out)
+	if err != nil {
+		return err
+	}
+
+	identities := tuiIdentities(runCtx)
+	m := &tuiModel{
+		state:      tuiState{months: runCtx.months, allBranches: tuiAllBranch},
+		identities: identities,
+		cache:      make(map[tuiState]*tuiData),
+		heatmap: stats.HeatmapOptions{
+			Theme:      theme,
+			Thresholds: thresholds,
+			NoColor:    !color,
+			Charset:    charset,
+			WeekStart:  runCtx.WeekStart,
+		},
+		load: func(s tuiState) (*tuiData, error) {
+			since, until, err := stats.TimeRange("", "", s.months, runCtx.WeekStart, runCtx.Timezone.Location)
+			if err != nil {
+				return nil, err
+			}
+			opts := runCtx.collectOptions(stats.BranchOption{AllBranches: s.allBranches}, false)
+			opts.Since, opts.Until = since, until
+			opts.Emails = identities[s.identity].emails
+			commits, done, err := stats.CollectCommits(opts)
+			if err != nil && len(done) == 0 {
+				return nil, fmt.Errorf("all repositories failed to collect commits: %w", err)
+			}
+			return newTUIData(since, until, commits), err
+		},
+	}
+	return runTUILoop(m, t)
+}
+
+// tuiIdentities 返回可切换的身份：第一个为命令行或配置的邮箱（未设置时为所有作者），其后为各别名组。
+func tuiIdentities(runCtx *RunContext) []tuiIdentity {
+	first := tuiIdentity{name: "all authors", emails: runCtx.Emails}
+	if len(runCtx.Emails) > 0 {
+		first.name = strings.Join(runCtx.Emails, ", ")
+	}
+	identities := []tuiIdentity{first}
+	for _, alias := range runCtx.Config.Aliases {
+		identities = append(identities, tuiIdentity{name: alias.Name, emails: alias.Emails})
+	}
+	return identities
+}
+
+// tuiIdentity 是可切换的统计身份。
+type tuiIdentity struct {
+	name   string
+	emails []string // 邮箱过滤，空表示所有作者
+}
+
+// tuiState 是决定加载哪份数据的状态，相同状态的数据只加载一次。
+type tuiState struct {
+	months      int
+	identity    int // identities 下标
+	allBranches bool
+}
+
+// tuiData 是某个状态下加载的统计数据。
+type tuiData struct {
+	since   time.Time
+	until   time.Time
+	daily   map[time.Time]int
+	commits map[time.Time][]stats.LogCommit // 按天分组，组内从新到旧
+}
+
+// newTUIData 按天分组提交；日期取提交时间所在时区的自然日，与热力图的统计口径一致。
+func newTUIData(since, until time.Time, commits []stats.LogCommit) *tuiData {
+	d := &tuiData{
+		since:   since,
+		until:   until,
+		daily:   make(map[time.Time]int),
+		commits: make(map[time.Time][]stats.LogCommit),
+	}
+	loc := until.Location()
+	for _, c := range commits {
+		day := time.Date(c.Time.Year(), c.Time.Month(), c.Time.Day(), 0, 0, 0, 0, loc)
+		d.daily[day]++
+		d.commits[day] = append(d.commits[day], c)
+	}
+	return d
+}
+
+// tuiKey 是解码后的按键。
+type tuiKey int
+
+const (
+	keyNone tuiKey = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyMonths
+	keyIdentity
+	keyBranches
+	keyToday
+	keyQuit
+)
+
+// readTUIKey 读取并解码一个按键：方向键（ESC [ A-D 或 ESC O A-D）、h/j/k/l 与功能键。
+// 单独的 Esc（其后没有已到达的字节）与 Ctrl-C 视为退出，无法识别的输入返回 keyNone。
+func readTUIKey(r *bufio.Reader) (tuiKey, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return keyNone, err
+	}
+	switch b {
+	case 0x1b:
+		if r.Buffered() == 0 {
+			return keyQuit, nil
+		}
+		if next, _ := r.ReadByte(); next != '[' && next != 'O' {
+			return keyNone, nil
+		}
+		code, err := r.ReadByte()
+		if err != nil {
+			return keyNone, err
+		}
+		switch code {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		case 'C':
+			return keyRight, nil
+		case 'D':
+			return keyLeft, nil
+		}
+	case 'k':
+		return keyUp, nil
+	case 'j':
+		return keyDown, nil
+	case 'l':
+		return keyRight, nil
+	case 'h':
+		return keyLeft, nil
+	case 'm':
+		return keyMonths, nil
+	case 'i':
+		return keyIdentity, nil
+	case 'b':
+		return keyBranches, nil
+	case 't':
+		return keyToday, nil
+	case 'q', 'Q', 0x03:
+		return keyQuit, nil
+	}
+	return keyNone, nil
+}
+
+// tuiModel 是 TUI 的全部状态，按键处理与渲染都不直接访问终端，便于测试。
+type tuiModel struct {
+	state      tuiState
+	identities []tuiIdentity
+	cursor     time.Time // 光标所在日期，零值表示尚未定位（首次加载后定位到今天）
+	data       *tuiData  // 当前显示的数据
+	loaded     tuiState  // data 对应的状态
+	cache      map[tuiState]*tuiData
+	load       func(tuiState) (*tuiData, error)
+	status     string               // 最近一次加载的警告或错误
+	heatmap    stats.HeatmapOptions // 主题、档位、颜色、字符集与每周第一天；范围、光标与宽度按帧填充
+}
+
+// runTUILoop 运行按键循环：先绘制首帧，之后每读取一个按键更新状态并重绘，
+// 收到退出键或输入结束时返回。首次加载失败时直接返回错误。
+func runTUILoop(m *tuiModel, t *tuiTerminal) error {
+	draw := func() {
+		w, h := t.size()
+		fmt.Fprint(t.out, tuiClearScreen+strings.Join(m.render(w, h), "\r\n"))
+	}
+
+	m.reload()
+	if m.data == nil {
+		return errors.New(m.status)
+	}
+	draw()
+
+	r := bufio.NewReader(t.in)
+	for {
+		k, err := readTUIKey(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if k == keyQuit {
+			return nil
+		}
+		m.handle(k)
+		if m.loaded != m.state {
+			m.status = "loading..."
+			draw()
+			m.reload()
+		}
+		draw()
+	}
+}
+
+// handle 根据按键移动光标或切换状态；切换状态后由调用方重新加载数据。
+func (m *tuiModel) handle(k tuiKey) {
+	switch k {
+	case keyUp:
+		m.moveCursor(-1)
+	case keyDown:
+		m.moveCursor(1)
+	case keyLeft:
+		m.moveCursor(-7)
+	case keyRight:
+		m.moveCursor(7)
+	case keyToday:
+		m.cursor = m.data.until
+	case keyMonths:
+		m.state.months = nextMonths(m.state.months)
+	case keyIdentity:
+		m.state.identity = (m.state.identity + 1) % len(m.identities)
+	case keyBranches:
+		m.state.allBranches = !m.state.allBranches
+	}
+}
+
+// nextMonths 返回循环中下一个更大的月数，已是最大值时回到最小值。
+func nextMonths(cur int) int {
+	for _, n := range tuiMonthsCycle {
+		if n > cur {
+			return n
+		}
+	}
+	return tuiMonthsCycle[0]
+}
+
+// moveCursor 将光标移动 days 天，并限制在统计范围内。
+func (m *tuiModel) moveCursor(days int) {
+	m.cursor = m.cursor.AddDate(0, 0, days)
+	m.clampCursor()
+}
+
+// clampCursor 将光标限制在当前数据的统计范围内，未定位时定位到范围最后一天（今天）。
+func (m *tuiModel) clampCursor() {
+	switch {
+	case m.cursor.IsZero() || m.cursor.After(m.data.until):
+		m.cursor = m.data.until
+	case m.cursor.Before(m.data.since):
+		m.cursor = m.data.since
+	}
+}
+
+// reload 加载当前状态的数据（已加载过的状态直接复用）。
+// 加载失败时保留上一份数据并恢复对应状态，错误显示在状态栏。
+func (m *tuiModel) reload() {
+	m.status = ""
+	data, ok := m.cache[m.state]
+	if !ok {
+		var err error
+		data, err = m.load(m.state)
+		if err != nil {
+			m.status = err.Error()
+		}
+		if data == nil {
+			if m.data != nil {
+				m.state = m.loaded
+			}
+			return
+		}
+		m.cache[m.state] = data
+	}
+	m.data, m.loaded = data, m.state
+	m.clampCursor()
+}
+
+// render 返回一帧的全部行：热力图与侧边栏（宽度足够时并排，否则上下排列），底部为状态栏。
+func (m *tuiModel) render(width, height int) []string {
+	opts := m.heatmap
+	opts.Since, opts.Until, opts.Cursor = m.data.since, m.data.until, m.cursor
+
+	side := width-tuiPanelWidth-3 >= tuiMinHeatmapWidth
+	opts.Width = width
+	if side {
+		opts.Width = width - tuiPanelWidth - 3
+	}
+	heat := strings.Split(strings.TrimSuffix(stats.RenderHeatmapWithOptions(m.data.daily, opts), "\n"), "\n")
+	panel := m.panelLines()
+
+	var lines []string
+	if side {
+		sep := " │ "
+		if opts.Charset == stats.CharsetASCII {
+			sep = " | "
+		}
+		heatW := 0
+		for _, line := range heat {
+			heatW = max(heatW, visibleWidth(line))
+		}
+		for i := 0; i < max(len(heat), len(panel)); i++ {
+			left, right := "", ""
+			if i < len(heat) {
+				left = heat[i]
+			}
+			if i < len(panel) {
+				right = truncateRunes(panel[i], tuiPanelWidth)
+			}
+			line := left + strings.Repeat(" ", heatW-visibleWidth(left)) + sep + right
+			lines = append(lines, strings.TrimRight(line, " "))
+		}
+	} else {
+		lines = append(lines, heat...)
+		lines = append(lines, "")
+		for _, p := range panel {
+			lines = append(lines, truncateRunes(p, width))
+		}
+	}
+
+	// 最后两行留给空行与状态栏
+	if height > 2 && len(lines) > height-2 {
+		lines = lines[:height-2]
+	}
+	return append(lines, "", truncateRunes(m.statusLine(), width))
+}
+
+// panelLines 返回侧边栏内容：光标日期、提交数、提交最多的仓库与提交标题。
+func (m *tuiModel) panelLines() []string {
+	commits := m.data.commits[m.cursor]
+	lines := []string{m.cursor.Format("Mon 2006-01-02")}
+	if len(commits) == 0 {
+		return append(lines, "No commits")
+	}
+	lines = append(lines, fmt.Sprintf("%d %s", len(commits), stats.Pluralize(len(commits), "commit", "commits")))
+
+	counts := make(map[string]int)
+	for _, c := range commits {
+		counts[filepath.Base(c.Repo)]++
+	}
+	repos := make([]string, 0, len(counts))
+	for name := range counts {
+		repos = append(repos, name)
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		if counts[repos[i]] != counts[repos[j]] {
+			return counts[repos[i]] > counts[repos[j]]
+		}
+		return repos[i] < repos[j]
+	})
+	lines = append(lines, "", "Top repositories")
+	for _, name := range repos[:min(len(repos), tuiTopRepos)] {
+		lines = append(lines, fmt.Sprintf("  %-24s %d", name, counts[name]))
+	}
+
+	lines = append(lines, "", "Commits")
+	for _, c := range commits {
+		lines = append(lines, fmt.Sprintf("  %s %s  %s", c.Time.Format("15:04"), filepath.Base(c.Repo), c.Subject))
+	}
+	return lines
+}
+
+// statusLine 返回状态栏：当前范围、身份、分支模式与按键提示，有警告或错误时附在最后。
+func (m *tuiModel) statusLine() string {
+	branch := "HEAD"
+	if m.state.allBranches {
+		branch = "all branches"
+	}
+	line := fmt.Sprintf("[m] %d months  [i] %s  [b] %s  [t] today  [q] quit  arrows/hjkl: move",
+		m.state.months, m.identities[m.state.identity].name, branch)
+	if m.status != "" {
+		line += "  | " + m.status
+	}
+	return line
+}
+
+// ansiEscape 匹配 ANSI 颜色序列。
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// visibleWidth 返回去掉 ANSI 颜色序列后的显示宽度（热力图字符均为单列宽）。
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(s, ""))
+}
+
+// truncateRunes 将纯文本截断为最多 n 个字符。
+func truncateRunes(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
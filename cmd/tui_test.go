@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTUIKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[A\x1b[B\x1b[C\x1b[D\x1bOAhjklmibtxq\x03"))
+	want := []tuiKey{keyUp, keyDown, keyRight, keyLeft, keyUp, keyLeft, keyDown, keyUp, keyRight, keyMonths, keyIdentity, keyBranches, keyToday, keyNone, keyQuit, keyQuit}
+	for i, w := range want {
+		k, err := readTUIKey(r)
+		require.NoError(t, err, "key %d", i)
+		assert.Equal(t, w, k, "key %d", i)
+	}
+	_, err := readTUIKey(r)
+	require.Error(t, err, "end of input")
+
+	// 单独的 Esc（其后没有后续字节）退出
+	k, err := readTUIKey(bufio.NewReader(strings.NewReader("\x1b")))
+	require.NoError(t, err)
+	assert.Equal(t, keyQuit, k)
+}
+
+func TestNextMonths(t *testing.T) {
+	assert.Equal(t, 6, nextMonths(3))
+	assert.Equal(t, 12, nextMonths(6))
+	assert.Equal(t, 3, nextMonths(12))
+	assert.Equal(t, 6, nextMonths(4), "a configured value outside the cycle moves to the next larger one")
+}
+
+func TestTUI_ScriptedSession(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{
+		Email:   "me@example.com",
+		Months:  config.DefaultMonths,
+		Aliases: []config.Alias{{Name: "work", Emails: []string{"work@corp.com"}}},
+	})
+
+	now := timeNowLocal()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	api := filepath.Join(home, "code", "api")
+	createRepoWithCommitSpecs(t, api, []commitSpec{
+		{Email: "me@example.com", When: today.AddDate(0, 0, -7).Add(9 * time.Hour), Message: "Last week fix"},
+		{Email: "me@example.com", When: today.Add(10 * time.Hour), Message: "Today api work"},
+		{Email: "work@corp.com", When: today.Add(11 * time.Hour), Message: "Work account commit"},
+	})
+	web := filepath.Join(home, "code", "web")
+	createRepoWithCommitSpecs(t, web, []commitSpec{
+		{Email: "me@example.com", When: today.Add(8 * time.Hour), Message: "Today web work"},
+	})
+	writeReposFile(t, home, []string{api, web})
+
+	// 脚本：左移一周 → 回到今天 → 切换身份 → 切换分支模式 → 切换月数 → 退出
+	frames := runScriptedTUI(t, 120, 30, "\x1b[Dtibmq", "--charset", "ascii")
+
+	first := frames[0]
+	assert.Contains(t, first, today.Format("Mon 2006-01-02"))
+	assert.Contains(t, first, "2 commits")
+	assert.Contains(t, first, "Today api work")
+	assert.Contains(t, first, "Today web work")
+	assert.NotContains(t, first, "Work account commit")
+	assert.Contains(t, first, "[m] 6 months  [i] me@example.com  [b] HEAD")
+	assert.Contains(t, first, " o X", "cursor cell is marked without color (compact layout)")
+	assert.Contains(t, first, " | ", "panel is beside the heatmap on a wide screen")
+
+	lastWeek := frames[1]
+	assert.Contains(t, lastWeek, today.AddDate(0, 0, -7).Format("Mon 2006-01-02"))
+	assert.Contains(t, lastWeek, "Last week fix")
+
+	assert.Contains(t, frames[2], "Today api work", "t jumps back to today")
+
+	last := frames[len(frames)-1]
+	assert.Contains(t, last, "[m] 12 months  [i] work  [b] all branches")
+	assert.Contains(t, last, "1 commit")
+	assert.Contains(t, last, "Work account commit")
+	assert.NotContains(t, last, "Today api work")
+	for _, f := range frames {
+		assert.LessOrEqual(t, len(strings.Split(f, "\r\n")), 30, "frame fits the virtual screen height")
+	}
+}
+
+func TestTUI_NarrowScreenPutsPanelBelow(t *testing.T) {
+	home := withTempHome(t)
+	setTestConfig(t, config.Config{Email: "", Months: config.DefaultMonths})
+
+	repoPath := filepath.Join(home, "code", "repo-1")
+	createRepoWithCommits(t, repoPath, 1, "me@example.com", timeNowLocal())
+	writeReposFile(t, home, []string{repoPath})
+
+	frames := runScriptedTUI(t, 60, 40, "q")
+	require.Len(t, frames, 1)
+	lines := strings.Split(frames[0], "\r\n")
+	assert.NotContains(t, frames[0], " │ ")
+	assert.Contains(t, frames[0], "\n1 commit\r")
+	assert.Contains(t, lines[len(lines)-1], "all authors")
+	for _, line := range lines {
+		assert.LessOrEqual(t, visibleWidth(line), 60)
+	}
+}
+
+// runScriptedTUI 以脚本化输入与虚拟屏幕尺寸运行 tui 命令，返回绘制的每一帧。
+func runScriptedTUI(t *testing.T, width, height int, script string, args ...string) []string {
+	t.Helper()
+
+	var screen bytes.Buffer
+	orig := openTUITerminalFn
+	openTUITerminalFn = func() (*tuiTerminal, error) {
+		return &tuiTerminal{
+			in:   strings.NewReader(script),
+			out:  &screen,
+			size: func() (int, int) { return width, height },
+		}, nil
+	}
+	t.Cleanup(func() { openTUITerminalFn = orig })
+
+	cmd := newTUICmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	require.NoError(t, cmd.Execute(), out.String())
+
+	var frames []string
+	for _, f := range strings.Split(screen.String(), tuiClearScreen) {
+		if f != "" && !strings.Contains(f, "loading...") {
+			frames = append(frames, f)
+		}
+	}
+	require.NotEmpty(t, frames)
+	return frames
+}
@@ -1,6 +1,7 @@
-// Package cache 提供基于 JSON 文件的统计结果缓存。
-// 缓存文件存储在 ~/.config/git-visible/cache/ 目录下，
-// 以仓库名 + 参数哈希命名，通过 HEAD hash 实现自动失效。
+// Package cache 提供基于 JSON 文件的统计结果缓存与提交索引。
+// 结果缓存存储在 ~/.config/git-visible/cache/ 目录下，
+// 以仓库名 + 参数哈希命名，通过 HEAD hash 实现自动失效；
+// 提交索引存储在 cache/index/ 子目录下，每个仓库一个文件，随新提交增量扩展。
 package cache
 
 import (
@@ -15,22 +16,44 @@ import (
 	"time"
 )
 
+// KeyVersion 是缓存键的格式版本，计入键摘要；键的语义变化时递增，使旧缓存文件自然失效。
+// 版本 2：本地时区以实际时区规则（而非空字符串）计入 Timezone。
+// 版本 3：--path 过滤下合并提交只按相对每个父提交都有变更的文件匹配。
+const KeyVersion = 3
+
 // CacheKey 唯一标识一次仓库扫描的上下文参数。
 // 任何参数变化（包括 HEAD 推进）都会产生不同的缓存键，从而自动失效旧缓存。
 type CacheKey struct {
-	RepoPath  string
+	RepoPath string
+	// HEADHash 是遍历起点的指纹：HEAD 或 --branch 时为对应提交 hash，
+	// --all-branches 时为全部分支 tip 的摘要。字段名沿用旧版本以兼容已有缓存文件。
 	HEADHash  string
 	Emails    []string // 排序后存储，保证顺序无关
 	TimeRange string   // 格式 "2024-01-01_2024-06-30"
 	Branch    string
 	AllBranch bool
+	LineStats bool     // 是否包含代码行统计
+	Merges    string   // 合并提交模式：""（全部）/exclude/only
+	Paths     []string // 路径过滤规则，排序后存储
+	CoAuthors bool     // 是否计入 Co-authored-by 共同作者
+	Mailmap   string   // 生效 mailmap 规则的摘要，无规则时为空
+	Timezone  string   // 按天划分使用的时区：local: 前缀为本地时区的实际规则，author 为作者时区，否则为 IANA 名称
+}
+
+// LineCounts 是单日代码行变更统计的持久化形式。
+type LineCounts struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Files     int `json:"files"`
 }
 
 // CacheEntry 是持久化到磁盘的缓存条目。
 type CacheEntry struct {
-	Key       CacheKey       `json:"key"`
-	Stats     map[string]int `json:"stats"` // 日期字符串 -> 提交数
-	CreatedAt time.Time      `json:"created_at"`
+	Key       CacheKey              `json:"key"`
+	Stats     map[string]int        `json:"stats"`            // 日期字符串 -> 提交数
+	Lines     map[string]LineCounts `json:"lines,omitempty"`  // 日期字符串 -> 代码行统计（仅 LineStats=true）
+	Merges    map[string]int        `json:"merges,omitempty"` // 日期字符串 -> 合并提交数（仅非零日期）
+	CreatedAt time.Time             `json:"created_at"`
 }
 
 // String 返回稳定的短文件名，格式为 "{repoName}_{hash}.json"。
@@ -44,12 +67,19 @@ func (k CacheKey) String() string {
 	}
 
 	payload := strings.Join([]string{
+		fmt.Sprintf("v%d", KeyVersion),
 		normalized.RepoPath,
 		normalized.HEADHash,
 		strings.Join(normalized.Emails, ","),
 		normalized.TimeRange,
 		normalized.Branch,
 		fmt.Sprintf("%t", normalized.AllBranch),
+		fmt.Sprintf("%t", normalized.LineStats),
+		normalized.Merges,
+		strings.Join(normalized.Paths, ","),
+		fmt.Sprintf("%t", normalized.CoAuthors),
+		normalized.Mailmap,
+		normalized.Timezone,
 	}, "\n")
 	digest := sha256.Sum256([]byte(payload))
 	return fmt.Sprintf("%s_%x.json", repoName, digest[:8])
@@ -72,6 +102,7 @@ func LoadCache(key CacheKey) (*CacheEntry, error) {
 	if err := json.Unmarshal(data, &entry); err != nil {
 		return nil, err
 	}
+	touch(cachePath)
 	return &entry, nil
 }
 
@@ -79,6 +110,12 @@ func LoadCache(key CacheKey) (*CacheEntry, error) {
 // 写入使用 tmp + rename 的原子策略，避免并发读到半写文件。
 // stats 会被拷贝一份，调用方可安全修改原 map。
 func SaveCache(key CacheKey, stats map[string]int) error {
+	return SaveCacheActivity(key, stats, nil, nil)
+}
+
+// SaveCacheActivity 写入按天的提交数、代码行统计与合并提交数；lines 为 nil 时不写入 lines 字段。
+// 设置了大小上限（SetSizeLimit）时，写入后按 LRU 淘汰旧文件。
+func SaveCacheActivity(key CacheKey, stats map[string]int, lines map[string]LineCounts, merges map[string]int) error {
 	cachePath, err := getCachePath(key)
 	if err != nil {
 		return err
@@ -91,9 +128,20 @@ func SaveCache(key CacheKey, stats map[string]int) error {
 	statsCopy := make(map[string]int, len(stats))
 	maps.Copy(statsCopy, stats)
 
+	var linesCopy map[string]LineCounts
+	if lines != nil {
+		linesCopy = make(map[string]LineCounts, len(lines))
+		maps.Copy(linesCopy, lines)
+	}
+
+	mergesCopy := make(map[string]int, len(merges))
+	maps.Copy(mergesCopy, merges)
+
 	entry := CacheEntry{
 		Key:       normalizeKey(key),
 		Stats:     statsCopy,
+		Lines:     linesCopy,
+		Merges:    mergesCopy,
 		CreatedAt: time.Now().UTC(),
 	}
 
@@ -111,16 +159,16 @@ func SaveCache(key CacheKey, stats map[string]int) error {
 		_ = os.Remove(tmpPath)
 		return err
 	}
+	enforceSizeLimit(cachePath)
 	return nil
 }
 
 // getCachePath 返回缓存文件的完整路径。
 func getCachePath(key CacheKey) (string, error) {
-	homeDir, err := os.UserHomeDir()
+	cacheDir, err := Dir()
 	if err != nil {
 		return "", err
 	}
-	cacheDir := filepath.Join(homeDir, ".config", "git-visible", "cache")
 	return filepath.Join(cacheDir, key.String()), nil
 }
 
@@ -1,9 +1,12 @@
 package cache
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -74,3 +77,110 @@ func TestCacheMiss(t *testing.T) {
 	require.Error(t, err)
 	assert.True(t, os.IsNotExist(err))
 }
+
+func TestIndexRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	idx := &CommitIndex{
+		RepoPath: filepath.Join(home, "repo"),
+		Tips:     []string{"aaa"},
+		Commits: []IndexedCommit{
+			{Hash: "aaa", Email: "a@example.com", When: 1700000000, Offset: 8 * 3600, Parents: []string{"bbb"}},
+			{Hash: "bbb", Email: "b@example.com", When: 1690000000},
+		},
+	}
+	require.NoError(t, SaveIndex(idx))
+
+	got, err := LoadIndex(filepath.Join(home, "repo"))
+	require.NoError(t, err)
+	assert.Equal(t, IndexVersion, got.Version)
+	assert.Equal(t, idx.Commits, got.Commits)
+	assert.Equal(t, []string{"aaa"}, got.Tips)
+	assert.False(t, got.UpdatedAt.IsZero())
+}
+
+func TestIndexVersionMismatchTreatedAsMissing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	repoPath := filepath.Join(home, "repo")
+	indexPath, err := getIndexPath(repoPath)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(indexPath), 0o700))
+	require.NoError(t, os.WriteFile(indexPath, []byte(`{"version":0,"commits":[]}`), 0o600))
+
+	_, err = LoadIndex(repoPath)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestSaveCache_SizeLimitEvictsLeastRecentlyUsed(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	keyFor := func(i int) CacheKey {
+		return CacheKey{
+			RepoPath:  filepath.Join(home, "repo"),
+			HEADHash:  "head",
+			TimeRange: fmt.Sprintf("2024-01-%02d_2024-01-31", i+1),
+		}
+	}
+	stats := map[string]int{"2024-01-02": 1}
+
+	// 写入三条缓存，并把修改时间依次错开，模拟不同的最近使用时间
+	base := time.Now().Add(-time.Hour)
+	var size int64
+	for i := 0; i < 3; i++ {
+		require.NoError(t, SaveCache(keyFor(i), stats))
+		path, err := getCachePath(keyFor(i))
+		require.NoError(t, err)
+		ts := base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(path, ts, ts))
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		size = info.Size()
+	}
+
+	// 命中最旧的条目会刷新其使用时间
+	_, err := LoadCache(keyFor(0))
+	require.NoError(t, err)
+
+	SetSizeLimit(size * 3)
+	t.Cleanup(func() { SetSizeLimit(0) })
+
+	require.NoError(t, SaveCache(keyFor(3), stats))
+
+	_, err = LoadCache(keyFor(1))
+	assert.True(t, errors.Is(err, os.ErrNotExist), "least recently used entry should be evicted")
+	for _, i := range []int{0, 2, 3} {
+		_, err := LoadCache(keyFor(i))
+		assert.NoError(t, err, "entry %d should be kept", i)
+	}
+}
+
+func TestList_ReportsEntriesAndIndexes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	repoPath := filepath.Join(home, "repo")
+	require.NoError(t, SaveCache(CacheKey{RepoPath: repoPath, HEADHash: "h", TimeRange: "2024-01-01_2024-01-31"}, map[string]int{}))
+	require.NoError(t, SaveIndex(&CommitIndex{RepoPath: repoPath}))
+
+	files, err := List()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var indexes int
+	for _, f := range files {
+		assert.Equal(t, repoPath, f.RepoPath)
+		if f.Index {
+			indexes++
+			assert.Nil(t, f.Entry)
+		} else {
+			require.NotNil(t, f.Entry)
+			assert.Equal(t, "h", f.Entry.Key.HEADHash)
+		}
+	}
+	assert.Equal(t, 1, indexes)
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IndexVersion 是提交索引的格式版本。
+// 索引字段发生不兼容变化时递增，旧版本索引会被整体丢弃并重建。
+// 版本 3：合并提交的变更文件改为相对每个父提交都有变更的文件。
+// 版本 4：新增 FilesDone，区分"变更文件为空"与"尚未计算"。
+const IndexVersion = 4
+
+// IndexedCommit 是提交索引中的单条提交元数据。
+// JSON 字段名使用缩写以减小大仓库索引文件的体积。
+type IndexedCommit struct {
+	Hash      string      `json:"h"`
+	Name      string      `json:"n,omitempty"`
+	Email     string      `json:"e"`           // 原始作者邮箱（未规范化）
+	CoAuthors []string    `json:"c,omitempty"` // Co-authored-by trailer 中的原始邮箱
+	When      int64       `json:"t"`           // 作者时间，Unix 秒
+	Offset    int         `json:"z,omitempty"` // 作者时区相对 UTC 的偏移，单位秒
+	Parents   []string    `json:"p,omitempty"`
+	Lines     *LineCounts `json:"l,omitempty"`  // 代码行统计，首次按需计算后写回
+	Files     []string    `json:"f,omitempty"`  // 变更文件（合并提交为相对每个父提交都有变更的文件），首次按需计算后写回
+	FilesDone bool        `json:"fd,omitempty"` // Files 是否已计算；干净合并等提交的变更文件可能为空
+}
+
+// CommitIndex 是单个仓库的提交索引，只记录与统计参数无关的提交元数据，
+// 因此任意时间范围、邮箱过滤都可以直接基于索引回答。
+// 仓库出现新提交时只需从最新的起点向下遍历到已索引的提交即可增量扩展。
+type CommitIndex struct {
+	Version   int             `json:"version"`
+	RepoPath  string          `json:"repo_path"`
+	Tips      []string        `json:"tips"` // 上次扩展时的起点 hash
+	Commits   []IndexedCommit `json:"commits"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// LoadIndex 读取仓库的提交索引。
+// 索引不存在时返回 os.ErrNotExist；版本不匹配视为不存在。
+func LoadIndex(repoPath string) (*CommitIndex, error) {
+	indexPath, err := getIndexPath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var idx CommitIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, err
+	}
+	if idx.Version != IndexVersion {
+		return nil, fmt.Errorf("index version %d: %w", idx.Version, os.ErrNotExist)
+	}
+	touch(indexPath)
+	return &idx, nil
+}
+
+// SaveIndex 将提交索引写入磁盘，写入策略与 SaveCache 相同（tmp + rename）。
+func SaveIndex(idx *CommitIndex) error {
+	indexPath, err := getIndexPath(idx.RepoPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0o700); err != nil {
+		return err
+	}
+
+	idx.Version = IndexVersion
+	idx.RepoPath = filepath.Clean(strings.TrimSpace(idx.RepoPath))
+	idx.UpdatedAt = time.Now().UTC()
+
+	// 索引可能包含数万条提交，不做缩进
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := indexPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, indexPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	enforceSizeLimit(indexPath)
+	return nil
+}
+
+// getIndexPath 返回仓库提交索引文件的完整路径，格式为 "index/{repoName}_{hash}.json"。
+func getIndexPath(repoPath string) (string, error) {
+	cacheDir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	cleaned := filepath.Clean(strings.TrimSpace(repoPath))
+	repoName := sanitizeFileComponent(filepath.Base(cleaned))
+	if repoName == "" {
+		repoName = "repo"
+	}
+	digest := sha256.Sum256([]byte(cleaned))
+	fileName := fmt.Sprintf("%s_%x.json", repoName, digest[:8])
+	return filepath.Join(cacheDir, "index", fileName), nil
+}
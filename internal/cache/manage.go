@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sizeLimit 是缓存目录总大小上限（字节），<=0 表示不限制。
+var sizeLimit atomic.Int64
+
+// evictMu 串行化同一进程内的淘汰过程，避免并发写入时重复删除。
+var evictMu sync.Mutex
+
+// SetSizeLimit 设置缓存目录（结果缓存 + 提交索引）的总大小上限，<=0 表示不限制。
+// 超出上限时，SaveCache/SaveIndex 会按最近使用时间淘汰最旧的文件。
+func SetSizeLimit(bytes int64) {
+	sizeLimit.Store(bytes)
+}
+
+// FileInfo 描述缓存目录中的一个文件（结果缓存条目或提交索引）。
+type FileInfo struct {
+	Path     string
+	RepoPath string    // 所属仓库，文件无法解析时为空
+	Size     int64     // 文件大小（字节）
+	LastUsed time.Time // 最近一次读写时间（文件 mtime），LRU 淘汰依据
+	Index    bool      // 是否为提交索引
+	Entry    *CacheEntry
+}
+
+// Dir 返回缓存目录的路径 (~/.config/git-visible/cache)。
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git-visible", "cache"), nil
+}
+
+// List 列出缓存目录中的全部结果缓存条目与提交索引。
+// 缓存目录不存在时返回空列表；无法解析的文件也会列出（RepoPath 为空、Entry 为 nil），
+// 便于调用方清理。
+func List() ([]FileInfo, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := scanCacheFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		f := &files[i]
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			continue
+		}
+		if f.Index {
+			var header struct {
+				RepoPath string `json:"repo_path"`
+			}
+			if json.Unmarshal(data, &header) == nil {
+				f.RepoPath = header.RepoPath
+			}
+			continue
+		}
+		var entry CacheEntry
+		if json.Unmarshal(data, &entry) == nil {
+			f.RepoPath = entry.Key.RepoPath
+			f.Entry = &entry
+		}
+	}
+	return files, nil
+}
+
+// Remove 删除一个缓存文件，文件不存在不视为错误。
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// touch 更新文件的访问/修改时间，记录缓存命中以供 LRU 使用。
+func touch(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// enforceSizeLimit 在缓存目录超出上限时按最近使用时间从旧到新淘汰文件。
+// keep 为刚写入的文件，即使单独超过上限也不会被淘汰。
+func enforceSizeLimit(keep string) {
+	limit := sizeLimit.Load()
+	if limit <= 0 {
+		return
+	}
+
+	evictMu.Lock()
+	defer evictMu.Unlock()
+
+	dir, err := Dir()
+	if err != nil {
+		return
+	}
+	files, err := scanCacheFiles(dir)
+	if err != nil {
+		return
+	}
+
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+	if total <= limit {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].LastUsed.Before(files[j].LastUsed)
+	})
+	for _, f := range files {
+		if total <= limit {
+			break
+		}
+		if f.Path == keep {
+			continue
+		}
+		if Remove(f.Path) == nil {
+			total -= f.Size
+		}
+	}
+}
+
+// scanCacheFiles 收集缓存目录（含 index 子目录）中的 JSON 文件，不解析内容。
+func scanCacheFiles(dir string) ([]FileInfo, error) {
+	files := make([]FileInfo, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, FileInfo{
+			Path:     path,
+			Size:     info.Size(),
+			LastUsed: info.ModTime(),
+			Index:    filepath.Base(filepath.Dir(path)) == "index",
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
@@ -13,11 +15,64 @@ import (
 // DefaultMonths 是默认的统计月份数。
 const DefaultMonths = 6
 
+// EmailAuto 是 email 的特殊取值，表示从 git 配置（user.email）自动识别"我"的邮箱。
+const EmailAuto = "auto"
+
 // Config 表示应用配置。
 type Config struct {
 	Months  int     `mapstructure:"months" yaml:"months"`   // 默认统计的月份数
-	Email   string  `mapstructure:"email" yaml:"email"`     // 默认的邮箱过滤条件
+	Email   string  `mapstructure:"email" yaml:"email"`     // 默认的邮箱过滤条件，"auto" 表示从 git 配置识别
 	Aliases []Alias `mapstructure:"aliases" yaml:"aliases"` // 作者身份别名映射
+	// CacheMaxMB 是缓存目录的大小上限（MB），超出时按 LRU 淘汰，0 表示不限制。
+	CacheMaxMB int `mapstructure:"cache_max_mb" yaml:"cache_max_mb"`
+	// CoAuthors 为 true 时默认计入提交信息中 Co-authored-by trailer 的共同作者。
+	CoAuthors bool `mapstructure:"co_authors" yaml:"co_authors"`
+	// MailmapFile 是全局 mailmap 文件路径（同 git 的 mailmap.file），支持 "~/" 开头。
+	MailmapFile string `mapstructure:"mailmap_file" yaml:"mailmap_file"`
+	// SVG 是 show --format svg 的样式配置。
+	SVG SVGConfig `mapstructure:"svg" yaml:"svg"`
+	// Theme 是热力图默认主题名称（内置主题或 themes 中的自定义主题），为空时使用 github。
+	Theme string `mapstructure:"theme" yaml:"theme"`
+	// Themes 是自定义主题，键为主题名称。
+	Themes map[string]ThemeColors `mapstructure:"themes" yaml:"themes"`
+	// Thresholds 是热力图档位划分：fixed（默认）、quantile 或 "1,5,10" 形式的三个递增下限。
+	Thresholds string `mapstructure:"thresholds" yaml:"thresholds"`
+	// WeekStart 是每周的第一天（如 monday、sunday），影响热力图行序与周对齐以及按周聚合；
+	// 为空时热力图从周日开始，按周聚合使用 ISO 8601 的周一。
+	WeekStart string `mapstructure:"week_start" yaml:"week_start"`
+	// Timezone 是按天划分提交与解析日期使用的时区：local（默认）、author（按各提交作者时区）或 IANA 名称。
+	Timezone string `mapstructure:"timezone" yaml:"timezone"`
+}
+
+// ThemeColors 定义自定义主题各档位的颜色（#rgb 或 #rrggbb），未设置的项沿用 github 主题。
+type ThemeColors struct {
+	Empty  string `mapstructure:"empty" yaml:"empty"`
+	Low    string `mapstructure:"low" yaml:"low"`
+	Medium string `mapstructure:"medium" yaml:"medium"`
+	High   string `mapstructure:"high" yaml:"high"`
+	Today  string `mapstructure:"today" yaml:"today"`
+}
+
+// SVGConfig 定义 SVG 热力图的单元格大小与配色，未设置的项使用内置默认值。
+type SVGConfig struct {
+	CellSize int       `mapstructure:"cell_size" yaml:"cell_size"`
+	Colors   SVGColors `mapstructure:"colors" yaml:"colors"`
+}
+
+// SVGColors 定义 SVG 热力图各元素的颜色（#rgb 或 #rrggbb）。
+type SVGColors struct {
+	Background string `mapstructure:"background" yaml:"background"`
+	Text       string `mapstructure:"text" yaml:"text"`
+	Empty      string `mapstructure:"empty" yaml:"empty"`
+	Low        string `mapstructure:"low" yaml:"low"`
+	Medium     string `mapstructure:"medium" yaml:"medium"`
+	High       string `mapstructure:"high" yaml:"high"`
+	Today      string `mapstructure:"today" yaml:"today"`
+}
+
+// IsZero 报告 SVG 配置是否全部为默认值。
+func (c SVGConfig) IsZero() bool {
+	return c == SVGConfig{}
 }
 
 // Alias 定义一个作者身份及其关联邮箱。
@@ -90,10 +145,31 @@ func Load() (*Config, error) {
 			return
 		}
 
+		var svg SVGConfig
+		if err := v.UnmarshalKey("svg", &svg); err != nil {
+			loadErr = err
+			return
+		}
+
+		var themes map[string]ThemeColors
+		if err := v.UnmarshalKey("themes", &themes); err != nil {
+			loadErr = err
+			return
+		}
+
 		instance = &Config{
-			Email:   v.GetString("email"),
-			Months:  v.GetInt("months"),
-			Aliases: aliases,
+			Email:       v.GetString("email"),
+			Months:      v.GetInt("months"),
+			Aliases:     aliases,
+			CacheMaxMB:  v.GetInt("cache_max_mb"),
+			CoAuthors:   v.GetBool("co_authors"),
+			MailmapFile: v.GetString("mailmap_file"),
+			SVG:         svg,
+			Theme:       v.GetString("theme"),
+			Themes:      themes,
+			Thresholds:  v.GetString("thresholds"),
+			WeekStart:   v.GetString("week_start"),
+			Timezone:    v.GetString("timezone"),
 		}
 	})
 
@@ -117,6 +193,27 @@ func Save(config Config) error {
 	v.Set("email", config.Email)
 	v.Set("months", config.Months)
 	v.Set("aliases", config.Aliases)
+	v.Set("cache_max_mb", config.CacheMaxMB)
+	v.Set("co_authors", config.CoAuthors)
+	v.Set("mailmap_file", config.MailmapFile)
+	if !config.SVG.IsZero() {
+		v.Set("svg", config.SVG)
+	}
+	if config.Theme != "" {
+		v.Set("theme", config.Theme)
+	}
+	if len(config.Themes) > 0 {
+		v.Set("themes", config.Themes)
+	}
+	if config.Thresholds != "" {
+		v.Set("thresholds", config.Thresholds)
+	}
+	if config.WeekStart != "" {
+		v.Set("week_start", config.WeekStart)
+	}
+	if config.Timezone != "" {
+		v.Set("timezone", config.Timezone)
+	}
 
 	// 将配置写入文件（viper 默认 0644，需手动修正权限）
 	if err := v.WriteConfigAs(configFile); err != nil {
@@ -162,7 +259,30 @@ func (c *Config) NormalizeEmail(email string) string {
 	return email
 }
 
+// AutoEmail 报告 email 是否配置为从 git 配置自动识别。
+func (c *Config) AutoEmail() bool {
+	return c != nil && strings.EqualFold(strings.TrimSpace(c.Email), EmailAuto)
+}
+
+// MailmapPath 返回展开 "~/" 后的全局 mailmap 路径，未配置时返回空字符串。
+func (c *Config) MailmapPath() string {
+	if c == nil {
+		return ""
+	}
+	p := strings.TrimSpace(c.MailmapFile)
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+	return p
+}
+
+// hexColorRegexp 匹配 #rgb 与 #rrggbb 形式的颜色。
+var hexColorRegexp = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
 // ValidateConfig 检查配置合法性，返回问题列表。
+// theme、thresholds、week_start 与 timezone 需要 stats 包的解析规则，由调用方（doctor）另行校验。
 func ValidateConfig(cfg *Config) []string {
 	if cfg == nil {
 		return []string{"config is nil"}
@@ -174,7 +294,49 @@ func ValidateConfig(cfg *Config) []string {
 		issues = append(issues, fmt.Sprintf("months must be > 0, got %d", cfg.Months))
 	}
 
-	if cfg.Email != "" {
+	if cfg.CacheMaxMB < 0 {
+		issues = append(issues, fmt.Sprintf("cache_max_mb must be >= 0, got %d", cfg.CacheMaxMB))
+	}
+
+	if cfg.SVG.CellSize < 0 {
+		issues = append(issues, fmt.Sprintf("svg.cell_size must be >= 0, got %d", cfg.SVG.CellSize))
+	}
+	colors := cfg.SVG.Colors
+	for _, c := range []struct{ name, value string }{
+		{"background", colors.Background},
+		{"text", colors.Text},
+		{"empty", colors.Empty},
+		{"low", colors.Low},
+		{"medium", colors.Medium},
+		{"high", colors.High},
+		{"today", colors.Today},
+	} {
+		if c.value != "" && !hexColorRegexp.MatchString(c.value) {
+			issues = append(issues, fmt.Sprintf("svg.colors.%s must be #rgb or #rrggbb, got %q", c.name, c.value))
+		}
+	}
+
+	themeNames := make([]string, 0, len(cfg.Themes))
+	for name := range cfg.Themes {
+		themeNames = append(themeNames, name)
+	}
+	sort.Strings(themeNames)
+	for _, name := range themeNames {
+		t := cfg.Themes[name]
+		for _, c := range []struct{ name, value string }{
+			{"empty", t.Empty},
+			{"low", t.Low},
+			{"medium", t.Medium},
+			{"high", t.High},
+			{"today", t.Today},
+		} {
+			if c.value != "" && !hexColorRegexp.MatchString(c.value) {
+				issues = append(issues, fmt.Sprintf("themes.%s.%s must be #rgb or #rrggbb, got %q", name, c.name, c.value))
+			}
+		}
+	}
+
+	if cfg.Email != "" && !cfg.AutoEmail() {
 		email := strings.TrimSpace(cfg.Email)
 		if !strings.Contains(email, "@") {
 			issues = append(issues, fmt.Sprintf("invalid email format: %q", cfg.Email))
@@ -34,6 +34,32 @@ func TestValidateConfig(t *testing.T) {
 		require.NotEmpty(t, issues)
 		assert.Contains(t, strings.Join(issues, "\n"), "invalid email format")
 	})
+
+	t.Run("email auto should pass", func(t *testing.T) {
+		for _, email := range []string{"auto", "AUTO", " auto "} {
+			assert.Empty(t, config.ValidateConfig(&config.Config{Months: 6, Email: email}), email)
+		}
+	})
+
+	t.Run("invalid svg color should fail", func(t *testing.T) {
+		issues := config.ValidateConfig(&config.Config{
+			Months: 6,
+			Email:  "test@example.com",
+			SVG:    config.SVGConfig{Colors: config.SVGColors{Low: "green", High: "#216e39"}},
+		})
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "svg.colors.low")
+	})
+
+	t.Run("invalid theme color should fail", func(t *testing.T) {
+		issues := config.ValidateConfig(&config.Config{
+			Months: 6,
+			Email:  "test@example.com",
+			Themes: map[string]config.ThemeColors{"ocean": {Low: "#caf0f8", High: "navy"}},
+		})
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "themes.ocean.high")
+	})
 }
 
 func TestCheckBranchReachability(t *testing.T) {
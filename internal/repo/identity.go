@@ -0,0 +1,291 @@
+package repo
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxIncludeDepth 是 include/includeIf 的最大嵌套层数（与 git 一致），防止循环引用。
+const maxIncludeDepth = 10
+
+// DetectedEmail 是从 git 配置中识别出的一个 user.email 及其来源。
+type DetectedEmail struct {
+	Email   string   // user.email 的值（保留原始大小写）
+	Sources []string // 提供该值的配置文件路径，按发现顺序去重
+}
+
+// DetectEmails 从 git 配置中识别"我"的邮箱：先取不在任何仓库中时生效的 user.email
+// （系统级与全局配置），再取每个仓库生效的 user.email（叠加仓库本地配置，并按仓库的 gitdir
+// 评估 includeIf）。邮箱按大小写不敏感去重，保留首次出现的写法。
+// 无法读取的配置文件视为不存在，与 git 的行为一致。
+func DetectEmails(repos []string) []DetectedEmail {
+	var out []DetectedEmail
+	add := func(email, source string) {
+		if email == "" {
+			return
+		}
+		for i := range out {
+			if strings.EqualFold(out[i].Email, email) {
+				for _, s := range out[i].Sources {
+					if s == source {
+						return
+					}
+				}
+				out[i].Sources = append(out[i].Sources, source)
+				return
+			}
+		}
+		out = append(out, DetectedEmail{Email: email, Sources: []string{source}})
+	}
+
+	add(effectiveEmail(""))
+	for _, repoPath := range repos {
+		add(effectiveEmail(repoPath))
+	}
+	return out
+}
+
+// effectiveEmail 返回在 repoPath 中生效的 user.email 及提供它的配置文件；repoPath 为空时只读取系统级与全局配置。
+// 读取顺序与 git 相同：系统级 → 全局（XDG 与 ~/.gitconfig）→ 仓库本地，后读到的值覆盖先读到的值。
+func effectiveEmail(repoPath string) (email, source string) {
+	gitDir := ""
+	if repoPath != "" {
+		gitDir = resolveGitDir(repoPath)
+	}
+
+	r := gitConfigReader{gitDir: gitDir}
+	for _, path := range gitConfigFiles(gitDir) {
+		r.read(path, 0)
+	}
+	return r.email, r.source
+}
+
+// gitConfigFiles 返回按优先级从低到高排列的配置文件路径，遵循 GIT_CONFIG_NOSYSTEM、
+// GIT_CONFIG_SYSTEM、GIT_CONFIG_GLOBAL 与 XDG_CONFIG_HOME 环境变量。
+func gitConfigFiles(gitDir string) []string {
+	var files []string
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		system := os.Getenv("GIT_CONFIG_SYSTEM")
+		if system == "" {
+			system = "/etc/gitconfig"
+		}
+		files = append(files, system)
+	}
+
+	if global := os.Getenv("GIT_CONFIG_GLOBAL"); global != "" {
+		files = append(files, global)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		xdg := os.Getenv("XDG_CONFIG_HOME")
+		if xdg == "" {
+			xdg = filepath.Join(home, ".config")
+		}
+		files = append(files, filepath.Join(xdg, "git", "config"), filepath.Join(home, ".gitconfig"))
+	}
+
+	if gitDir != "" {
+		files = append(files, filepath.Join(commonGitDir(gitDir), "config"))
+	}
+	return files
+}
+
+// commonGitDir 返回仓库本地配置所在的共享 git 目录：linked worktree 的 git 目录（.git/worktrees/<name>）
+// 中没有 config，由 commondir 文件指向主仓库的 git 目录；没有 commondir 时即为 gitDir 本身。
+func commonGitDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	dir := strings.TrimSpace(string(data))
+	if dir == "" {
+		return gitDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// resolveGitDir 返回仓库的 git 目录：.git 为目录时直接使用，为文件（worktree、submodule）时读取其中的 gitdir。
+// includeIf "gitdir:" 按该目录匹配，与 git 相同。
+func resolveGitDir(repoPath string) string {
+	dotGit := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil || info.IsDir() {
+		return dotGit
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return dotGit
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return dotGit
+	}
+	dir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// gitConfigReader 按出现顺序读取配置文件中的 user.email，并就地展开 include 与匹配的 includeIf。
+type gitConfigReader struct {
+	gitDir string // 用于评估 includeIf "gitdir:" 条件，为空时条件均不成立
+	email  string // 最后读到的 user.email
+	source string // 提供 email 的配置文件
+}
+
+// read 读取单个配置文件，文件不存在或不可读时忽略。
+func (r *gitConfigReader) read(path string, depth int) {
+	if depth > maxIncludeDepth {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	section, subsection := "", ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			section, subsection = parseSectionHeader(line)
+			continue
+		}
+
+		key, value := parseConfigEntry(line)
+		switch {
+		case section == "user" && subsection == "" && key == "email":
+			r.email, r.source = value, path
+		case key == "path" && section == "include" && subsection == "":
+			r.read(resolveIncludePath(value, path), depth+1)
+		case key == "path" && section == "includeif" && r.includeIfMatches(subsection, path):
+			r.read(resolveIncludePath(value, path), depth+1)
+		}
+	}
+}
+
+// includeIfMatches 评估 includeIf 条件，目前支持 gitdir: 与 gitdir/i:，其他条件视为不成立。
+func (r *gitConfigReader) includeIfMatches(cond, configPath string) bool {
+	if r.gitDir == "" {
+		return false
+	}
+	var pattern string
+	var fold bool
+	switch {
+	case strings.HasPrefix(cond, "gitdir:"):
+		pattern = strings.TrimPrefix(cond, "gitdir:")
+	case strings.HasPrefix(cond, "gitdir/i:"):
+		pattern, fold = strings.TrimPrefix(cond, "gitdir/i:"), true
+	default:
+		return false
+	}
+	re, err := gitdirPattern(pattern, configPath, fold)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(filepath.ToSlash(r.gitDir))
+}
+
+// gitdirPattern 将 includeIf 的 gitdir 模式转换为正则表达式，规则与 git 相同：
+// "~/" 展开为主目录，"./" 相对于当前配置文件所在目录，不以 "/" 开头时补 "**/"，
+// 以 "/" 结尾时补 "**"（匹配该目录下的所有仓库）。
+func gitdirPattern(pattern, configPath string, fold bool) (*regexp.Regexp, error) {
+	switch {
+	case strings.HasPrefix(pattern, "~/"):
+		if home, err := os.UserHomeDir(); err == nil {
+			pattern = filepath.ToSlash(home) + pattern[1:]
+		}
+	case strings.HasPrefix(pattern, "./"):
+		pattern = filepath.ToSlash(filepath.Dir(configPath)) + pattern[1:]
+	}
+	if !strings.HasPrefix(pattern, "/") && !filepath.IsAbs(pattern) {
+		pattern = "**/" + pattern
+	}
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	var b strings.Builder
+	if fold {
+		b.WriteString("(?i)")
+	}
+	b.WriteByte('^')
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteByte('$')
+	return regexp.Compile(b.String())
+}
+
+// resolveIncludePath 解析 include 路径："~/" 展开为主目录，相对路径相对于当前配置文件所在目录。
+func resolveIncludePath(p, configPath string) string {
+	if strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, p[2:])
+		}
+	}
+	if !filepath.IsAbs(p) {
+		return filepath.Join(filepath.Dir(configPath), p)
+	}
+	return p
+}
+
+// parseSectionHeader 解析 [section] 或 [section "subsection"]，节名转为小写，子节名保持原样。
+func parseSectionHeader(line string) (section, subsection string) {
+	line = strings.TrimPrefix(line, "[")
+	if i := strings.LastIndexByte(line, ']'); i >= 0 {
+		line = line[:i]
+	}
+	if i := strings.IndexByte(line, '"'); i >= 0 {
+		subsection = strings.TrimSuffix(line[i+1:], `"`)
+		subsection = strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(subsection)
+		line = line[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(line)), subsection
+}
+
+// parseConfigEntry 解析 key = value 行：键名转为小写，值去掉引号与行尾注释。
+func parseConfigEntry(line string) (key, value string) {
+	key, value, _ = strings.Cut(line, "=")
+	key = strings.ToLower(strings.TrimSpace(key))
+
+	var b strings.Builder
+	inQuote := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case c == '\\' && i+1 < len(value):
+			i++
+			b.WriteByte(value[i])
+		case (c == '#' || c == ';') && !inQuote:
+			return key, strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return key, strings.TrimSpace(b.String())
+}
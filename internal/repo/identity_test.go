@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withGitConfigHome 将 HOME 指向临时目录并屏蔽系统级与外部 XDG 配置，返回主目录。
+func withGitConfigHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	return home
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestDetectEmails_GlobalLocalAndIncludeIf(t *testing.T) {
+	home := withGitConfigHome(t)
+
+	global := filepath.Join(home, ".gitconfig")
+	workConfig := filepath.Join(home, ".gitconfig-work")
+	writeFile(t, global, `[user]
+	name = Me
+	email = me@personal.dev ; inline comment
+[includeIf "gitdir:~/work/"]
+	path = .gitconfig-work
+[includeIf "onbranch:main"]
+	path = ~/.gitconfig-never
+`)
+	writeFile(t, workConfig, "[user]\n\temail = \"me@corp.com\"\n")
+	writeFile(t, filepath.Join(home, ".gitconfig-never"), "[user]\n\temail = never@example.com\n")
+
+	workRepo := filepath.Join(home, "work", "api")
+	writeFile(t, filepath.Join(workRepo, ".git", "config"), "[core]\n\tbare = false\n")
+	ossRepo := filepath.Join(home, "oss", "lib")
+	localConfig := filepath.Join(ossRepo, ".git", "config")
+	writeFile(t, localConfig, "[user]\n\temail = Me@Personal.dev\n")
+	otherRepo := filepath.Join(home, "oss", "tool")
+	writeFile(t, filepath.Join(otherRepo, ".git", "config"), "[user]\n\temail = oss@example.org\n")
+
+	got := DetectEmails([]string{workRepo, ossRepo, otherRepo})
+	assert.Equal(t, []DetectedEmail{
+		{Email: "me@personal.dev", Sources: []string{global, localConfig}},
+		{Email: "me@corp.com", Sources: []string{workConfig}},
+		{Email: "oss@example.org", Sources: []string{filepath.Join(otherRepo, ".git", "config")}},
+	}, got)
+}
+
+func TestDetectEmails_LinkedWorktreeUsesCommonConfig(t *testing.T) {
+	home := withGitConfigHome(t)
+
+	// 主仓库的本地配置通过 include 引入 user.email
+	mainGitDir := filepath.Join(home, "oss", "lib", ".git")
+	teamConfig := filepath.Join(mainGitDir, "team.gitconfig")
+	writeFile(t, filepath.Join(mainGitDir, "config"), "[include]\n\tpath = team.gitconfig\n")
+	writeFile(t, teamConfig, "[user]\n\temail = me@team.dev\n")
+
+	// linked worktree：.git 文件指向 .git/worktrees/feature，其中只有 commondir 没有 config
+	wtGitDir := filepath.Join(mainGitDir, "worktrees", "feature")
+	writeFile(t, filepath.Join(wtGitDir, "commondir"), "../..\n")
+	worktree := filepath.Join(home, "lib-feature")
+	writeFile(t, filepath.Join(worktree, ".git"), "gitdir: "+wtGitDir+"\n")
+
+	got := DetectEmails([]string{worktree})
+	assert.Equal(t, []DetectedEmail{{Email: "me@team.dev", Sources: []string{teamConfig}}}, got)
+}
+
+func TestDetectEmails_XDGAndEnvOverrides(t *testing.T) {
+	home := withGitConfigHome(t)
+
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	writeFile(t, filepath.Join(xdg, "git", "config"), "[user]\n\temail = xdg@example.com\n")
+	got := DetectEmails(nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, "xdg@example.com", got[0].Email)
+
+	// ~/.gitconfig 在 XDG 配置之后读取，优先级更高
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\temail = home@example.com\n")
+	got = DetectEmails(nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, "home@example.com", got[0].Email)
+
+	override := filepath.Join(home, "custom.gitconfig")
+	writeFile(t, override, "[include]\n\tpath = nested.gitconfig\n")
+	writeFile(t, filepath.Join(home, "nested.gitconfig"), "[user]\n\temail = nested@example.com\n")
+	t.Setenv("GIT_CONFIG_GLOBAL", override)
+	got = DetectEmails(nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, "nested@example.com", got[0].Email)
+	assert.Equal(t, []string{filepath.Join(home, "nested.gitconfig")}, got[0].Sources)
+}
+
+func TestDetectEmails_NothingConfigured(t *testing.T) {
+	withGitConfigHome(t)
+
+	assert.Empty(t, DetectEmails([]string{t.TempDir()}))
+}
+
+func TestDetectEmails_IncludeCycleStops(t *testing.T) {
+	home := withGitConfigHome(t)
+
+	writeFile(t, filepath.Join(home, ".gitconfig"), "[user]\n\temail = me@example.com\n[include]\n\tpath = ~/.gitconfig\n")
+	got := DetectEmails(nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, "me@example.com", got[0].Email)
+}
+
+func TestGitdirPattern(t *testing.T) {
+	home := withGitConfigHome(t)
+
+	tests := []struct {
+		pattern string
+		fold    bool
+		gitDir  string
+		want    bool
+	}{
+		{"~/work/", false, home + "/work/api/.git", true},
+		{"~/work/", false, home + "/personal/api/.git", false},
+		{"work/", false, "/src/work/api/.git", true},
+		{"/src/*/api/.git", false, "/src/x/api/.git", true},
+		{"/src/*/api/.git", false, "/src/x/y/api/.git", false},
+		{"/SRC/", true, "/src/api/.git", true},
+		{"/SRC/", false, "/src/api/.git", false},
+		{"./sub/", false, home + "/sub/repo/.git", true},
+	}
+	for _, tt := range tests {
+		re, err := gitdirPattern(tt.pattern, filepath.Join(home, ".gitconfig"), tt.fold)
+		require.NoError(t, err, tt.pattern)
+		assert.Equal(t, tt.want, re.MatchString(tt.gitDir), "%s vs %s", tt.pattern, tt.gitDir)
+	}
+}
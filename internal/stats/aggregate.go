@@ -0,0 +1,156 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Granularity 表示聚合输出的时间粒度。
+type Granularity int
+
+const (
+	// GranularityDay 按天输出（默认）。
+	GranularityDay Granularity = iota
+	// GranularityWeek 按周聚合。
+	GranularityWeek
+	// GranularityMonth 按自然月聚合。
+	GranularityMonth
+	// GranularityQuarter 按自然季度聚合。
+	GranularityQuarter
+)
+
+// granularityNames 是各粒度的名称，下标与 Granularity 取值一致。
+var granularityNames = [...]string{"day", "week", "month", "quarter"}
+
+// ParseGranularity 解析粒度名称（大小写不敏感），空字符串为 day。
+func ParseGranularity(s string) (Granularity, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return GranularityDay, nil
+	}
+	for i, name := range granularityNames {
+		if s == name {
+			return Granularity(i), nil
+		}
+	}
+	return GranularityDay, fmt.Errorf("unsupported granularity %q (supported: %s)", s, strings.Join(granularityNames[:], ", "))
+}
+
+// String 返回粒度名称。
+func (g Granularity) String() string {
+	if g < 0 || int(g) >= len(granularityNames) {
+		return granularityNames[GranularityDay]
+	}
+	return granularityNames[g]
+}
+
+// ParseWeekStart 解析一周的起始日（如 monday / sunday，大小写不敏感，支持三字母缩写）。
+func ParseWeekStart(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			return wd, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid week start %q (expected a weekday like monday or sunday)", s)
+}
+
+// Bucket 是一个聚合时间段的统计结果。
+type Bucket struct {
+	Label      string    // 时间段标签：2025-06-02 / 2025-W23 / 2025-06 / 2025-Q2
+	Start      time.Time // 时间段第一天（自然边界，可能早于统计起点）
+	End        time.Time // 时间段最后一天
+	Activity   DayActivity
+	ActiveDays int
+}
+
+// AggregateActivity 将 [start, end] 内的按天统计按粒度聚合，返回按时间排序、包含空时间段的全部桶。
+// 周粒度以 weekStart 为每周第一天，标签为 ISO 8601 周号（取该周周四所在的 ISO 周，
+// 周日开始的周与其后的周一同号）。
+func AggregateActivity(activity map[time.Time]DayActivity, start, end time.Time, g Granularity, weekStart time.Weekday) []Bucket {
+	if start.IsZero() || end.IsZero() || start.After(end) {
+		return nil
+	}
+	loc := end.Location()
+	start = beginningOfDay(start, loc)
+	end = beginningOfDay(end, loc)
+
+	var buckets []Bucket
+	for from := bucketStart(start, g, weekStart); !from.After(end); {
+		next := bucketNext(from, g)
+		b := Bucket{Label: bucketLabel(from, g, weekStart), Start: from, End: next.AddDate(0, 0, -1)}
+		for d := maxTime(from, start); d.Before(next) && !d.After(end); d = d.AddDate(0, 0, 1) {
+			a := activity[d]
+			b.Activity = b.Activity.Add(a)
+			if a.Commits > 0 {
+				b.ActiveDays++
+			}
+		}
+		buckets = append(buckets, b)
+		from = next
+	}
+	return buckets
+}
+
+// bucketStart 返回 day 所在时间段的第一天。
+func bucketStart(day time.Time, g Granularity, weekStart time.Weekday) time.Time {
+	loc := day.Location()
+	switch g {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+	case GranularityQuarter:
+		return time.Date(day.Year(), (day.Month()-1)/3*3+1, 1, 0, 0, 0, 0, loc)
+	default:
+		return day
+	}
+}
+
+// bucketNext 返回下一个时间段的第一天。
+func bucketNext(from time.Time, g Granularity) time.Time {
+	switch g {
+	case GranularityWeek:
+		return from.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return from.AddDate(0, 1, 0)
+	case GranularityQuarter:
+		return from.AddDate(0, 3, 0)
+	default:
+		return from.AddDate(0, 0, 1)
+	}
+}
+
+// bucketLabel 返回时间段标签。
+func bucketLabel(from time.Time, g Granularity, weekStart time.Weekday) string {
+	switch g {
+	case GranularityWeek:
+		// 取该周内的周四确定 ISO 周号，周一开始时与 ISO 8601 完全一致
+		offset := (int(time.Thursday) - int(weekStart) + 7) % 7
+		year, week := from.AddDate(0, 0, offset).ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case GranularityMonth:
+		return from.Format("2006-01")
+	case GranularityQuarter:
+		return fmt.Sprintf("%d-Q%d", from.Year(), (int(from.Month())-1)/3+1)
+	default:
+		return from.Format("2006-01-02")
+	}
+}
+
+// maxTime 返回较晚的时间。
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// WeekRange 返回 day 所在周（以 weekStart 为第一天）的第一天与最后一天。
+func WeekRange(day time.Time, weekStart time.Weekday) (start, end time.Time) {
+	start = bucketStart(beginningOfDay(day, day.Location()), GranularityWeek, weekStart)
+	return start, start.AddDate(0, 0, 6)
+}
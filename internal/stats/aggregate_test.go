@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGranularityAndWeekStart(t *testing.T) {
+	for in, want := range map[string]Granularity{"": GranularityDay, "Week": GranularityWeek, " month ": GranularityMonth, "quarter": GranularityQuarter} {
+		g, err := ParseGranularity(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, g, in)
+	}
+	_, err := ParseGranularity("year")
+	assert.ErrorContains(t, err, "unsupported granularity")
+
+	for in, want := range map[string]time.Weekday{"monday": time.Monday, "Sun": time.Sunday, "SATURDAY": time.Saturday} {
+		wd, err := ParseWeekStart(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, wd, in)
+	}
+	_, err = ParseWeekStart("mo")
+	assert.ErrorContains(t, err, "invalid week start")
+}
+
+func TestAggregateActivity_ISOWeeks(t *testing.T) {
+	loc := time.UTC
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+	activity := map[time.Time]DayActivity{
+		day(2024, 12, 30): {Commits: 2}, // 周一，ISO 2025-W01
+		day(2025, 1, 1):   {Commits: 3, Lines: LineTotals{Additions: 5}},
+		day(2025, 1, 13):  {Commits: 1},
+	}
+
+	buckets := AggregateActivity(activity, day(2025, 1, 1), day(2025, 1, 15), GranularityWeek, time.Monday)
+	require.Len(t, buckets, 3)
+	assert.Equal(t, "2025-W01", buckets[0].Label)
+	assert.Equal(t, day(2024, 12, 30), buckets[0].Start)
+	assert.Equal(t, day(2025, 1, 5), buckets[0].End)
+	// 统计起点之前的天不计入
+	assert.Equal(t, 3, buckets[0].Activity.Commits)
+	assert.Equal(t, 5, buckets[0].Activity.Lines.Additions)
+	assert.Equal(t, 1, buckets[0].ActiveDays)
+	assert.Equal(t, "2025-W02", buckets[1].Label)
+	assert.Zero(t, buckets[1].Activity.Commits)
+	assert.Equal(t, "2025-W03", buckets[2].Label)
+	assert.Equal(t, 1, buckets[2].Activity.Commits)
+
+	// 周日开始：2025-01-12（周日）与其后的周一同属 W03
+	buckets = AggregateActivity(activity, day(2025, 1, 12), day(2025, 1, 18), GranularityWeek, time.Sunday)
+	require.Len(t, buckets, 1)
+	assert.Equal(t, "2025-W03", buckets[0].Label)
+	assert.Equal(t, day(2025, 1, 12), buckets[0].Start)
+	assert.Equal(t, day(2025, 1, 18), buckets[0].End)
+}
+
+func TestAggregateActivity_MonthsAndQuarters(t *testing.T) {
+	loc := time.UTC
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+	activity := map[time.Time]DayActivity{
+		day(2025, 2, 10): {Commits: 2},
+		day(2025, 4, 1):  {Commits: 4},
+	}
+
+	months := AggregateActivity(activity, day(2025, 1, 15), day(2025, 4, 2), GranularityMonth, time.Monday)
+	labels := make([]string, 0, len(months))
+	for _, b := range months {
+		labels = append(labels, b.Label)
+	}
+	assert.Equal(t, []string{"2025-01", "2025-02", "2025-03", "2025-04"}, labels)
+	assert.Equal(t, day(2025, 2, 28), months[1].End)
+	assert.Equal(t, 2, months[1].Activity.Commits)
+
+	quarters := AggregateActivity(activity, day(2025, 1, 15), day(2025, 4, 2), GranularityQuarter, time.Monday)
+	require.Len(t, quarters, 2)
+	assert.Equal(t, "2025-Q1", quarters[0].Label)
+	assert.Equal(t, day(2025, 3, 31), quarters[0].End)
+	assert.Equal(t, "2025-Q2", quarters[1].Label)
+	assert.Equal(t, 4, quarters[1].Activity.Commits)
+
+	assert.Nil(t, AggregateActivity(activity, day(2025, 5, 1), day(2025, 4, 1), GranularityMonth, time.Monday))
+}
+
+func TestRenderTrend(t *testing.T) {
+	buckets := []Bucket{
+		{Label: "2025-01", Activity: DayActivity{Commits: 10}},
+		{Label: "2025-02", Activity: DayActivity{Commits: 0}},
+		{Label: "2025-03", Activity: DayActivity{Commits: 5}},
+	}
+
+	out := RenderTrend(buckets, GranularityMonth, TrendOptions{ShowSummary: true})
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, "2025-01 "+strings.Repeat("█", 40)+" 10", lines[0])
+	assert.Equal(t, "2025-02 "+strings.Repeat(" ", 40)+"  0", lines[1])
+	assert.Equal(t, "2025-03 "+strings.Repeat("█", 20)+strings.Repeat(" ", 20)+"  5", lines[2])
+	assert.Contains(t, out, "Trend: █▁▄\n")
+	assert.Contains(t, out, "Total: 15 commits │ Average: 5.0 per month │ Peak: 2025-01 (10 commits)\n")
+
+	// 窄终端时柱状条随宽度缩短，但至少保留 10 列
+	out = RenderTrend(buckets, GranularityMonth, TrendOptions{Charset: CharsetASCII, Width: 30})
+	assert.True(t, strings.HasPrefix(out, "2025-01 "+strings.Repeat("#", 19)+" 10\n"))
+	assert.Contains(t, out, "Trend: #_-\n")
+	assert.NotContains(t, out, "Total:")
+
+	assert.Empty(t, RenderTrend(nil, GranularityWeek, TrendOptions{}))
+}
+
+func TestWeekRange(t *testing.T) {
+	friday := time.Date(2025, 3, 14, 15, 30, 0, 0, time.UTC)
+
+	start, end := WeekRange(friday, time.Monday)
+	assert.Equal(t, "2025-03-10", start.Format("2006-01-02"))
+	assert.Equal(t, "2025-03-16", end.Format("2006-01-02"))
+	assert.Zero(t, start.Hour(), "start is normalized to the beginning of the day")
+
+	start, end = WeekRange(friday, time.Sunday)
+	assert.Equal(t, "2025-03-09", start.Format("2006-01-02"))
+	assert.Equal(t, "2025-03-15", end.Format("2006-01-02"))
+}
@@ -0,0 +1,309 @@
+package stats
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// AuthorIdentity 是一个不同的作者身份（姓名 + 邮箱）及其提交概况。
+type AuthorIdentity struct {
+	Name      string    `json:"name"`  // 经 mailmap 映射后的作者姓名
+	Email     string    `json:"email"` // 经 mailmap 映射后的作者邮箱（不应用别名，保留原始大小写）
+	Commits   int       `json:"commits"`
+	FirstSeen time.Time `json:"firstSeen"` // 最早的作者时间
+	LastSeen  time.Time `json:"lastSeen"`  // 最晚的作者时间
+	Repos     int       `json:"repos"`     // 出现过的仓库数
+}
+
+// AliasSuggestion 是一组疑似属于同一个人的邮箱。
+type AliasSuggestion struct {
+	Name    string   `json:"name"`    // 建议的别名组名称（提交最多的姓名）
+	Emails  []string `json:"emails"`  // 主邮箱在前，按大小写不敏感去重
+	Reasons []string `json:"reasons"` // 归为一组的依据：same name / GitHub noreply / case variant
+	Commits int      `json:"commits"` // 组内身份的提交总数
+}
+
+// 建议别名组的归组依据。
+const (
+	reasonSameName     = "same name"
+	reasonNoreply      = "GitHub noreply"
+	reasonCaseVariants = "case variant"
+)
+
+// githubNoreplyRegexp 匹配 GitHub noreply 地址（"login@" 或 "12345+login@users.noreply.github.com"）。
+var githubNoreplyRegexp = regexp.MustCompile(`(?i)^(?:\d+\+)?([^@+]+)@users\.noreply\.github\.com$`)
+
+// GitHubNoreplyLogin 返回 GitHub noreply 地址中的登录名（小写）；不是 noreply 地址时 ok 为 false。
+func GitHubNoreplyLogin(email string) (login string, ok bool) {
+	m := githubNoreplyRegexp.FindStringSubmatch(strings.TrimSpace(email))
+	if m == nil {
+		return "", false
+	}
+	return strings.ToLower(m[1]), true
+}
+
+// authorKey 是作者身份的去重键（姓名与邮箱均区分大小写，大小写变体由 SuggestAliases 归组）。
+type authorKey struct {
+	name, email string
+}
+
+// CollectAuthors 并发遍历各仓库，返回时间范围内出现过的全部作者身份（按提交数从多到少）。
+// 身份只经 mailmap 映射、不应用别名，以便发现尚未配置别名的邮箱；
+// 始终直接读取提交对象，不使用结果缓存。
+// 单个仓库失败时跳过并继续，返回已完成的仓库与聚合错误。
+func CollectAuthors(opts CollectOptions) ([]AuthorIdentity, []string, error) {
+	merged := make(map[authorKey]*AuthorIdentity)
+	done, err := collectCommonGeneric(opts, collectRepoAuthors, func(_ string, repoAuthors map[authorKey]*AuthorIdentity) {
+		for key, a := range repoAuthors {
+			m, ok := merged[key]
+			if !ok {
+				copied := *a
+				merged[key] = &copied
+				continue
+			}
+			m.Commits += a.Commits
+			m.Repos += a.Repos
+			if a.FirstSeen.Before(m.FirstSeen) {
+				m.FirstSeen = a.FirstSeen
+			}
+			if a.LastSeen.After(m.LastSeen) {
+				m.LastSeen = a.LastSeen
+			}
+		}
+	})
+
+	authors := make([]AuthorIdentity, 0, len(merged))
+	for _, a := range merged {
+		authors = append(authors, *a)
+	}
+	SortAuthors(authors)
+	return authors, done, err
+}
+
+// SortAuthors 将作者按提交数从多到少排序，相同时按邮箱与姓名排序，保证输出稳定。
+func SortAuthors(authors []AuthorIdentity) {
+	sort.Slice(authors, func(i, j int) bool {
+		a, b := authors[i], authors[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		if a.Email != b.Email {
+			return a.Email < b.Email
+		}
+		return a.Name < b.Name
+	})
+}
+
+// collectRepoAuthors 统计单个仓库中每个作者身份的提交数与首末出现时间。
+func collectRepoAuthors(repoPath string, q repoQuery) (map[authorKey]*AuthorIdentity, error) {
+	repo, err := openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make(map[authorKey]*AuthorIdentity)
+	err = walkRepoCommits(repo, repoPath, q, func(_ []string, _ int, c *object.Commit) error {
+		name, email := q.mailmap.Resolve(c.Author.Name, c.Author.Email)
+		key := authorKey{name: strings.TrimSpace(name), email: strings.TrimSpace(email)}
+		when := c.Author.When.In(q.dayLocation(c.Author.When))
+
+		a, ok := authors[key]
+		if !ok {
+			authors[key] = &AuthorIdentity{Name: key.name, Email: key.email, Commits: 1, FirstSeen: when, LastSeen: when, Repos: 1}
+			return nil
+		}
+		a.Commits++
+		if when.Before(a.FirstSeen) {
+			a.FirstSeen = when
+		}
+		if when.After(a.LastSeen) {
+			a.LastSeen = when
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// SuggestAliases 将疑似属于同一个人的作者身份归为建议的别名组：
+// 姓名相同（忽略大小写与多余空白）而邮箱不同、GitHub noreply 地址的登录名与其他身份的姓名或邮箱用户名相同、
+// 邮箱仅大小写不同。只返回包含至少两个不同邮箱（区分大小写）的组，按提交总数从多到少排列。
+func SuggestAliases(authors []AuthorIdentity) []AliasSuggestion {
+	n := len(authors)
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	reasons := make(map[int]map[string]struct{}) // 以并查集根为键，合并时一并迁移
+	union := func(i, j int, reason string) {
+		ri, rj := find(i), find(j)
+		if ri != rj {
+			parent[rj] = ri
+			for r := range reasons[rj] {
+				addReason(reasons, ri, r)
+			}
+			delete(reasons, rj)
+		}
+		if reason != "" {
+			addReason(reasons, ri, reason)
+		}
+	}
+
+	byName := make(map[string]int)
+	byEmail := make(map[string]int)
+	byLocal := make(map[string][]int)
+	for i, a := range authors {
+		email := strings.ToLower(a.Email)
+		// 同一邮箱的不同姓名本就是同一身份，只合并不记依据
+		if j, ok := byEmail[email]; !ok {
+			byEmail[email] = i
+		} else if authors[j].Email != a.Email {
+			union(j, i, reasonCaseVariants)
+		} else {
+			union(j, i, "")
+		}
+		if name := normalizeAuthorName(a.Name); name != "" {
+			if j, ok := byName[name]; ok && !strings.EqualFold(authors[j].Email, a.Email) {
+				union(j, i, reasonSameName)
+			} else if !ok {
+				byName[name] = i
+			}
+		}
+		if local, _, ok := strings.Cut(email, "@"); ok {
+			byLocal[local] = append(byLocal[local], i)
+		}
+	}
+	for i, a := range authors {
+		login, ok := GitHubNoreplyLogin(a.Email)
+		if !ok {
+			continue
+		}
+		if j, ok := byName[login]; ok && find(j) != find(i) {
+			union(j, i, reasonNoreply)
+		}
+		for _, j := range byLocal[login] {
+			if _, noreply := GitHubNoreplyLogin(authors[j].Email); !noreply && find(j) != find(i) {
+				union(j, i, reasonNoreply)
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range authors {
+		r := find(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], i)
+	}
+
+	var suggestions []AliasSuggestion
+	for _, r := range roots {
+		members := make([]AuthorIdentity, 0, len(groups[r]))
+		for _, i := range groups[r] {
+			members = append(members, authors[i])
+		}
+		if s, ok := buildAliasSuggestion(members, reasons[r]); ok {
+			suggestions = append(suggestions, s)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Commits != suggestions[j].Commits {
+			return suggestions[i].Commits > suggestions[j].Commits
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	return suggestions
+}
+
+// addReason 记录并查集根 root 所在组的一条归组依据。
+func addReason(reasons map[int]map[string]struct{}, root int, reason string) {
+	if reasons[root] == nil {
+		reasons[root] = make(map[string]struct{})
+	}
+	reasons[root][reason] = struct{}{}
+}
+
+// buildAliasSuggestion 由同组身份构建建议：名称取提交最多的姓名；主邮箱取提交最多的非 noreply 邮箱，
+// 其余邮箱按提交数排列，noreply 地址排在最后。组内不同邮箱（区分大小写）少于两个时返回 false。
+func buildAliasSuggestion(members []AuthorIdentity, reasons map[string]struct{}) (AliasSuggestion, bool) {
+	commitsByEmail := make(map[string]int)
+	commitsByName := make(map[string]int)
+	total := 0
+	for _, m := range members {
+		commitsByEmail[m.Email] += m.Commits
+		if m.Name != "" {
+			commitsByName[m.Name] += m.Commits
+		}
+		total += m.Commits
+	}
+	if len(commitsByEmail) < 2 {
+		return AliasSuggestion{}, false
+	}
+
+	emails := make([]string, 0, len(commitsByEmail))
+	for email := range commitsByEmail {
+		emails = append(emails, email)
+	}
+	sort.Slice(emails, func(i, j int) bool {
+		_, ni := GitHubNoreplyLogin(emails[i])
+		_, nj := GitHubNoreplyLogin(emails[j])
+		if ni != nj {
+			return !ni
+		}
+		if commitsByEmail[emails[i]] != commitsByEmail[emails[j]] {
+			return commitsByEmail[emails[i]] > commitsByEmail[emails[j]]
+		}
+		return emails[i] < emails[j]
+	})
+	// 大小写变体只保留首个写法：别名匹配本身大小写不敏感
+	deduped := emails[:0]
+	seen := make(map[string]struct{}, len(emails))
+	for _, email := range emails {
+		key := strings.ToLower(email)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, email)
+	}
+
+	name, best := "", -1
+	for n, c := range commitsByName {
+		if c > best || (c == best && n < name) {
+			name, best = n, c
+		}
+	}
+	if name == "" {
+		name = deduped[0]
+	}
+
+	// 组内出现 noreply 地址时总是注明，即使它是因姓名相同归入的
+	_, hasNoreply := GitHubNoreplyLogin(deduped[len(deduped)-1])
+	s := AliasSuggestion{Name: name, Emails: deduped, Commits: total}
+	for _, r := range []string{reasonSameName, reasonNoreply, reasonCaseVariants} {
+		if _, ok := reasons[r]; ok || (r == reasonNoreply && hasNoreply) {
+			s.Reasons = append(s.Reasons, r)
+		}
+	}
+	return s, true
+}
+
+// normalizeAuthorName 返回用于比较的姓名：小写并合并空白。
+func normalizeAuthorName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
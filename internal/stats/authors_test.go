@@ -0,0 +1,144 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectAuthors_CountsPerIdentityAcrossRepos(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	repoA := t.TempDir()
+	wtA := worktree(t, initRepo(t, repoA))
+	commitAs(t, wtA, repoA, "a.txt", "Alice", "alice@company.com", day.Add(9*time.Hour))
+	commitAs(t, wtA, repoA, "b.txt", "Alice", "alice@company.com", day.AddDate(0, 0, 2).Add(9*time.Hour))
+	commitAs(t, wtA, repoA, "c.txt", "Bob", "bob@example.com", day.Add(10*time.Hour))
+
+	repoB := t.TempDir()
+	wtB := worktree(t, initRepo(t, repoB))
+	commitAs(t, wtB, repoB, "a.txt", "Alice", "alice@company.com", day.AddDate(0, 0, -5).Add(9*time.Hour))
+	commitAs(t, wtB, repoB, "b.txt", "Alice", "alice@gmail.com", day.Add(11*time.Hour))
+	// 超出时间范围的提交不计入
+	commitAs(t, wtB, repoB, "c.txt", "Carol", "carol@example.com", day.AddDate(-2, 0, 0))
+
+	authors, done, err := CollectAuthors(CollectOptions{
+		Repos: []string{repoA, repoB},
+		Since: day.AddDate(0, 0, -10),
+		Until: day.AddDate(0, 0, 10),
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{repoA, repoB}, done)
+	require.Len(t, authors, 3)
+
+	alice := authors[0]
+	assert.Equal(t, "Alice", alice.Name)
+	assert.Equal(t, "alice@company.com", alice.Email)
+	assert.Equal(t, 3, alice.Commits)
+	assert.Equal(t, 2, alice.Repos)
+	assert.Equal(t, "2025-03-09", alice.FirstSeen.Format("2006-01-02"))
+	assert.Equal(t, "2025-03-16", alice.LastSeen.Format("2006-01-02"))
+
+	assert.Equal(t, "alice@gmail.com", authors[1].Email)
+	assert.Equal(t, "bob@example.com", authors[2].Email)
+	assert.Equal(t, 1, authors[2].Repos)
+}
+
+func TestCollectAuthors_AppliesMailmap(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	repoPath := t.TempDir()
+	wt := worktree(t, initRepo(t, repoPath))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, ".mailmap"), []byte("Alice Smith <alice@company.com> <alice@old.com>\n"), 0o644))
+	commitAs(t, wt, repoPath, "a.txt", "alice", "alice@old.com", day.Add(9*time.Hour))
+	commitAs(t, wt, repoPath, "b.txt", "Alice Smith", "alice@company.com", day.Add(10*time.Hour))
+
+	authors, _, err := CollectAuthors(CollectOptions{Repos: []string{repoPath}, Since: day, Until: day})
+	require.NoError(t, err)
+	require.Len(t, authors, 1)
+	assert.Equal(t, AuthorIdentity{
+		Name:      "Alice Smith",
+		Email:     "alice@company.com",
+		Commits:   2,
+		FirstSeen: authors[0].FirstSeen,
+		LastSeen:  authors[0].LastSeen,
+		Repos:     1,
+	}, authors[0])
+	assert.Equal(t, 9, authors[0].FirstSeen.Hour())
+	assert.Equal(t, 10, authors[0].LastSeen.Hour())
+}
+
+func TestSuggestAliases(t *testing.T) {
+	authors := []AuthorIdentity{
+		{Name: "Alice Smith", Email: "alice@company.com", Commits: 30},
+		{Name: "alice  smith", Email: "alice@gmail.com", Commits: 5},
+		{Name: "Alice Smith", Email: "1234+asmith@users.noreply.github.com", Commits: 8},
+		{Name: "Bob", Email: "bob@example.com", Commits: 20},
+		{Name: "Bob", Email: "Bob@Example.com", Commits: 2},
+		{Name: "octo", Email: "octo@home.dev", Commits: 4},
+		{Name: "The Octocat", Email: "octo@users.noreply.github.com", Commits: 3},
+		{Name: "Carol", Email: "carol@example.com", Commits: 7},
+		{Name: "carol-bot", Email: "carol@example.com", Commits: 1},
+	}
+
+	got := SuggestAliases(authors)
+	assert.Equal(t, []AliasSuggestion{
+		{
+			Name:    "Alice Smith",
+			Emails:  []string{"alice@company.com", "alice@gmail.com", "1234+asmith@users.noreply.github.com"},
+			Reasons: []string{"same name", "GitHub noreply"},
+			Commits: 43,
+		},
+		{
+			Name:    "Bob",
+			Emails:  []string{"bob@example.com"},
+			Reasons: []string{"case variant"},
+			Commits: 22,
+		},
+		{
+			Name:    "octo",
+			Emails:  []string{"octo@home.dev", "octo@users.noreply.github.com"},
+			Reasons: []string{"GitHub noreply"},
+			Commits: 7,
+		},
+	}, got)
+}
+
+func TestGitHubNoreplyLogin(t *testing.T) {
+	tests := []struct {
+		email string
+		login string
+		ok    bool
+	}{
+		{"1234+Octo@users.noreply.github.com", "octo", true},
+		{"octo@users.noreply.github.com", "octo", true},
+		{"noreply@github.com", "", false},
+		{"octo@example.com", "", false},
+	}
+	for _, tt := range tests {
+		login, ok := GitHubNoreplyLogin(tt.email)
+		assert.Equal(t, tt.ok, ok, tt.email)
+		assert.Equal(t, tt.login, login, tt.email)
+	}
+}
+
+// commitAs 以指定作者姓名与邮箱提交一个文件。
+func commitAs(t *testing.T, wt *git.Worktree, repoPath, file, name, email string, when time.Time) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, file), []byte(name+email), 0o644))
+	_, err := wt.Add(file)
+	require.NoError(t, err)
+
+	sig := &object.Signature{Name: name, Email: email, When: when}
+	_, err = wt.Commit("commit "+file, &git.CommitOptions{Author: sig, Committer: sig})
+	require.NoError(t, err)
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"strings"
+)
+
+// coAuthorTrailer 是 GitHub/GitLab 约定的结对提交 trailer 前缀（大小写不敏感）。
+const coAuthorTrailer = "co-authored-by:"
+
+// parseCoAuthors 从提交信息中解析 "Co-authored-by: Name <email>" trailer，返回原始邮箱列表。
+// 与 git interpret-trailers 不同，这里不要求 trailer 位于最后一段，以兼容手写的提交信息；
+// 缺少尖括号或邮箱为空的行会被忽略。
+func parseCoAuthors(message string) []string {
+	var out []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < len(coAuthorTrailer) || !strings.EqualFold(line[:len(coAuthorTrailer)], coAuthorTrailer) {
+			continue
+		}
+		value := line[len(coAuthorTrailer):]
+		start := strings.LastIndex(value, "<")
+		end := strings.LastIndex(value, ">")
+		if start < 0 || end <= start+1 {
+			continue
+		}
+		email := strings.TrimSpace(value[start+1 : end])
+		if email == "" {
+			continue
+		}
+		out = append(out, email)
+	}
+	return out
+}
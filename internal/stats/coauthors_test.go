@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	msg := "feat: pair work\n\nbody mentions co-authored-by without colon\n\n" +
+		"Co-authored-by: Bob <bob@example.com>\n" +
+		"  CO-AUTHORED-BY: Carol Smith <carol@example.com>  \n" +
+		"Co-authored-by: missing brackets carol@example.com\n" +
+		"Co-authored-by: Empty <>\n"
+	assert.Equal(t, []string{"bob@example.com", "carol@example.com"}, parseCoAuthors(msg))
+	assert.Nil(t, parseCoAuthors("plain message"))
+}
+
+func TestCollectActivityByEmails_CoAuthors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	commitFile(t, wt, repoPath, "a.txt", "solo", "alice@company.com", when)
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "b.txt"), []byte("pair"), 0o644))
+	_, err = wt.Add("b.txt")
+	require.NoError(t, err)
+	sig := &object.Signature{Name: "Alice", Email: "alice@company.com", When: when.Add(time.Minute)}
+	_, err = wt.Commit("pair\n\nCo-authored-by: Bob <bob@gmail.com>", &git.CommitOptions{Author: sig, Committer: sig})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Aliases: []config.Alias{{Name: "Bob", Emails: []string{"bob@company.com", "bob@gmail.com"}}}}
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{
+		Repos:          []string{repoPath},
+		Emails:         []string{"alice@company.com", "bob@company.com"},
+		Since:          day,
+		Until:          day,
+		NormalizeEmail: cfg.NormalizeEmail,
+	}
+
+	for _, useCache := range []bool{false, true} {
+		opts.UseCache = useCache
+
+		opts.CoAuthors = false
+		got, err := CollectActivityByEmails(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, SumActivity(got["alice@company.com"]).Commits, "useCache=%t", useCache)
+		assert.Equal(t, 0, SumActivity(got["bob@company.com"]).Commits, "useCache=%t", useCache)
+
+		opts.CoAuthors = true
+		got, err = CollectActivityByEmails(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, SumActivity(got["alice@company.com"]).Commits, "useCache=%t", useCache)
+		assert.Equal(t, 1, SumActivity(got["bob@company.com"]).Commits, "useCache=%t", useCache)
+
+		// 聚合视图中结对提交只计一次
+		total, err := CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, SumActivity(total).Commits, "useCache=%t", useCache)
+
+		// 仅按共同作者过滤时也能命中
+		bobOnly := opts
+		bobOnly.Emails = []string{"bob@company.com"}
+		total, err = CollectActivity(bobOnly)
+		require.NoError(t, err)
+		assert.Equal(t, 1, SumActivity(total).Commits, "useCache=%t", useCache)
+	}
+}
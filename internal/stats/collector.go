@@ -1,10 +1,13 @@
 package stats
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -37,6 +40,20 @@ type BranchOption struct {
 	AllBranches bool
 }
 
+// MergeMode 控制合并提交（父提交数 > 1）是否计入统计。
+type MergeMode string
+
+const (
+	// MergesInclude 统计全部提交（默认，与 git log 一致）。
+	MergesInclude MergeMode = ""
+	// MergesExclude 跳过合并提交（与 git log --no-merges 一致）。
+	MergesExclude MergeMode = "exclude"
+	// MergesOnly 只统计合并提交（与 git log --merges 一致）。
+	MergesOnly MergeMode = "only"
+)
+
+// CollectOptions 汇总一次收集所需的全部参数。
+// 零值字段表示默认行为（HEAD、不过滤邮箱、不统计代码行）。
 type CollectOptions struct {
 	Repos          []string
 	Emails         []string
@@ -46,6 +63,107 @@ type CollectOptions struct {
 	AllBranch      bool
 	UseCache       bool
 	NormalizeEmail func(string) string
+	// LineStats 为 true 时额外统计新增/删除行数与变更文件数。
+	// 需要对每个命中的提交与其第一个父提交做 diff，开销明显高于仅计数。
+	LineStats bool
+	// Merges 控制合并提交的取舍，零值为全部统计。
+	Merges MergeMode
+	// Paths 为路径过滤规则（见 ParsePathFilter），只统计变更了匹配文件的提交。
+	Paths []string
+	// CoAuthors 为 true 时解析提交信息中的 Co-authored-by trailer，
+	// 每位共同作者都视为该提交当天的贡献者。
+	CoAuthors bool
+	// MailmapFile 为全局 mailmap 文件路径（可选），与各仓库的 .mailmap 合并后
+	// 在邮箱过滤与别名规范化之前应用。
+	MailmapFile string
+	// AuthorLocal 为 true 时按每个提交作者时间戳记录的时区划分日期，
+	// 否则按 Until 的时区划分。
+	AuthorLocal bool
+}
+
+// LineTotals 表示代码行变更的合计值。
+type LineTotals struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Files     int `json:"files"`
+}
+
+// Add 返回两个合计值之和。
+func (l LineTotals) Add(o LineTotals) LineTotals {
+	return LineTotals{
+		Additions: l.Additions + o.Additions,
+		Deletions: l.Deletions + o.Deletions,
+		Files:     l.Files + o.Files,
+	}
+}
+
+// DayActivity 表示单日（或任意聚合范围）的提交活动。
+// Lines 仅在 CollectOptions.LineStats 开启时填充；
+// 合并提交不计入行数（与 git log --numstat 的默认行为一致）。
+type DayActivity struct {
+	Commits int
+	Merges  int // Commits 中合并提交的数量
+	Lines   LineTotals
+}
+
+// Add 返回两个活动统计之和。
+func (a DayActivity) Add(o DayActivity) DayActivity {
+	return DayActivity{
+		Commits: a.Commits + o.Commits,
+		Merges:  a.Merges + o.Merges,
+		Lines:   a.Lines.Add(o.Lines),
+	}
+}
+
+// ActivityCounts 将按天的活动统计降维为按天的提交数，供热力图与摘要复用。
+func ActivityCounts(daily map[time.Time]DayActivity) map[time.Time]int {
+	out := make(map[time.Time]int, len(daily))
+	for day, act := range daily {
+		out[day] = act.Commits
+	}
+	return out
+}
+
+// SumActivity 返回按天活动统计的总和。
+func SumActivity(daily map[time.Time]DayActivity) DayActivity {
+	var total DayActivity
+	for _, act := range daily {
+		total = total.Add(act)
+	}
+	return total
+}
+
+// repoQuery 是单仓库收集所需的参数，由 collectCommonGeneric 统一构建，
+// 避免 collectFn 的签名随选项增加而膨胀。
+type repoQuery struct {
+	startDayKey    int
+	endDayKey      int
+	loc            *time.Location
+	authorLocal    bool // 按提交作者时区划分日期
+	emailSet       map[string]struct{}
+	branch         BranchOption
+	normalizeEmail func(string) string
+	lineStats      bool
+	merges         MergeMode
+	paths          *PathFilter
+	coAuthors      bool
+	mailmap        *Mailmap // 仓库 .mailmap 与全局 mailmap 合并后的规则，按仓库设置
+	useCache       bool
+	// startPoints 为单仓库解析后的遍历起点，由 collectRepo 解析一次后同时用于
+	// 缓存指纹与遍历，避免两次读取分支引用之间分支移动导致缓存与数据不一致。
+	// 为 nil 时按 branch 现场解析。
+	startPoints []plumbing.Hash
+	// seenCommits 非 nil 时在多次 walkRepoCommits 之间共享已遍历的提交并据此剪枝，
+	// 用于逐个起点分别遍历（如 export 记录来源分支）时不重复遍历共同历史。
+	seenCommits map[plumbing.Hash]struct{}
+}
+
+// resolveStartPoints 返回本次查询的遍历起点。
+func (q repoQuery) resolveStartPoints(repo *git.Repository, repoPath string) ([]plumbing.Hash, error) {
+	if q.startPoints != nil {
+		return q.startPoints, nil
+	}
+	return collectStartPoints(repo, repoPath, q.branch)
 }
 
 // CollectStats 并发收集多个仓库的提交统计。
@@ -59,9 +177,7 @@ type CollectOptions struct {
 // 返回以日期（当天 00:00:00）为键、提交数为值的映射。
 // 如果部分仓库收集失败，会返回已成功收集的数据和聚合的错误。
 func CollectStats(repos []string, emails []string, start, end time.Time, branch BranchOption, normalizeEmail func(string) string, useCache bool) (map[time.Time]int, error) {
-	loc := end.Location()
-	out := make(map[time.Time]int)
-	done, err := collectCommonGeneric[map[int]int](CollectOptions{
+	daily, err := CollectActivity(CollectOptions{
 		Repos:          repos,
 		Emails:         emails,
 		Since:          start,
@@ -70,24 +186,18 @@ func CollectStats(repos []string, emails []string, start, end time.Time, branch
 		AllBranch:      branch.AllBranches,
 		UseCache:       useCache,
 		NormalizeEmail: normalizeEmail,
-	}, collectRepoFn, func(_ string, daily map[int]int) {
-		for dayKey, count := range daily {
-			out[dayKeyToTime(dayKey, loc)] += count
-		}
 	})
-	if err != nil && len(done) == 0 {
+	if daily == nil {
 		return nil, err
 	}
-	return out, err
+	return ActivityCounts(daily), err
 }
 
 // CollectStatsPerRepo 并发收集多个仓库的提交统计，并按仓库分别返回结果。
 // 返回 map[repoPath]map[day]count，其中 day 为当天 00:00:00（由 end 的时区决定）。
 // 如果部分仓库收集失败，会返回已成功收集的数据和聚合的错误。
 func CollectStatsPerRepo(repos []string, emails []string, start, end time.Time, branch BranchOption, normalizeEmail func(string) string, useCache bool) (map[string]map[time.Time]int, error) {
-	loc := end.Location()
-	out := make(map[string]map[time.Time]int)
-	done, err := collectCommonGeneric[map[int]int](CollectOptions{
+	perRepo, err := CollectActivityPerRepo(CollectOptions{
 		Repos:          repos,
 		Emails:         emails,
 		Since:          start,
@@ -96,26 +206,18 @@ func CollectStatsPerRepo(repos []string, emails []string, start, end time.Time,
 		AllBranch:      branch.AllBranches,
 		UseCache:       useCache,
 		NormalizeEmail: normalizeEmail,
-	}, collectRepoFn, func(repoPath string, daily map[int]int) {
-		stats := make(map[time.Time]int, len(daily))
-		for dayKey, count := range daily {
-			stats[dayKeyToTime(dayKey, loc)] = count
-		}
-		out[repoPath] = stats
 	})
-	if err != nil && len(done) == 0 {
+	if perRepo == nil {
 		return nil, err
 	}
-	return out, err
+	return activityCountsByKey(perRepo), err
 }
 
 // CollectStatsByEmails 并发收集多个仓库的提交统计，并按邮箱分桶聚合。
 // 返回 map[email]map[day]count，其中 day 为当天 00:00:00（由 end 的时区决定）。
 // 如果部分仓库收集失败，会返回已成功收集的数据和聚合的错误。
 func CollectStatsByEmails(repos []string, emails []string, start, end time.Time, branch BranchOption, normalizeEmail func(string) string, useCache bool) (map[string]map[time.Time]int, error) {
-	loc := end.Location()
-	out := make(map[string]map[int]int, len(emails))
-	done, err := collectCommonGeneric[map[string]map[int]int](CollectOptions{
+	byEmail, err := CollectActivityByEmails(CollectOptions{
 		Repos:          repos,
 		Emails:         emails,
 		Since:          start,
@@ -124,15 +226,59 @@ func CollectStatsByEmails(repos []string, emails []string, start, end time.Time,
 		AllBranch:      branch.AllBranches,
 		UseCache:       useCache,
 		NormalizeEmail: normalizeEmail,
-	}, collectRepoByEmailsFn, func(_ string, byEmail map[string]map[int]int) {
+	})
+	if byEmail == nil {
+		return nil, err
+	}
+	return activityCountsByKey(byEmail), err
+}
+
+// CollectActivity 与 CollectStats 相同，但返回包含代码行统计的按天活动。
+func CollectActivity(opts CollectOptions) (map[time.Time]DayActivity, error) {
+	loc := opts.Until.Location()
+	out := make(map[time.Time]DayActivity)
+	done, err := collectCommonGeneric[map[int]DayActivity](opts, collectRepoFn, func(_ string, daily map[int]DayActivity) {
+		for dayKey, act := range daily {
+			day := dayKeyToTime(dayKey, loc)
+			out[day] = out[day].Add(act)
+		}
+	})
+	if err != nil && len(done) == 0 {
+		return nil, err
+	}
+	return out, err
+}
+
+// CollectActivityPerRepo 与 CollectStatsPerRepo 相同，但返回包含代码行统计的按天活动。
+func CollectActivityPerRepo(opts CollectOptions) (map[string]map[time.Time]DayActivity, error) {
+	loc := opts.Until.Location()
+	out := make(map[string]map[time.Time]DayActivity)
+	done, err := collectCommonGeneric[map[int]DayActivity](opts, collectRepoFn, func(repoPath string, daily map[int]DayActivity) {
+		stats := make(map[time.Time]DayActivity, len(daily))
+		for dayKey, act := range daily {
+			stats[dayKeyToTime(dayKey, loc)] = act
+		}
+		out[repoPath] = stats
+	})
+	if err != nil && len(done) == 0 {
+		return nil, err
+	}
+	return out, err
+}
+
+// CollectActivityByEmails 与 CollectStatsByEmails 相同，但返回包含代码行统计的按天活动。
+func CollectActivityByEmails(opts CollectOptions) (map[string]map[time.Time]DayActivity, error) {
+	loc := opts.Until.Location()
+	out := make(map[string]map[int]DayActivity, len(opts.Emails))
+	done, err := collectCommonGeneric[map[string]map[int]DayActivity](opts, collectRepoByEmailsFn, func(_ string, byEmail map[string]map[int]DayActivity) {
 		for email, daily := range byEmail {
 			target := out[email]
 			if target == nil {
-				target = make(map[int]int, len(daily))
+				target = make(map[int]DayActivity, len(daily))
 				out[email] = target
 			}
-			for dayKey, count := range daily {
-				target[dayKey] += count
+			for dayKey, act := range daily {
+				target[dayKey] = target[dayKey].Add(act)
 			}
 		}
 	})
@@ -140,60 +286,36 @@ func CollectStatsByEmails(repos []string, emails []string, start, end time.Time,
 		return nil, err
 	}
 
-	converted := make(map[string]map[time.Time]int, len(out))
+	converted := make(map[string]map[time.Time]DayActivity, len(out))
 	for email, daily := range out {
-		dayStats := make(map[time.Time]int, len(daily))
-		for dayKey, count := range daily {
-			dayStats[dayKeyToTime(dayKey, loc)] = count
+		dayStats := make(map[time.Time]DayActivity, len(daily))
+		for dayKey, act := range daily {
+			dayStats[dayKeyToTime(dayKey, loc)] = act
 		}
 		converted[email] = dayStats
 	}
 	return converted, err
 }
 
+// activityCountsByKey 对分组后的活动统计逐组调用 ActivityCounts。
+func activityCountsByKey(grouped map[string]map[time.Time]DayActivity) map[string]map[time.Time]int {
+	out := make(map[string]map[time.Time]int, len(grouped))
+	for key, daily := range grouped {
+		out[key] = ActivityCounts(daily)
+	}
+	return out
+}
+
 func collectCommonGeneric[T any](
 	opts CollectOptions,
-	collectFn func(repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string, useCache bool) (T, error),
+	collectFn func(repoPath string, q repoQuery) (T, error),
 	aggregator func(repoPath string, result T),
 ) ([]string, error) {
-	if opts.Since.IsZero() {
-		return nil, fmt.Errorf("start must be set")
-	}
-	if opts.Until.IsZero() {
-		return nil, fmt.Errorf("end must be set")
-	}
-
-	branch, err := normalizeBranchOption(BranchOption{
-		Branch:      opts.Branch,
-		AllBranches: opts.AllBranch,
-	})
+	q, err := newRepoQuery(opts)
 	if err != nil {
 		return nil, err
 	}
 
-	loc := opts.Until.Location()
-	start := beginningOfDay(opts.Since, loc)
-	end := beginningOfDay(opts.Until, loc)
-
-	if start.After(end) {
-		return nil, fmt.Errorf("start must be <= end (start=%s, end=%s)", start.Format("2006-01-02"), end.Format("2006-01-02"))
-	}
-	startDayKey := dayKeyFromTime(start, loc)
-	endDayKey := dayKeyFromTime(end, loc)
-
-	normalizeEmail := resolveNormalizeEmail(opts.NormalizeEmail)
-	emailSet := make(map[string]struct{}, len(opts.Emails))
-	for _, email := range opts.Emails {
-		if email == "" {
-			continue
-		}
-		email = normalizeEmail(email)
-		if email == "" {
-			continue
-		}
-		emailSet[email] = struct{}{}
-	}
-
 	done := make([]string, 0, len(opts.Repos))
 
 	var (
@@ -226,7 +348,8 @@ func collectCommonGeneric[T any](
 				pmu.Unlock()
 			}()
 
-			stats, err := collectFn(repoPath, startDayKey, endDayKey, loc, emailSet, branch, normalizeEmail, opts.UseCache)
+			rq := q.forRepo(repoPath)
+			stats, err := collectFn(repoPath, rq)
 			if err != nil {
 				emu.Lock()
 				errs = append(errs, err)
@@ -245,9 +368,88 @@ func collectCommonGeneric[T any](
 	return done, errors.Join(errs...)
 }
 
+// newRepoQuery 校验收集参数并构建各仓库共用的查询条件。
+// 查询条件中的 mailmap 只包含全局规则，具体仓库需通过 forRepo 合并其 .mailmap。
+func newRepoQuery(opts CollectOptions) (repoQuery, error) {
+	if opts.Since.IsZero() {
+		return repoQuery{}, fmt.Errorf("start must be set")
+	}
+	if opts.Until.IsZero() {
+		return repoQuery{}, fmt.Errorf("end must be set")
+	}
+
+	branch, err := normalizeBranchOption(BranchOption{
+		Branch:      opts.Branch,
+		AllBranches: opts.AllBranch,
+	})
+	if err != nil {
+		return repoQuery{}, err
+	}
+	switch opts.Merges {
+	case MergesInclude, MergesExclude, MergesOnly:
+	default:
+		return repoQuery{}, fmt.Errorf("invalid merge mode %q", opts.Merges)
+	}
+	paths, err := ParsePathFilter(opts.Paths)
+	if err != nil {
+		return repoQuery{}, err
+	}
+	var globalMailmap *Mailmap
+	if opts.MailmapFile != "" {
+		// 与 git 一致：全局 mailmap 缺失或部分行无法解析时不影响统计，由 doctor 报告
+		globalMailmap, _ = LoadMailmap(opts.MailmapFile)
+	}
+
+	loc := opts.Until.Location()
+	start := beginningOfDay(opts.Since, loc)
+	end := beginningOfDay(opts.Until, loc)
+
+	if start.After(end) {
+		return repoQuery{}, fmt.Errorf("start must be <= end (start=%s, end=%s)", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	startDayKey := dayKeyFromTime(start, loc)
+	endDayKey := dayKeyFromTime(end, loc)
+
+	normalizeEmail := resolveNormalizeEmail(opts.NormalizeEmail)
+	emailSet := make(map[string]struct{}, len(opts.Emails))
+	for _, email := range opts.Emails {
+		if email == "" {
+			continue
+		}
+		email = normalizeEmail(email)
+		if email == "" {
+			continue
+		}
+		emailSet[email] = struct{}{}
+	}
+
+	q := repoQuery{
+		startDayKey:    startDayKey,
+		endDayKey:      endDayKey,
+		loc:            loc,
+		authorLocal:    opts.AuthorLocal,
+		emailSet:       emailSet,
+		branch:         branch,
+		normalizeEmail: normalizeEmail,
+		lineStats:      opts.LineStats,
+		merges:         opts.Merges,
+		paths:          paths,
+		coAuthors:      opts.CoAuthors,
+		mailmap:        globalMailmap,
+		useCache:       opts.UseCache,
+	}
+	return q, nil
+}
+
+// forRepo 返回合并了仓库 .mailmap 的查询条件副本。
+func (q repoQuery) forRepo(repoPath string) repoQuery {
+	q.mailmap = loadRepoMailmap(repoPath, q.mailmap)
+	return q
+}
+
 // CollectStatsMonths 兼容旧接口：按最近 N 个月（对齐到周日）并截止到今天统计。
 func CollectStatsMonths(repos []string, emails []string, months int) (map[time.Time]int, error) {
-	start, end, err := TimeRange("", "", months)
+	start, end, err := TimeRange("", "", months, time.Sunday, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -281,94 +483,158 @@ func newRepoProgressBar(total int) *progressbar.ProgressBar {
 //   - 统计口径基于 Author.When，且 Author.When 不保证单调，禁止据此提前终止遍历。
 //   - 禁止基于 Author.When 或 Committer.When 的 < start 重新引入 ErrStop。
 //   - 性能保障依赖邮箱过滤前移、dayKey 轻量聚合、以及 --all-branches 下的 hash 剪枝。
-func collectRepo(repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string, useCache bool) (map[int]int, error) {
-	if _, err := os.Stat(repoPath); err != nil {
-		return nil, fmt.Errorf("stat repo %s: %w", repoPath, err)
-	}
-
-	// 打开 Git 仓库
-	repo, err := git.PlainOpen(repoPath)
+//   - 启用缓存时先查结果缓存，未命中再基于提交索引计算（只增量读取新提交）；
+//     禁用缓存（--no-cache）时直接全量遍历提交对象。
+func collectRepo(repoPath string, q repoQuery) (map[int]DayActivity, error) {
+	repo, err := openRepository(repoPath)
 	if err != nil {
-		return nil, fmt.Errorf("open repo %s: %w", repoPath, err)
+		return nil, err
 	}
 
 	var cacheKey cache.CacheKey
-	if useCache {
-		headRef, err := repo.Head()
+	if q.useCache {
+		// 指纹基于实际遍历的起点（--branch 的分支、--all-branches 的全部分支 tip），
+		// 而不是 HEAD：非 HEAD 分支移动或新建分支同样会让缓存失效。
+		startPoints, err := collectStartPoints(repo, repoPath, q.branch)
 		if err != nil {
-			return nil, fmt.Errorf("head repo %s: %w", repoPath, err)
+			return nil, err
 		}
-		cacheKey = buildRepoCacheKey(repoPath, headRef.Hash().String(), startDayKey, endDayKey, emailSet, branch)
+		q.startPoints = startPoints
+		cacheKey = buildRepoCacheKey(repoPath, startPointsFingerprint(startPoints), q)
 
 		entry, err := cache.LoadCache(cacheKey)
 		if err == nil {
-			daily, convErr := fromCachedStats(entry.Stats)
+			daily, convErr := fromCachedActivity(entry)
 			if convErr == nil {
 				return daily, nil
 			}
 		}
 	}
 
-	stats, err := collectRepoFromRepositoryFn(repo, repoPath, startDayKey, endDayKey, loc, emailSet, branch, normalizeEmail)
+	scan := collectRepoFromRepositoryFn
+	if q.useCache {
+		scan = collectRepoFromIndexFn
+	}
+	stats, err := scan(repo, repoPath, q)
 	if err != nil {
 		return nil, err
 	}
 
-	if useCache {
-		_ = cache.SaveCache(cacheKey, toCachedStats(stats))
+	if q.useCache {
+		counts, lines, merges := toCachedActivity(stats, q.lineStats)
+		_ = cache.SaveCacheActivity(cacheKey, counts, lines, merges)
 	}
 
 	return stats, nil
 }
 
-func collectRepoByEmails(repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string, useCache bool) (map[string]map[int]int, error) {
-	// 按邮箱分桶的缓存收益较低且缓存体积更大，当前实现不启用缓存；保留参数仅为复用统一 collectFn 签名。
-	_ = useCache
+// openRepository 检查路径存在并打开 Git 仓库。
+func openRepository(repoPath string) (*git.Repository, error) {
 	if _, err := os.Stat(repoPath); err != nil {
 		return nil, fmt.Errorf("stat repo %s: %w", repoPath, err)
 	}
-
 	repo, err := git.PlainOpen(repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("open repo %s: %w", repoPath, err)
 	}
+	return repo, nil
+}
 
-	return collectRepoByEmailsFromRepositoryFn(repo, repoPath, startDayKey, endDayKey, loc, emailSet, branch, normalizeEmail)
+func collectRepoByEmails(repoPath string, q repoQuery) (map[string]map[int]DayActivity, error) {
+	// 按邮箱分桶的结果缓存收益较低且缓存体积更大，这里只使用提交索引。
+	repo, err := openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.useCache {
+		return collectRepoByEmailsFromIndexFn(repo, repoPath, q)
+	}
+	return collectRepoByEmailsFromRepositoryFn(repo, repoPath, q)
+}
+
+func collectRepoFromRepository(repo *git.Repository, repoPath string, q repoQuery) (map[int]DayActivity, error) {
+	out := make(map[int]DayActivity)
+	if err := walkRepoCommits(repo, repoPath, q, func(_ []string, dayKey int, c *object.Commit) error {
+		act, err := commitActivity(c, q.lineStats)
+		if err != nil {
+			return fmt.Errorf("line stats repo %s commit %s: %w", repoPath, c.Hash, err)
+		}
+		out[dayKey] = out[dayKey].Add(act)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func collectRepoFromRepository(repo *git.Repository, repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string) (map[int]int, error) {
-	out := make(map[int]int)
-	if err := walkRepoCommits(repo, repoPath, startDayKey, endDayKey, loc, emailSet, branch, normalizeEmail, func(_ string, dayKey int) {
-		out[dayKey]++
+func collectRepoByEmailsFromRepository(repo *git.Repository, repoPath string, q repoQuery) (map[string]map[int]DayActivity, error) {
+	out := make(map[string]map[int]DayActivity)
+	if err := walkRepoCommits(repo, repoPath, q, func(emails []string, dayKey int, c *object.Commit) error {
+		act, err := commitActivity(c, q.lineStats)
+		if err != nil {
+			return fmt.Errorf("line stats repo %s commit %s: %w", repoPath, c.Hash, err)
+		}
+		addEmailActivity(out, emails, dayKey, act)
+		return nil
 	}); err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func collectRepoByEmailsFromRepository(repo *git.Repository, repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string) (map[string]map[int]int, error) {
-	out := make(map[string]map[int]int)
-	if err := walkRepoCommits(repo, repoPath, startDayKey, endDayKey, loc, emailSet, branch, normalizeEmail, func(email string, dayKey int) {
+// addEmailActivity 将一个提交的活动量计入每个贡献者邮箱的桶（结对提交的每位作者各计一次）。
+func addEmailActivity(out map[string]map[int]DayActivity, emails []string, dayKey int, act DayActivity) {
+	for _, email := range emails {
 		daily := out[email]
 		if daily == nil {
-			daily = make(map[int]int)
+			daily = make(map[int]DayActivity)
 			out[email] = daily
 		}
-		daily[dayKey]++
-	}); err != nil {
-		return nil, err
+		daily[dayKey] = daily[dayKey].Add(act)
 	}
-	return out, nil
 }
 
-func walkRepoCommits(repo *git.Repository, repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string, visitor func(email string, dayKey int)) error {
-	startPoints, err := collectStartPoints(repo, repoPath, branch)
+// commitActivity 计算单个提交贡献的活动量。
+// lineStats 为 false 时只计数，避免 diff 开销；合并提交不统计行数。
+func commitActivity(c *object.Commit, lineStats bool) (DayActivity, error) {
+	act := DayActivity{Commits: 1}
+	if c.NumParents() > 1 {
+		act.Merges = 1
+		return act, nil
+	}
+	if !lineStats {
+		return act, nil
+	}
+
+	fileStats, err := c.Stats()
+	if err != nil {
+		return DayActivity{}, err
+	}
+	for _, fs := range fileStats {
+		act.Lines.Additions += fs.Addition
+		act.Lines.Deletions += fs.Deletion
+	}
+	act.Lines.Files = len(fileStats)
+	return act, nil
+}
+
+// walkRepoCommits 遍历起点可达的提交并对命中过滤的提交调用 visitor。
+// visitor 收到的 emails 为命中过滤的贡献者邮箱（见 matchCommit），仅在本次回调内有效。
+func walkRepoCommits(repo *git.Repository, repoPath string, q repoQuery, visitor func(emails []string, dayKey int, c *object.Commit) error) error {
+	startPoints, err := q.resolveStartPoints(repo, repoPath)
 	if err != nil {
 		return err
 	}
-	normalizeEmail = resolveNormalizeEmail(normalizeEmail)
+	normalizeEmail := resolveNormalizeEmail(q.normalizeEmail)
 
-	seenCommits := make(map[plumbing.Hash]struct{})
+	// --all-branches 或调用方共享已遍历集合时，遇到已处理过的提交即剪枝
+	prune := q.branch.AllBranches || q.seenCommits != nil
+	seenCommits := q.seenCommits
+	if seenCommits == nil {
+		seenCommits = make(map[plumbing.Hash]struct{})
+	}
+	emailBuf := make([]string, 0, 1)
 
 	for _, from := range startPoints {
 		iterator, err := repo.Log(&git.LogOptions{From: from})
@@ -377,7 +643,7 @@ func walkRepoCommits(repo *git.Repository, repoPath string, startDayKey, endDayK
 		}
 
 		iterErr := iterator.ForEach(func(c *object.Commit) error {
-			if branch.AllBranches {
+			if prune {
 				if _, seen := seenCommits[c.Hash]; seen {
 					// 该提交及其祖先已在先前分支遍历中处理过，提前剪枝。
 					return storer.ErrStop
@@ -385,24 +651,25 @@ func walkRepoCommits(repo *git.Repository, repoPath string, startDayKey, endDayK
 				seenCommits[c.Hash] = struct{}{}
 			}
 
-			email := normalizeEmail(c.Author.Email)
-			// 邮箱过滤前移：无关邮箱直接跳过，避免后续时间归一化开销。
-			if len(emailSet) > 0 {
-				if _, ok := emailSet[email]; !ok {
-					return nil
-				}
+			var coAuthors []string
+			if q.coAuthors {
+				coAuthors = parseCoAuthors(c.Message)
 			}
-
-			commitDayKey := dayKeyFromTime(c.Author.When, loc)
-			if commitDayKey > endDayKey {
+			emails, commitDayKey, ok := matchCommit(q, normalizeEmail, emailBuf, c.Author.Name, c.Author.Email, coAuthors, c.Author.When, c.NumParents())
+			if !ok {
 				return nil
 			}
-			if commitDayKey < startDayKey {
-				return nil
+			// 路径过滤需要 diff，放在邮箱与时间过滤之后
+			if q.paths != nil {
+				files, err := commitFilesFn(c)
+				if err != nil {
+					return fmt.Errorf("diff repo %s commit %s: %w", repoPath, c.Hash, err)
+				}
+				if !q.paths.MatchAny(files) {
+					return nil
+				}
 			}
-
-			visitor(email, commitDayKey)
-			return nil
+			return visitor(emails, commitDayKey, c)
 		})
 		iterator.Close()
 		if iterErr != nil && !errors.Is(iterErr, storer.ErrStop) {
@@ -413,14 +680,132 @@ func walkRepoCommits(repo *git.Repository, repoPath string, startDayKey, endDayK
 	return nil
 }
 
-func buildRepoCacheKey(repoPath string, headHash string, startDayKey, endDayKey int, emailSet map[string]struct{}, branch BranchOption) cache.CacheKey {
+// matchCommit 对单个提交应用合并提交、邮箱与时间范围过滤，返回命中过滤的贡献者邮箱（已规范化）与 dayKey。
+// 邮箱先经过仓库 mailmap 映射，再做别名规范化与邮箱过滤。
+// 开启 co-author 统计时，作者与 Co-authored-by 中任一邮箱命中即计入，返回全部命中的邮箱（作者在前、去重）。
+// walkRepoCommits 与提交索引共用该函数，保证两条路径的统计口径一致。
+// 返回的切片复用 dst 的底层数组，避免热路径上逐提交分配；调用方不得跨提交持有。
+func matchCommit(q repoQuery, normalizeEmail func(string) string, dst []string, authorName, authorEmail string, coAuthors []string, when time.Time, numParents int) ([]string, int, bool) {
+	isMerge := numParents > 1
+	if (q.merges == MergesExclude && isMerge) || (q.merges == MergesOnly && !isMerge) {
+		return nil, 0, false
+	}
+
+	// 邮箱过滤前移：无关邮箱直接跳过，避免后续时间归一化开销。
+	emails := appendMatchedEmail(q, dst[:0], normalizeEmail(q.mailmap.Email(authorName, authorEmail)))
+	if q.coAuthors {
+		for _, raw := range coAuthors {
+			email := normalizeEmail(q.mailmap.Email("", raw))
+			if email == "" || slices.Contains(emails, email) {
+				continue
+			}
+			emails = appendMatchedEmail(q, emails, email)
+		}
+	}
+	if len(emails) == 0 {
+		return nil, 0, false
+	}
+
+	dayKey := dayKeyFromTime(when, q.dayLocation(when))
+	if dayKey > q.endDayKey || dayKey < q.startDayKey {
+		return nil, 0, false
+	}
+	return emails, dayKey, true
+}
+
+// dayLocation 返回提交时间 when 划分日期所用的时区。
+func (q repoQuery) dayLocation(when time.Time) *time.Location {
+	if q.authorLocal {
+		return when.Location()
+	}
+	return q.loc
+}
+
+// timezoneKey 返回缓存键中的时区标识：按作者时区为 "author"，本地时区为 localZoneKey 描述的实际规则，
+// 否则为时区名称。
+func (q repoQuery) timezoneKey() string {
+	switch {
+	case q.authorLocal:
+		return TimezoneAuthor
+	case q.loc == nil || q.loc == time.Local || q.loc.String() == "Local":
+		return localZoneKey(q.startDayKey, q.endDayKey)
+	default:
+		return q.loc.String()
+	}
+}
+
+// localZoneKey 描述本地时区的实际规则。time.Local 的名称恒为 "Local"，不能区分 TZ 不同的机器，
+// 因此取范围首尾两天以及结束年份 1 月 1 日、7 月 1 日（覆盖夏令时）的时区缩写与偏移。
+func localZoneKey(startDayKey, endDayKey int) string {
+	end := dayKeyToTime(endDayKey, time.Local)
+	samples := []time.Time{
+		dayKeyToTime(startDayKey, time.Local),
+		end,
+		time.Date(end.Year(), time.January, 1, 0, 0, 0, 0, time.Local),
+		time.Date(end.Year(), time.July, 1, 0, 0, 0, 0, time.Local),
+	}
+	parts := make([]string, len(samples))
+	for i, t := range samples {
+		parts[i] = t.Format("MST-0700")
+	}
+	return "local:" + strings.Join(parts, ",")
+}
+
+// appendMatchedEmail 在邮箱通过过滤（或未设置过滤）时将其追加到 dst。
+func appendMatchedEmail(q repoQuery, dst []string, email string) []string {
+	if len(q.emailSet) > 0 {
+		if _, ok := q.emailSet[email]; !ok {
+			return dst
+		}
+	}
+	return append(dst, email)
+}
+
+// RepoFingerprint 返回仓库在给定分支选项下当前的缓存指纹（与 CacheKey.HEADHash 对应）。
+// 供缓存清理判断条目是否已过期。
+func RepoFingerprint(repoPath string, branch BranchOption) (string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("open repo %s: %w", repoPath, err)
+	}
+	startPoints, err := collectStartPoints(repo, repoPath, branch)
+	if err != nil {
+		return "", err
+	}
+	return startPointsFingerprint(startPoints), nil
+}
+
+// startPointsFingerprint 计算遍历起点集合的指纹。
+// 单一起点（HEAD 或 --branch）直接使用其 hash，与旧缓存文件保持兼容；
+// 多个起点（--all-branches）排序后取 SHA-256，与分支枚举顺序无关。
+func startPointsFingerprint(startPoints []plumbing.Hash) string {
+	if len(startPoints) == 1 {
+		return startPoints[0].String()
+	}
+
+	hashes := make([]string, 0, len(startPoints))
+	for _, h := range startPoints {
+		hashes = append(hashes, h.String())
+	}
+	sort.Strings(hashes)
+	digest := sha256.Sum256([]byte(strings.Join(hashes, ",")))
+	return "tips:" + hex.EncodeToString(digest[:])
+}
+
+func buildRepoCacheKey(repoPath string, fingerprint string, q repoQuery) cache.CacheKey {
 	return cache.CacheKey{
 		RepoPath:  repoPath,
-		HEADHash:  headHash,
-		Emails:    sortedEmails(emailSet),
-		TimeRange: fmt.Sprintf("%s_%s", dayKeyToDateString(startDayKey), dayKeyToDateString(endDayKey)),
-		Branch:    branch.Branch,
-		AllBranch: branch.AllBranches,
+		HEADHash:  fingerprint,
+		Emails:    sortedEmails(q.emailSet),
+		TimeRange: fmt.Sprintf("%s_%s", dayKeyToDateString(q.startDayKey), dayKeyToDateString(q.endDayKey)),
+		Branch:    q.branch.Branch,
+		AllBranch: q.branch.AllBranches,
+		LineStats: q.lineStats,
+		Merges:    string(q.merges),
+		Paths:     q.paths.Patterns(),
+		CoAuthors: q.coAuthors,
+		Mailmap:   q.mailmap.Fingerprint(),
+		Timezone:  q.timezoneKey(),
 	}
 }
 
@@ -436,22 +821,45 @@ func sortedEmails(emailSet map[string]struct{}) []string {
 	return out
 }
 
-func toCachedStats(daily map[int]int) map[string]int {
-	out := make(map[string]int, len(daily))
-	for dayKey, count := range daily {
-		out[dayKeyToDateString(dayKey)] = count
+// toCachedActivity 将按天活动拆分为缓存所需的提交数、代码行与合并提交数三部分。
+// 未开启行统计时 lines 为 nil，保持缓存文件体积不变；merges 只记录非零的日期。
+func toCachedActivity(daily map[int]DayActivity, lineStats bool) (map[string]int, map[string]cache.LineCounts, map[string]int) {
+	counts := make(map[string]int, len(daily))
+	merges := make(map[string]int)
+	var lines map[string]cache.LineCounts
+	if lineStats {
+		lines = make(map[string]cache.LineCounts, len(daily))
+	}
+	for dayKey, act := range daily {
+		day := dayKeyToDateString(dayKey)
+		counts[day] = act.Commits
+		if act.Merges > 0 {
+			merges[day] = act.Merges
+		}
+		if lines != nil {
+			lines[day] = cache.LineCounts{
+				Additions: act.Lines.Additions,
+				Deletions: act.Lines.Deletions,
+				Files:     act.Lines.Files,
+			}
+		}
 	}
-	return out
+	return counts, lines, merges
 }
 
-func fromCachedStats(stats map[string]int) (map[int]int, error) {
-	out := make(map[int]int, len(stats))
-	for day, count := range stats {
+// fromCachedActivity 将缓存条目还原为按天活动。
+func fromCachedActivity(entry *cache.CacheEntry) (map[int]DayActivity, error) {
+	out := make(map[int]DayActivity, len(entry.Stats))
+	for day, count := range entry.Stats {
 		dayKey, err := dateStringToDayKey(day)
 		if err != nil {
 			return nil, err
 		}
-		out[dayKey] = count
+		act := DayActivity{Commits: count, Merges: entry.Merges[day]}
+		if lc, ok := entry.Lines[day]; ok {
+			act.Lines = LineTotals{Additions: lc.Additions, Deletions: lc.Deletions, Files: lc.Files}
+		}
+		out[dayKey] = act
 	}
 	return out, nil
 }
@@ -484,8 +892,28 @@ func normalizeBranchOption(opt BranchOption) (BranchOption, error) {
 	return opt, nil
 }
 
+// startPoint 是一个带来源名称的遍历起点。
+type startPoint struct {
+	name string // 分支短名；HEAD 处于分离状态时为 "HEAD"
+	hash plumbing.Hash
+}
+
 // collectStartPoints 根据分支选项确定遍历的起始 commit hash 列表。
 func collectStartPoints(repo *git.Repository, repoPath string, branch BranchOption) ([]plumbing.Hash, error) {
+	points, err := collectNamedStartPoints(repo, repoPath, branch)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]plumbing.Hash, 0, len(points))
+	for _, p := range points {
+		hashes = append(hashes, p.hash)
+	}
+	return hashes, nil
+}
+
+// collectNamedStartPoints 与 collectStartPoints 相同，但同时返回起点来源名称。
+// --all-branches 下按分支名排序，指向同一提交的多个分支只保留第一个。
+func collectNamedStartPoints(repo *git.Repository, repoPath string, branch BranchOption) ([]startPoint, error) {
 	switch {
 	case branch.AllBranches:
 		iter, err := repo.Branches()
@@ -494,26 +922,29 @@ func collectStartPoints(repo *git.Repository, repoPath string, branch BranchOpti
 		}
 		defer iter.Close()
 
-		tips := make([]plumbing.Hash, 0)
-		seenTips := make(map[plumbing.Hash]struct{})
+		refs := make([]*plumbing.Reference, 0)
 		err = iter.ForEach(func(ref *plumbing.Reference) error {
-			if ref == nil {
-				return nil
-			}
-			h := ref.Hash()
-			if h.IsZero() {
+			if ref == nil || ref.Hash().IsZero() {
 				return nil
 			}
-			if _, ok := seenTips[h]; ok {
-				return nil
-			}
-			seenTips[h] = struct{}{}
-			tips = append(tips, h)
+			refs = append(refs, ref)
 			return nil
 		})
 		if err != nil {
 			return nil, fmt.Errorf("iterate branches repo %s: %w", repoPath, err)
 		}
+		sort.Slice(refs, func(i, j int) bool { return refs[i].Name() < refs[j].Name() })
+
+		tips := make([]startPoint, 0, len(refs))
+		seenTips := make(map[plumbing.Hash]struct{}, len(refs))
+		for _, ref := range refs {
+			h := ref.Hash()
+			if _, ok := seenTips[h]; ok {
+				continue
+			}
+			seenTips[h] = struct{}{}
+			tips = append(tips, startPoint{name: ref.Name().Short(), hash: h})
+		}
 		return tips, nil
 	case branch.Branch != "":
 		refName := plumbing.NewBranchReferenceName(branch.Branch)
@@ -527,7 +958,7 @@ func collectStartPoints(repo *git.Repository, repoPath string, branch BranchOpti
 		if ref.Hash().IsZero() {
 			return nil, fmt.Errorf("repo %s: branch %q has no commits", repoPath, branch.Branch)
 		}
-		return []plumbing.Hash{ref.Hash()}, nil
+		return []startPoint{{name: branch.Branch, hash: ref.Hash()}}, nil
 	default:
 		ref, err := repo.Head()
 		if err != nil {
@@ -536,7 +967,11 @@ func collectStartPoints(repo *git.Repository, repoPath string, branch BranchOpti
 		if ref.Hash().IsZero() {
 			return nil, fmt.Errorf("repo %s: HEAD has no commits", repoPath)
 		}
-		return []plumbing.Hash{ref.Hash()}, nil
+		name := "HEAD"
+		if ref.Name().IsBranch() {
+			name = ref.Name().Short()
+		}
+		return []startPoint{{name: name, hash: ref.Hash()}}, nil
 	}
 }
 
@@ -562,12 +997,11 @@ func dayKeyToTime(dayKey int, loc *time.Location) time.Time {
 }
 
 // heatmapStart 计算热力图的起始日期。
-// 从当前日期向前推 months 个月，然后调整到最近的周日（热力图从周日开始）。
-func heatmapStart(now time.Time, months int) time.Time {
+// 从当前日期向前推 months 个月，然后向前调整到最近的 weekStart（热力图每列从该星期开始）。
+func heatmapStart(now time.Time, months int, weekStart time.Weekday) time.Time {
 	loc := now.Location()
 	start := beginningOfDay(now.AddDate(0, -months, 0), loc)
-	// 调整到周日（热力图的行从周日开始）
-	for start.Weekday() != time.Sunday {
+	for start.Weekday() != weekStart {
 		start = start.AddDate(0, 0, -1)
 	}
 	return start
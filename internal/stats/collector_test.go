@@ -98,7 +98,7 @@ func TestHeatmapStart(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := heatmapStart(tt.now, tt.months)
+			got := heatmapStart(tt.now, tt.months, time.Sunday)
 
 			assert.Equal(t, time.Sunday, got.Weekday(), "should start on Sunday")
 			assert.Equal(t, 0, got.Hour(), "hour should be 0")
@@ -201,14 +201,15 @@ func TestCollectStats_FirstCollectionCreatesCacheFile(t *testing.T) {
 
 	startDayKey := dayKeyFromTime(start, start.Location())
 	endDayKey := dayKeyFromTime(end, end.Location())
-	key := buildRepoCacheKey(repoPath, headRef.Hash().String(), startDayKey, endDayKey, nil, BranchOption{})
+	key := buildRepoCacheKey(repoPath, headRef.Hash().String(), repoQuery{startDayKey: startDayKey, endDayKey: endDayKey})
 
 	cachePath := filepath.Join(home, ".config", "git-visible", "cache", key.String())
 	assert.FileExists(t, cachePath)
 
 	entry, err := cache.LoadCache(key)
 	require.NoError(t, err)
-	assert.Equal(t, toCachedStats(toDayKeyStats(got)), entry.Stats)
+	assert.Equal(t, toDateStringStats(got), entry.Stats)
+	assert.Nil(t, entry.Lines, "line stats are only cached when requested")
 }
 
 func TestCollectStats_SecondCollectionHitsCacheWhenHeadUnchanged(t *testing.T) {
@@ -226,11 +227,16 @@ func TestCollectStats_SecondCollectionHitsCacheWhenHeadUnchanged(t *testing.T) {
 	require.NoError(t, err)
 
 	originalScan := collectRepoFromRepositoryFn
-	collectRepoFromRepositoryFn = func(_ *git.Repository, _ string, _, _ int, _ *time.Location, _ map[string]struct{}, _ BranchOption, _ func(string) string) (map[int]int, error) {
+	originalIndex := collectRepoFromIndexFn
+	collectRepoFromRepositoryFn = func(_ *git.Repository, _ string, _ repoQuery) (map[int]DayActivity, error) {
 		return nil, fmt.Errorf("scan should be skipped on cache hit")
 	}
+	collectRepoFromIndexFn = func(_ *git.Repository, _ string, _ repoQuery) (map[int]DayActivity, error) {
+		return nil, fmt.Errorf("index should be skipped on cache hit")
+	}
 	t.Cleanup(func() {
 		collectRepoFromRepositoryFn = originalScan
+		collectRepoFromIndexFn = originalIndex
 	})
 
 	second, err := CollectStats([]string{repoPath}, nil, start, end, BranchOption{}, nil, true)
@@ -238,6 +244,113 @@ func TestCollectStats_SecondCollectionHitsCacheWhenHeadUnchanged(t *testing.T) {
 	assert.Equal(t, first, second)
 }
 
+// ---------------------------------------------------------------------------
+// Cache invalidation for --branch / --all-branches
+// ---------------------------------------------------------------------------
+
+func TestCollectStats_Branch_SideBranchMoved_NotServedStale(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithMainAndFeature(t, repoPath, "test@example.com", base)
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	branch := BranchOption{Branch: "feature"}
+
+	first, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sumCounts(first))
+
+	commitOnBranch(t, repoPath, "feature", "main", base.Add(10*time.Minute))
+
+	second, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, sumCounts(second), "moving a non-HEAD branch must invalidate its cached result")
+}
+
+func TestCollectStats_AllBranches_SideBranchMoved_NotServedStale(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithMainAndFeature(t, repoPath, "test@example.com", base)
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	branch := BranchOption{AllBranches: true}
+
+	first, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, sumCounts(first))
+
+	commitOnBranch(t, repoPath, "feature", "main", base.Add(10*time.Minute))
+
+	second, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 5, sumCounts(second), "moving a side branch must invalidate the --all-branches result")
+}
+
+func TestCollectStats_AllBranches_NewBranch_NotServedStale(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithMainAndFeature(t, repoPath, "test@example.com", base)
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	branch := BranchOption{AllBranches: true}
+
+	first, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, sumCounts(first))
+
+	commitOnBranch(t, repoPath, "hotfix", "main", base.Add(20*time.Minute))
+
+	second, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 5, sumCounts(second), "a new local branch must invalidate the --all-branches result")
+}
+
+func TestCollectStats_HeadUnchanged_SideBranchMoved_HeadResultStillCached(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithMainAndFeature(t, repoPath, "test@example.com", base)
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+
+	first, err := CollectStats([]string{repoPath}, nil, start, end, BranchOption{}, nil, true)
+	require.NoError(t, err)
+
+	commitOnBranch(t, repoPath, "feature", "main", base.Add(10*time.Minute))
+
+	originalIndex := collectRepoFromIndexFn
+	collectRepoFromIndexFn = func(_ *git.Repository, _ string, _ repoQuery) (map[int]DayActivity, error) {
+		return nil, fmt.Errorf("HEAD result should still be served from cache")
+	}
+	t.Cleanup(func() {
+		collectRepoFromIndexFn = originalIndex
+	})
+
+	second, err := CollectStats([]string{repoPath}, nil, start, end, BranchOption{}, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestStartPointsFingerprint_OrderIndependent(t *testing.T) {
+	a := plumbing.NewHash("1111111111111111111111111111111111111111")
+	b := plumbing.NewHash("2222222222222222222222222222222222222222")
+
+	assert.Equal(t, a.String(), startPointsFingerprint([]plumbing.Hash{a}))
+	assert.Equal(t, startPointsFingerprint([]plumbing.Hash{a, b}), startPointsFingerprint([]plumbing.Hash{b, a}))
+	assert.NotEqual(t, startPointsFingerprint([]plumbing.Hash{a, b}), startPointsFingerprint([]plumbing.Hash{a}))
+}
+
 // ---------------------------------------------------------------------------
 // Alias merging
 // ---------------------------------------------------------------------------
@@ -255,12 +368,12 @@ func TestCollectStats_AliasMergeCountsCombined(t *testing.T) {
 	}
 
 	originalCollect := collectRepoFn
-	collectRepoFn = func(repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string, _ bool) (map[int]int, error) {
+	collectRepoFn = func(repoPath string, q repoQuery) (map[int]DayActivity, error) {
 		repository, ok := repos[repoPath]
 		if !ok {
 			return nil, fmt.Errorf("unknown repo %s", repoPath)
 		}
-		return collectRepoFromRepository(repository, repoPath, startDayKey, endDayKey, loc, emailSet, branch, normalizeEmail)
+		return collectRepoFromRepository(repository, repoPath, q)
 	}
 	t.Cleanup(func() {
 		collectRepoFn = originalCollect
@@ -305,7 +418,7 @@ func TestCollectRepo_OutOfOrderAuthorWhen_NoMissInRange(t *testing.T) {
 	startDayKey := dayKeyFromTime(time.Date(2024, 1, 10, 0, 0, 0, 0, loc), loc)
 	endDayKey := dayKeyFromTime(time.Date(2024, 1, 20, 0, 0, 0, 0, loc), loc)
 
-	got, err := collectRepoFromRepository(repo, "mem://out-of-order", startDayKey, endDayKey, loc, map[string]struct{}{}, BranchOption{}, nil)
+	got, err := collectRepoCounts(repo, "mem://out-of-order", repoQuery{startDayKey: startDayKey, endDayKey: endDayKey, loc: loc})
 	require.NoError(t, err)
 
 	want := map[int]int{
@@ -378,7 +491,7 @@ func TestCollectRepo_TimeRangeBoundaryScenarios(t *testing.T) {
 
 			startDayKey := dayKeyFromTime(tt.start, loc)
 			endDayKey := dayKeyFromTime(tt.end, loc)
-			got, err := collectRepoFromRepository(repo, "mem://boundary", startDayKey, endDayKey, loc, map[string]struct{}{}, BranchOption{}, nil)
+			got, err := collectRepoCounts(repo, "mem://boundary", repoQuery{startDayKey: startDayKey, endDayKey: endDayKey, loc: loc})
 			require.NoError(t, err)
 			assert.Equal(t, tt.want, got)
 		})
@@ -441,9 +554,9 @@ func TestCollectRepo_AllBranches_PruningIdempotent(t *testing.T) {
 	endDayKey := dayKeyFromTime(end, loc)
 
 	legacy := collectRepoAllBranchesWithoutPruning(t, repoPath, startDayKey, endDayKey, loc, map[string]struct{}{})
-	got, err := collectRepo(repoPath, startDayKey, endDayKey, loc, map[string]struct{}{}, BranchOption{AllBranches: true}, nil, false)
+	got, err := collectRepo(repoPath, repoQuery{startDayKey: startDayKey, endDayKey: endDayKey, loc: loc, branch: BranchOption{AllBranches: true}})
 	require.NoError(t, err)
-	assert.Equal(t, legacy, got, "pruning must not change --all-branches results")
+	assert.Equal(t, legacy, dayKeyCounts(got), "pruning must not change --all-branches results")
 }
 
 func TestCollectStats_Branch_MissingInOneRepo_Continue(t *testing.T) {
@@ -494,12 +607,12 @@ func TestCollectStatsByEmails_MemoryRepoBuckets(t *testing.T) {
 	}
 
 	originalCollect := collectRepoByEmailsFn
-	collectRepoByEmailsFn = func(repoPath string, startDayKey, endDayKey int, loc *time.Location, emailSet map[string]struct{}, branch BranchOption, normalizeEmail func(string) string, _ bool) (map[string]map[int]int, error) {
+	collectRepoByEmailsFn = func(repoPath string, q repoQuery) (map[string]map[int]DayActivity, error) {
 		repo, ok := repos[repoPath]
 		if !ok {
 			return nil, fmt.Errorf("unknown repo %s", repoPath)
 		}
-		return collectRepoByEmailsFromRepository(repo, repoPath, startDayKey, endDayKey, loc, emailSet, branch, normalizeEmail)
+		return collectRepoByEmailsFromRepository(repo, repoPath, q)
 	}
 	t.Cleanup(func() {
 		collectRepoByEmailsFn = originalCollect
@@ -535,6 +648,155 @@ func TestCollectStatsByEmails_MemoryRepoBuckets(t *testing.T) {
 	assert.Equal(t, 1, got[carol][time.Date(2024, 1, 4, 0, 0, 0, 0, loc)])
 }
 
+func TestCollectRepo_LineStats(t *testing.T) {
+	loc := time.UTC
+	repo, wt := initMemoryGitRepo(t)
+
+	commitMemoryFile(t, wt, "a.txt", "one\ntwo\n", "dev@example.com", time.Date(2024, 1, 1, 12, 0, 0, 0, loc))
+	commitMemoryFile(t, wt, "a.txt", "one\nthree\nfour\n", "dev@example.com", time.Date(2024, 1, 2, 12, 0, 0, 0, loc))
+	commitMemoryFile(t, wt, "b.txt", "x\n", "dev@example.com", time.Date(2024, 1, 2, 13, 0, 0, 0, loc))
+
+	q := repoQuery{
+		startDayKey: 20240101,
+		endDayKey:   20240131,
+		loc:         loc,
+		lineStats:   true,
+	}
+	got, err := collectRepoFromRepository(repo, "mem://lines", q)
+	require.NoError(t, err)
+
+	assert.Equal(t, DayActivity{Commits: 1, Lines: LineTotals{Additions: 2, Deletions: 0, Files: 1}}, got[20240101])
+	assert.Equal(t, DayActivity{Commits: 2, Lines: LineTotals{Additions: 3, Deletions: 1, Files: 2}}, got[20240102])
+
+	q.lineStats = false
+	counts, err := collectRepoFromRepository(repo, "mem://lines", q)
+	require.NoError(t, err)
+	assert.Equal(t, DayActivity{Commits: 2}, counts[20240102], "line stats must stay empty when not requested")
+}
+
+func TestCollectActivity_MergeModes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	createRepoWithMergeCommit(t, repoPath, time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local))
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{Repos: []string{repoPath}, Since: day, Until: day}
+
+	for _, useCache := range []bool{false, true} {
+		opts.UseCache = useCache
+		for mode, want := range map[MergeMode]DayActivity{
+			MergesInclude: {Commits: 3, Merges: 1},
+			MergesExclude: {Commits: 2},
+			MergesOnly:    {Commits: 1, Merges: 1},
+		} {
+			opts.Merges = mode
+			got, err := CollectActivity(opts)
+			require.NoError(t, err)
+			assert.Equal(t, want, SumActivity(got), "mode=%q useCache=%t", mode, useCache)
+
+			// 第二次命中结果缓存，合并提交数必须一并还原
+			if useCache {
+				cached, err := CollectActivity(opts)
+				require.NoError(t, err)
+				assert.Equal(t, want, SumActivity(cached), "cached mode=%q", mode)
+			}
+		}
+	}
+}
+
+func TestBuildRepoCacheKey_MergeModeChangesKey(t *testing.T) {
+	q := repoQuery{startDayKey: 20250101, endDayKey: 20250131}
+	include := buildRepoCacheKey("/tmp/repo", "abc", q)
+	q.merges = MergesExclude
+	exclude := buildRepoCacheKey("/tmp/repo", "abc", q)
+	assert.NotEqual(t, include.String(), exclude.String())
+}
+
+func TestBuildRepoCacheKey_TimezoneChangesKey(t *testing.T) {
+	q := repoQuery{startDayKey: 20250101, endDayKey: 20250131, loc: time.Local}
+	local := buildRepoCacheKey("/tmp/repo", "abc", q)
+	assert.Regexp(t, `^local:`, local.Timezone)
+	q.loc = time.UTC
+	utc := buildRepoCacheKey("/tmp/repo", "abc", q)
+	q.authorLocal = true
+	author := buildRepoCacheKey("/tmp/repo", "abc", q)
+	assert.Equal(t, "UTC", utc.Timezone)
+	assert.Equal(t, TimezoneAuthor, author.Timezone)
+	assert.NotEqual(t, local.String(), utc.String())
+	assert.NotEqual(t, utc.String(), author.String())
+}
+
+func TestCollectActivity_CacheKeyFollowsLocalZone(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalLocal := time.Local
+	t.Cleanup(func() { time.Local = originalLocal })
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+	// UTC 的 3/10 05:00 与 12:00；在 UTC+14 下后者属于 3/11
+	commitFile(t, wt, repoPath, "a.txt", "a", "dev@example.com", time.Date(2025, 3, 10, 5, 0, 0, 0, time.UTC))
+	commitFile(t, wt, repoPath, "b.txt", "b", "dev@example.com", time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+
+	collect := func() map[time.Time]int {
+		t.Helper()
+		opts := CollectOptions{
+			Repos:    []string{repoPath},
+			Since:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+			Until:    time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local),
+			UseCache: true,
+		}
+		got, err := CollectActivity(opts)
+		require.NoError(t, err)
+		return ActivityCounts(got)
+	}
+
+	time.Local = time.UTC
+	assert.Equal(t, map[time.Time]int{time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local): 2}, collect())
+
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	time.Local = kiritimati
+	assert.Equal(t, map[time.Time]int{
+		time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local): 1,
+		time.Date(2025, 3, 11, 0, 0, 0, 0, time.Local): 1,
+	}, collect(), "the UTC result cache must not be reused under another local zone")
+}
+
+func TestCollectActivity_TimezoneModes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+	// 两个提交在 UTC 下分属 3/10 与 3/11，按作者所在时区都在 3/10 深夜
+	commitFile(t, wt, repoPath, "a.txt", "a", "dev@example.com", time.Date(2025, 3, 10, 23, 30, 0, 0, time.FixedZone("JST", 9*3600)))
+	commitFile(t, wt, repoPath, "b.txt", "b", "dev@example.com", time.Date(2025, 3, 10, 20, 0, 0, 0, time.FixedZone("PST", -8*3600)))
+
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	for _, useCache := range []bool{false, true} {
+		opts := CollectOptions{Repos: []string{repoPath}, Since: since, Until: until, UseCache: useCache}
+		got, err := CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, map[time.Time]int{
+			time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC): 1,
+			time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC): 1,
+		}, ActivityCounts(got), "useCache=%t", useCache)
+
+		// 按作者时区划分时不得复用 UTC 的结果缓存
+		opts.AuthorLocal = true
+		got, err = CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, map[time.Time]int{
+			time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC): 2,
+		}, ActivityCounts(got), "useCache=%t", useCache)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Benchmarks
 // ---------------------------------------------------------------------------
@@ -583,7 +845,7 @@ func BenchmarkCollectRepo_HotPath(b *testing.B) {
 		b.Run(bm.name, func(b *testing.B) {
 			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				stats, err := collectRepoFromRepository(bm.repo, "mem://"+bm.name, bm.start, bm.end, loc, emailSet, bm.branchOpt, nil)
+				stats, err := collectRepoFromRepository(bm.repo, "mem://"+bm.name, repoQuery{startDayKey: bm.start, endDayKey: bm.end, loc: loc, emailSet: emailSet, branch: bm.branchOpt})
 				if err != nil {
 					b.Fatalf("collect failed: %v", err)
 				}
@@ -669,6 +931,42 @@ func commitFile(t *testing.T, wt *git.Worktree, repoPath, name, content, email s
 	require.NoError(t, err)
 }
 
+// createRepoWithMergeCommit 在前一天的 base 上分出 side，main 前进一次后与 side 合并：
+// when 当天共有 2 个普通提交与 1 个合并提交。
+func createRepoWithMergeCommit(t *testing.T, repoPath string, when time.Time) {
+	t.Helper()
+
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	commitFile(t, wt, repoPath, "base.txt", "base", "dev@example.com", when.AddDate(0, 0, -1))
+	base, err := r.Head()
+	require.NoError(t, err)
+
+	commitFile(t, wt, repoPath, "main.txt", "main", "dev@example.com", when)
+	mainHead, err := r.Head()
+	require.NoError(t, err)
+
+	// 在 base 上创建侧分支提交，再回到 main 做合并
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Hash: base.Hash()}))
+	commitFile(t, wt, repoPath, "side.txt", "side", "dev@example.com", when.Add(time.Minute))
+	side, err := r.Head()
+	require.NoError(t, err)
+
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: mainHead.Name()}))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "side.txt"), []byte("side"), 0o644))
+	_, err = wt.Add("side.txt")
+	require.NoError(t, err)
+	sig := &object.Signature{Name: "Test", Email: "dev@example.com", When: when.Add(2 * time.Minute)}
+	_, err = wt.Commit("merge side", &git.CommitOptions{
+		Author:    sig,
+		Committer: sig,
+		Parents:   []plumbing.Hash{mainHead.Hash(), side.Hash()},
+	})
+	require.NoError(t, err)
+}
+
 func createRepoWithMainAndFeature(t *testing.T, repoPath string, email string, base time.Time) {
 	t.Helper()
 
@@ -697,6 +995,29 @@ func createRepoWithMainAndFeature(t *testing.T, repoPath string, email string, b
 	commitFile(t, wt, repoPath, "file.txt", "main-2\n", email, base.Add(3*time.Minute))
 }
 
+// commitOnBranch 在指定分支上追加一个提交（分支不存在时创建），随后切回 returnTo，
+// 用于模拟非 HEAD 分支移动。
+func commitOnBranch(t *testing.T, repoPath, branchName, returnTo string, when time.Time) {
+	t.Helper()
+
+	r, err := git.PlainOpen(repoPath)
+	require.NoError(t, err)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	refName := plumbing.NewBranchReferenceName(branchName)
+	_, err = r.Reference(refName, true)
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{
+		Branch: refName,
+		Create: err != nil,
+	}))
+	commitFile(t, wt, repoPath, "file.txt", fmt.Sprintf("%s-%d\n", branchName, when.Unix()), "test@example.com", when)
+
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(returnTo),
+	}))
+}
+
 func createRepoWithBranchCommits(t *testing.T, repoPath, branchName string, commits int, email string, base time.Time) {
 	t.Helper()
 
@@ -732,14 +1053,30 @@ func sumCounts(stats map[time.Time]int) int {
 	return total
 }
 
-func toDayKeyStats(stats map[time.Time]int) map[int]int {
-	out := make(map[int]int, len(stats))
+func toDateStringStats(stats map[time.Time]int) map[string]int {
+	out := make(map[string]int, len(stats))
 	for day, count := range stats {
-		out[dayKeyFromTime(day, day.Location())] = count
+		out[day.Format("2006-01-02")] = count
 	}
 	return out
 }
 
+func dayKeyCounts(stats map[int]DayActivity) map[int]int {
+	out := make(map[int]int, len(stats))
+	for dayKey, act := range stats {
+		out[dayKey] = act.Commits
+	}
+	return out
+}
+
+func collectRepoCounts(repo *git.Repository, repoPath string, q repoQuery) (map[int]int, error) {
+	got, err := collectRepoFromRepository(repo, repoPath, q)
+	if err != nil {
+		return nil, err
+	}
+	return dayKeyCounts(got), nil
+}
+
 func sumDayKeyCounts(stats map[int]int) int {
 	total := 0
 	for _, c := range stats {
@@ -15,6 +15,7 @@ type CompareMetrics struct {
 	MostActiveWeekday        time.Weekday
 	MostActiveWeekdayCommits int
 	LongestStreakDays        int
+	Lines                    *LineTotals // 仅基于活动统计计算时填充
 }
 
 // CalculateCompareMetrics 基于按天聚合的提交统计计算 compare 指标。
@@ -36,6 +37,14 @@ func CalculateCompareMetrics(daily map[time.Time]int) CompareMetrics {
 	}
 }
 
+// CalculateCompareMetricsActivity 与 CalculateCompareMetrics 相同，但额外汇总代码行统计。
+func CalculateCompareMetricsActivity(daily map[time.Time]DayActivity) CompareMetrics {
+	m := CalculateCompareMetrics(ActivityCounts(daily))
+	lines := SumActivity(daily).Lines
+	m.Lines = &lines
+	return m
+}
+
 // PercentChange 表示一个变化百分比结果。
 // Defined=false 表示无法计算（例如 from=0 且 to!=0）。
 type PercentChange struct {
@@ -78,13 +87,15 @@ type Period struct {
 //   - YYYY-HN: 半年 (H1=1-6月, H2=7-12月)
 //   - YYYY-QN: 季度 (Q1-Q4)
 //   - YYYY-MM: 单月
-func ParsePeriod(s string) (Period, error) {
+//
+// 时间段的起止日期位于 loc，nil 表示本地时区。
+func ParsePeriod(s string, loc *time.Location) (Period, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return Period{}, fmt.Errorf("period is empty")
 	}
 
-	loc := timeNow().Location()
+	loc = currentTime(loc).Location()
 
 	// YYYY
 	if len(s) == 4 && isDigits(s) {
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ExportCommit 是 export 命令输出的单个提交记录（NDJSON 一行）。
+type ExportCommit struct {
+	Repo           string   `json:"repo"`
+	Hash           string   `json:"hash"`
+	AuthorName     string   `json:"authorName"`     // 经 mailmap 映射后的姓名
+	AuthorEmail    string   `json:"authorEmail"`    // 经 mailmap 与别名规范化后的邮箱
+	RawAuthorName  string   `json:"rawAuthorName"`  // 提交对象中的原始姓名
+	RawAuthorEmail string   `json:"rawAuthorEmail"` // 提交对象中的原始邮箱
+	AuthorTime     string   `json:"authorTime"`     // RFC 3339，保留作者时区偏移
+	CommitterTime  string   `json:"committerTime"`  // RFC 3339，保留提交者时区偏移
+	Parents        int      `json:"parents"`
+	Branch         string   `json:"branch"`              // 首个到达该提交的遍历起点（分支短名或 HEAD）
+	CoAuthors      []string `json:"coAuthors,omitempty"` // 开启 co-author 统计时的共同作者邮箱（已规范化）
+}
+
+// ExportCommits 按仓库顺序逐个遍历，对每个命中过滤条件的提交调用 emit，不在内存中累积结果。
+// 过滤口径与 CollectActivity 相同（邮箱、时间范围、分支、合并提交、路径、共同作者）；
+// 导出始终直接读取提交对象，不使用结果缓存与提交索引。
+// emit 返回错误时立即终止并返回该错误；单个仓库失败时跳过并继续，返回已完成的仓库与聚合错误。
+func ExportCommits(opts CollectOptions, emit func(ExportCommit) error) ([]string, error) {
+	q, err := newRepoQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var emitErr error
+	guarded := func(c ExportCommit) error {
+		if err := emit(c); err != nil {
+			emitErr = err
+			return err
+		}
+		return nil
+	}
+
+	done := make([]string, 0, len(opts.Repos))
+	var errs []error
+	for _, repoPath := range opts.Repos {
+		if err := exportRepo(repoPath, q.forRepo(repoPath), guarded); err != nil {
+			if emitErr != nil {
+				return done, emitErr
+			}
+			errs = append(errs, err)
+			continue
+		}
+		done = append(done, repoPath)
+	}
+	return done, errors.Join(errs...)
+}
+
+// exportRepo 导出单个仓库的提交。--all-branches 下逐个分支遍历以记录每个提交的来源分支，
+// 各分支共享已遍历的提交集合：遇到先前分支已遍历过的提交即剪枝，共同历史只遍历（及 diff）一次。
+func exportRepo(repoPath string, q repoQuery, emit func(ExportCommit) error) error {
+	repo, err := openRepository(repoPath)
+	if err != nil {
+		return err
+	}
+	points, err := collectNamedStartPoints(repo, repoPath, q.branch)
+	if err != nil {
+		return err
+	}
+	normalizeEmail := resolveNormalizeEmail(q.normalizeEmail)
+
+	var seen map[plumbing.Hash]struct{}
+	if len(points) > 1 {
+		seen = make(map[plumbing.Hash]struct{})
+	}
+	for _, sp := range points {
+		pq := q
+		pq.branch = BranchOption{}
+		pq.startPoints = []plumbing.Hash{sp.hash}
+		pq.seenCommits = seen
+		err := walkRepoCommits(repo, repoPath, pq, func(_ []string, _ int, c *object.Commit) error {
+			return emit(newExportCommit(repoPath, sp.name, c, q, normalizeEmail))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newExportCommit 将提交对象转换为导出记录。
+func newExportCommit(repoPath, branch string, c *object.Commit, q repoQuery, normalizeEmail func(string) string) ExportCommit {
+	name, email := q.mailmap.Resolve(c.Author.Name, c.Author.Email)
+	rec := ExportCommit{
+		Repo:           repoPath,
+		Hash:           c.Hash.String(),
+		AuthorName:     name,
+		AuthorEmail:    normalizeEmail(email),
+		RawAuthorName:  c.Author.Name,
+		RawAuthorEmail: c.Author.Email,
+		AuthorTime:     c.Author.When.Format(time.RFC3339),
+		CommitterTime:  c.Committer.When.Format(time.RFC3339),
+		Parents:        c.NumParents(),
+		Branch:         branch,
+	}
+	if q.coAuthors {
+		for _, raw := range parseCoAuthors(c.Message) {
+			rec.CoAuthors = append(rec.CoAuthors, normalizeEmail(q.mailmap.Email("", raw)))
+		}
+	}
+	return rec
+}
@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCommits_RecordsAndBranchSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	commitFile(t, wt, repoPath, "a.txt", "a", "Dev@Example.com", when)
+	head, err := r.Head()
+	require.NoError(t, err)
+
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	commitFile(t, wt, repoPath, "b.txt", "b", "dev@example.com", when.Add(time.Hour))
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{
+		Repos:          []string{repoPath},
+		Since:          day.AddDate(0, 0, -1),
+		Until:          day.AddDate(0, 0, 1),
+		AllBranch:      true,
+		NormalizeEmail: func(e string) string { return "canonical@example.com" },
+	}
+
+	var got []ExportCommit
+	done, err := ExportCommits(opts, func(c ExportCommit) error {
+		got = append(got, c)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{repoPath}, done)
+	require.Len(t, got, 2, "commits shared by branches are exported once")
+
+	// 分支按名称排序遍历：feature 先到达两个提交
+	assert.Equal(t, "feature", got[0].Branch)
+	assert.Equal(t, "feature", got[1].Branch)
+
+	base := got[1]
+	assert.Equal(t, head.Hash().String(), base.Hash)
+	assert.Equal(t, repoPath, base.Repo)
+	assert.Equal(t, "Dev@Example.com", base.RawAuthorEmail)
+	assert.Equal(t, "canonical@example.com", base.AuthorEmail)
+	assert.Equal(t, "Test", base.AuthorName)
+	assert.Equal(t, "2025-06-02T12:00:00+08:00", base.AuthorTime)
+	assert.Equal(t, "2025-06-02T12:00:00+08:00", base.CommitterTime)
+	assert.Equal(t, 0, base.Parents)
+
+	opts.AllBranch = false
+	opts.Branch = "master"
+	got = got[:0]
+	_, err = ExportCommits(opts, func(c ExportCommit) error {
+		got = append(got, c)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "master", got[0].Branch)
+}
+
+func TestExportCommits_EmitErrorAborts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repos := make([]string, 0, 2)
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	for i := 0; i < 2; i++ {
+		repoPath := t.TempDir()
+		r := initRepo(t, repoPath)
+		wt, err := r.Worktree()
+		require.NoError(t, err)
+		commitFile(t, wt, repoPath, "a.txt", "a", "dev@example.com", when)
+		repos = append(repos, repoPath)
+	}
+
+	boom := errors.New("disk full")
+	calls := 0
+	done, err := ExportCommits(CollectOptions{Repos: repos, Since: when, Until: when}, func(ExportCommit) error {
+		calls++
+		return boom
+	})
+	require.ErrorIs(t, err, boom)
+	assert.Empty(t, done)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExportCommits_AllBranchesWalksSharedHistoryOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	// master、main、feature 三个分支共享初始提交，main 与 feature 共享 main-1，共 4 个不同提交
+	createRepoWithMainAndFeature(t, repoPath, "dev@example.com", base)
+
+	diffs := 0
+	originalFiles := commitFilesFn
+	commitFilesFn = func(c *object.Commit) ([]string, error) {
+		diffs++
+		return originalFiles(c)
+	}
+	t.Cleanup(func() {
+		commitFilesFn = originalFiles
+	})
+
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{Repos: []string{repoPath}, Since: day, Until: day, AllBranch: true, Paths: []string{"file.txt"}}
+	branches := make(map[string]string)
+	_, err := ExportCommits(opts, func(c ExportCommit) error {
+		branches[c.Hash] = c.Branch
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, branches, 4)
+	assert.Equal(t, 4, diffs, "commits shared by branches are walked and diffed once")
+}
@@ -0,0 +1,272 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"git-visible/internal/cache"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var collectRepoFromIndexFn = collectRepoFromIndex
+var collectRepoByEmailsFromIndexFn = collectRepoByEmailsFromIndex
+
+// repoIndex 是加载到内存中的提交索引，附带 hash -> 下标的查找表。
+type repoIndex struct {
+	data   *cache.CommitIndex
+	byHash map[plumbing.Hash]int
+	dirty  bool
+	// repo 用于按需读取提交对象（代码行统计、变更文件），由 openRepoIndex 设置。
+	repo     *git.Repository
+	repoPath string
+}
+
+// loadRepoIndex 读取仓库的提交索引；索引缺失、损坏或版本不匹配时从空索引开始。
+func loadRepoIndex(repoPath string) *repoIndex {
+	data, err := cache.LoadIndex(repoPath)
+	if err != nil {
+		data = &cache.CommitIndex{RepoPath: repoPath}
+	}
+
+	ri := &repoIndex{
+		data:   data,
+		byHash: make(map[plumbing.Hash]int, len(data.Commits)),
+	}
+	for i, ic := range data.Commits {
+		ri.byHash[plumbing.NewHash(ic.Hash)] = i
+	}
+	return ri
+}
+
+// openRepoIndex 加载索引并从当前起点增量扩展，返回索引与本次查询的起点。
+func openRepoIndex(repo *git.Repository, repoPath string, q repoQuery) (*repoIndex, []plumbing.Hash, error) {
+	startPoints, err := q.resolveStartPoints(repo, repoPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ri := loadRepoIndex(repoPath)
+	ri.repo = repo
+	ri.repoPath = repoPath
+	if err := ri.extend(repo, repoPath, startPoints); err != nil {
+		return nil, nil, err
+	}
+	return ri, startPoints, nil
+}
+
+// extend 从起点向下遍历，只读取尚未索引的提交。
+// 遇到已索引的提交即停止向下，因此 HEAD 前进后的更新代价与新增提交数成正比。
+// 浅克隆边界处缺失的父提交会被跳过，与 git log 的行为一致。
+func (ri *repoIndex) extend(repo *git.Repository, repoPath string, startPoints []plumbing.Hash) error {
+	stack := slices.Clone(startPoints)
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := ri.byHash[h]; ok {
+			continue
+		}
+
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrObjectNotFound) && !slices.Contains(startPoints, h) {
+				continue
+			}
+			return fmt.Errorf("index repo %s commit %s: %w", repoPath, h, err)
+		}
+
+		_, offset := c.Author.When.Zone()
+		ic := cache.IndexedCommit{
+			Hash:      h.String(),
+			Name:      c.Author.Name,
+			Email:     c.Author.Email,
+			CoAuthors: parseCoAuthors(c.Message),
+			When:      c.Author.When.Unix(),
+			Offset:    offset,
+		}
+		for _, p := range c.ParentHashes {
+			ic.Parents = append(ic.Parents, p.String())
+			stack = append(stack, p)
+		}
+
+		ri.byHash[h] = len(ri.data.Commits)
+		ri.data.Commits = append(ri.data.Commits, ic)
+		ri.dirty = true
+	}
+
+	tips := make([]string, 0, len(startPoints))
+	for _, h := range startPoints {
+		tips = append(tips, h.String())
+	}
+	if !slices.Equal(tips, ri.data.Tips) {
+		ri.data.Tips = tips
+		ri.dirty = true
+	}
+	return nil
+}
+
+// walk 在索引内遍历起点可达的提交（按 hash 去重），过滤口径与 walkRepoCommits 相同。
+func (ri *repoIndex) walk(startPoints []plumbing.Hash, q repoQuery, visitor func(emails []string, dayKey int, pos int) error) error {
+	normalizeEmail := resolveNormalizeEmail(q.normalizeEmail)
+	emailBuf := make([]string, 0, 1)
+
+	seen := make([]bool, len(ri.data.Commits))
+	stack := make([]int, 0, len(startPoints))
+	for _, h := range startPoints {
+		if pos, ok := ri.byHash[h]; ok {
+			stack = append(stack, pos)
+		}
+	}
+
+	for len(stack) > 0 {
+		pos := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[pos] {
+			continue
+		}
+		seen[pos] = true
+
+		ic := &ri.data.Commits[pos]
+		for _, p := range ic.Parents {
+			if parentPos, ok := ri.byHash[plumbing.NewHash(p)]; ok && !seen[parentPos] {
+				stack = append(stack, parentPos)
+			}
+		}
+
+		emails, dayKey, ok := matchCommit(q, normalizeEmail, emailBuf, ic.Name, ic.Email, ic.CoAuthors, indexedAuthorTime(ic), len(ic.Parents))
+		if !ok {
+			continue
+		}
+		if q.paths != nil {
+			files, err := ri.files(pos)
+			if err != nil {
+				return err
+			}
+			if !q.paths.MatchAny(files) {
+				continue
+			}
+		}
+		if err := visitor(emails, dayKey, pos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activity 返回索引中单个提交贡献的活动量。
+// 代码行统计按需计算一次后写回索引，后续查询无需再做 diff。
+func (ri *repoIndex) activity(repo *git.Repository, repoPath string, pos int, lineStats bool) (DayActivity, error) {
+	ic := &ri.data.Commits[pos]
+	act := DayActivity{Commits: 1}
+	if len(ic.Parents) > 1 {
+		act.Merges = 1
+		return act, nil
+	}
+	if !lineStats {
+		return act, nil
+	}
+
+	if ic.Lines == nil {
+		c, err := repo.CommitObject(plumbing.NewHash(ic.Hash))
+		if err != nil {
+			return DayActivity{}, fmt.Errorf("line stats repo %s commit %s: %w", repoPath, ic.Hash, err)
+		}
+		computed, err := commitActivity(c, true)
+		if err != nil {
+			return DayActivity{}, fmt.Errorf("line stats repo %s commit %s: %w", repoPath, ic.Hash, err)
+		}
+		ic.Lines = &cache.LineCounts{
+			Additions: computed.Lines.Additions,
+			Deletions: computed.Lines.Deletions,
+			Files:     computed.Lines.Files,
+		}
+		ri.dirty = true
+	}
+
+	act.Lines = LineTotals{Additions: ic.Lines.Additions, Deletions: ic.Lines.Deletions, Files: ic.Lines.Files}
+	return act, nil
+}
+
+// files 返回索引中单个提交的变更文件列表。
+// 首次按需 diff 后写回索引，并以 FilesDone 标记已计算，变更文件为空（如干净合并）时同样不再重复 diff。
+func (ri *repoIndex) files(pos int) ([]string, error) {
+	ic := &ri.data.Commits[pos]
+	if ic.FilesDone {
+		return ic.Files, nil
+	}
+
+	c, err := ri.repo.CommitObject(plumbing.NewHash(ic.Hash))
+	if err != nil {
+		return nil, fmt.Errorf("diff repo %s commit %s: %w", ri.repoPath, ic.Hash, err)
+	}
+	files, err := commitFilesFn(c)
+	if err != nil {
+		return nil, fmt.Errorf("diff repo %s commit %s: %w", ri.repoPath, ic.Hash, err)
+	}
+	ic.Files = files
+	ic.FilesDone = true
+	ri.dirty = true
+	return files, nil
+}
+
+// save 在索引有变化时写回磁盘。写入失败不影响本次统计结果。
+func (ri *repoIndex) save() {
+	if !ri.dirty {
+		return
+	}
+	if err := cache.SaveIndex(ri.data); err == nil {
+		ri.dirty = false
+	}
+}
+
+// indexedAuthorTime 还原索引提交的作者时间（保留原始时区偏移）。
+func indexedAuthorTime(ic *cache.IndexedCommit) time.Time {
+	return time.Unix(ic.When, 0).In(time.FixedZone("", ic.Offset))
+}
+
+// collectRepoFromIndex 基于提交索引收集单个仓库的按天活动。
+func collectRepoFromIndex(repo *git.Repository, repoPath string, q repoQuery) (map[int]DayActivity, error) {
+	ri, startPoints, err := openRepoIndex(repo, repoPath, q)
+	if err != nil {
+		return nil, err
+	}
+	defer ri.save()
+
+	out := make(map[int]DayActivity)
+	if err := ri.walk(startPoints, q, func(_ []string, dayKey int, pos int) error {
+		act, err := ri.activity(repo, repoPath, pos, q.lineStats)
+		if err != nil {
+			return err
+		}
+		out[dayKey] = out[dayKey].Add(act)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// collectRepoByEmailsFromIndex 基于提交索引收集单个仓库按邮箱分桶的按天活动。
+func collectRepoByEmailsFromIndex(repo *git.Repository, repoPath string, q repoQuery) (map[string]map[int]DayActivity, error) {
+	ri, startPoints, err := openRepoIndex(repo, repoPath, q)
+	if err != nil {
+		return nil, err
+	}
+	defer ri.save()
+
+	out := make(map[string]map[int]DayActivity)
+	if err := ri.walk(startPoints, q, func(emails []string, dayKey int, pos int) error {
+		act, err := ri.activity(repo, repoPath, pos, q.lineStats)
+		if err != nil {
+			return err
+		}
+		addEmailActivity(out, emails, dayKey, act)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
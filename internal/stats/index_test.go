@@ -0,0 +1,164 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"git-visible/internal/cache"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectStats_IndexExtendsIncrementally(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithBranchCommits(t, repoPath, "main", 3, "dev@example.com", base)
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local)
+
+	got, err := CollectStats([]string{repoPath}, nil, start, end, BranchOption{}, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sumCounts(got))
+
+	idx, err := cache.LoadIndex(repoPath)
+	require.NoError(t, err)
+	require.Len(t, idx.Commits, 3)
+
+	// 篡改索引中已有提交的邮箱：若后续查询重新遍历了旧提交，篡改会被覆盖。
+	for i := range idx.Commits {
+		idx.Commits[i].Email = "indexed@example.com"
+	}
+	require.NoError(t, cache.SaveIndex(idx))
+
+	repo, err := git.PlainOpen(repoPath)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	commitFile(t, wt, repoPath, "file.txt", "new-1\n", "dev@example.com", base.AddDate(0, 0, 2))
+	commitFile(t, wt, repoPath, "file.txt", "new-2\n", "dev@example.com", base.AddDate(0, 0, 3))
+
+	originalScan := collectRepoFromRepositoryFn
+	collectRepoFromRepositoryFn = func(_ *git.Repository, _ string, _ repoQuery) (map[int]DayActivity, error) {
+		return nil, fmt.Errorf("full scan should not run when the index is enabled")
+	}
+	t.Cleanup(func() {
+		collectRepoFromRepositoryFn = originalScan
+	})
+
+	byEmail, err := CollectStatsByEmails([]string{repoPath}, []string{"indexed@example.com", "dev@example.com"}, start, end, BranchOption{}, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sumCounts(byEmail["indexed@example.com"]), "old commits must be answered from the index")
+	assert.Equal(t, 2, sumCounts(byEmail["dev@example.com"]), "new commits must be read from the repository")
+
+	idx, err = cache.LoadIndex(repoPath)
+	require.NoError(t, err)
+	assert.Len(t, idx.Commits, 5)
+
+	// 不同的时间范围同样直接由索引回答。
+	narrow, err := CollectStats([]string{repoPath}, nil, base.AddDate(0, 0, 2), base.AddDate(0, 0, 3), BranchOption{}, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sumCounts(narrow))
+}
+
+func TestCollectStats_IndexMatchesFullScan(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithMainAndFeature(t, repoPath, "dev@example.com", base)
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+
+	for _, branch := range []BranchOption{{}, {Branch: "feature"}, {AllBranches: true}} {
+		scanned, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, false)
+		require.NoError(t, err)
+		indexed, err := CollectStats([]string{repoPath}, nil, start, end, branch, nil, true)
+		require.NoError(t, err)
+		assert.Equal(t, scanned, indexed, "branch=%+v", branch)
+	}
+}
+
+func TestCollectActivity_IndexPersistsLineStats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	createRepoWithBranchCommits(t, repoPath, "main", 2, "dev@example.com", base)
+
+	opts := CollectOptions{
+		Repos:     []string{repoPath},
+		Since:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		Until:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		UseCache:  true,
+		LineStats: true,
+	}
+	got, err := CollectActivity(opts)
+	require.NoError(t, err)
+	total := SumActivity(got)
+	assert.Equal(t, 2, total.Commits)
+	assert.Equal(t, LineTotals{Additions: 2, Deletions: 1, Files: 2}, total.Lines)
+
+	idx, err := cache.LoadIndex(repoPath)
+	require.NoError(t, err)
+	for _, ic := range idx.Commits {
+		assert.NotNil(t, ic.Lines, "commit %s should carry line stats", ic.Hash)
+	}
+}
+
+func TestCollectActivity_IndexKeepsEmptyMergeFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	// 干净合并：相对每个父提交都有变更的文件为空
+	createRepoWithMergeCommit(t, repoPath, when)
+
+	diffs := 0
+	originalFiles := commitFilesFn
+	commitFilesFn = func(c *object.Commit) ([]string, error) {
+		diffs++
+		return originalFiles(c)
+	}
+	t.Cleanup(func() {
+		commitFilesFn = originalFiles
+	})
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{Repos: []string{repoPath}, Since: day, Until: day, Paths: []string{"side.txt"}, UseCache: true}
+	got, err := CollectActivity(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, SumActivity(got).Commits)
+	assert.Equal(t, 3, diffs, "first run diffs each commit in range once")
+
+	idx, err := cache.LoadIndex(repoPath)
+	require.NoError(t, err)
+	var merge *cache.IndexedCommit
+	for i := range idx.Commits {
+		if len(idx.Commits[i].Parents) > 1 {
+			merge = &idx.Commits[i]
+		}
+	}
+	require.NotNil(t, merge)
+	assert.True(t, merge.FilesDone)
+	assert.Empty(t, merge.Files)
+
+	// 换一个时间范围避开结果缓存，直接由索引回答
+	diffs = 0
+	opts.Until = day.AddDate(0, 0, 1)
+	got, err = CollectActivity(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, SumActivity(got).Commits)
+	assert.Zero(t, diffs, "second run must not diff again")
+
+	reloaded, err := cache.LoadIndex(repoPath)
+	require.NoError(t, err)
+	assert.True(t, idx.UpdatedAt.Equal(reloaded.UpdatedAt), "second run must not rewrite the index")
+}
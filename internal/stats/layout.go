@@ -0,0 +1,214 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layout 表示终端热力图的排版方式。
+type Layout int
+
+const (
+	// LayoutAuto 根据终端宽度自动选择：wide 放得下用 wide，其次 compact，再次 stacked，过窄时 vertical。
+	LayoutAuto Layout = iota
+	// LayoutWide 每周一列、每列 4 个字符（默认版面）。
+	LayoutWide
+	// LayoutCompact 每周一列、每列 2 个字符。
+	LayoutCompact
+	// LayoutStacked 将范围按月份边界拆成多段上下堆叠，每段使用 wide 版面。
+	LayoutStacked
+	// LayoutVertical 每周一行、星期为列。
+	LayoutVertical
+)
+
+// layoutNames 是各排版方式的名称，下标与 Layout 取值一致。
+var layoutNames = [...]string{"auto", "wide", "compact", "stacked", "vertical"}
+
+// ParseLayout 解析排版方式名称（大小写不敏感），空字符串为 auto。
+func ParseLayout(s string) (Layout, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return LayoutAuto, nil
+	}
+	for i, name := range layoutNames {
+		if s == name {
+			return Layout(i), nil
+		}
+	}
+	return LayoutAuto, fmt.Errorf("unsupported layout %q (supported: %s)", s, strings.Join(layoutNames[:], ", "))
+}
+
+// String 返回排版方式名称。
+func (l Layout) String() string {
+	if l < 0 || int(l) >= len(layoutNames) {
+		return layoutNames[LayoutAuto]
+	}
+	return layoutNames[l]
+}
+
+const (
+	labelWidth       = 4  // 左侧星期标签宽度
+	wideColWidth     = 4  // wide 版面每周列宽
+	compactColWidth  = 2  // compact 版面每周列宽
+	defaultBlockCols = 27 // stacked 版面未知终端宽度时每段的周数（约半年）
+	minStackedCols   = 13 // stacked 每段至少容纳的周数（约一个季度），更窄时改用 vertical
+)
+
+// resolve 将 auto 解析为适合 width 列终端的具体排版；width <= 0 表示宽度未知，使用 wide。
+func (l Layout) resolve(weeks, width int) Layout {
+	if l != LayoutAuto {
+		return l
+	}
+	switch {
+	case width <= 0 || labelWidth+weeks*wideColWidth <= width:
+		return LayoutWide
+	case labelWidth+weeks*compactColWidth <= width:
+		return LayoutCompact
+	case (width-labelWidth)/wideColWidth >= minStackedCols:
+		return LayoutStacked
+	default:
+		return LayoutVertical
+	}
+}
+
+// writeGrid 按 opts.Layout（auto 时结合 opts.Width）写入热力图主体，不含图例与摘要。
+func writeGrid(b *strings.Builder, stats map[time.Time]int, g heatmapGrid, style cellStyle, scale levelScale, opts HeatmapOptions) {
+	switch opts.Layout.resolve(len(g.weekStarts), opts.Width) {
+	case LayoutCompact:
+		writeHorizontal(b, stats, g, style.narrow(), scale, compactColWidth)
+	case LayoutStacked:
+		for i, block := range g.stackedBlocks(opts.Width) {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			writeHorizontal(b, stats, g.sub(block[0], block[1]), style, scale, wideColWidth)
+		}
+	case LayoutVertical:
+		writeVertical(b, stats, g, style, scale)
+	default:
+		writeHorizontal(b, stats, g, style, scale, wideColWidth)
+	}
+}
+
+// stackedBlocks 将 weeks 列拆分为 [from, to) 区间，每段不超过 width 可容纳的周数，
+// 并尽量在月份开始处断开。
+func (g heatmapGrid) stackedBlocks(width int) [][2]int {
+	perBlock := defaultBlockCols
+	if width > 0 {
+		perBlock = max(1, (width-labelWidth)/wideColWidth)
+	}
+
+	starts := make(map[int]bool)
+	for _, m := range g.monthLabels() {
+		starts[m.col] = true
+	}
+
+	var blocks [][2]int
+	for from := 0; from < len(g.weekStarts); {
+		to := min(from+perBlock, len(g.weekStarts))
+		if to < len(g.weekStarts) {
+			for cut := to; cut > from+perBlock/2; cut-- {
+				if starts[cut] {
+					to = cut
+					break
+				}
+			}
+		}
+		blocks = append(blocks, [2]int{from, to})
+		from = to
+	}
+	return blocks
+}
+
+// sub 返回仅包含 [from, to) 列的版面。
+func (g heatmapGrid) sub(from, to int) heatmapGrid {
+	g.weekStarts = g.weekStarts[from:to]
+	return g
+}
+
+// writeHorizontal 按星期为行、周为列写入热力图（wide/compact 版面）。
+func writeHorizontal(b *strings.Builder, stats map[time.Time]int, g heatmapGrid, style cellStyle, scale levelScale, colWidth int) {
+	writeMonthHeaderCols(b, g, colWidth)
+
+	gap := strings.Repeat(" ", colWidth-style.width())
+	blank := strings.Repeat(" ", colWidth)
+	for row := 0; row < 7; row++ {
+		b.WriteString(weekdayLabel(g.weekday(row))) // 左侧星期标签
+
+		for col := range g.weekStarts {
+			day, ok := g.day(col, row)
+			// 跳过范围外的日期
+			if !ok {
+				b.WriteString(blank)
+				continue
+			}
+			b.WriteString(g.paintDay(style, scale, stats, day) + gap)
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// writeMonthHeaderCols 写入月份标题行，colWidth 为每周列宽。
+// 列宽不足以放下相邻两个月份缩写时，丢弃较早的标题（通常是范围开头的残月）。
+func writeMonthHeaderCols(b *strings.Builder, g heatmapGrid, colWidth int) {
+	line := []byte(strings.Repeat(" ", labelWidth+len(g.weekStarts)*colWidth))
+	prev := -1
+	for _, m := range g.monthLabels() {
+		name := m.name
+		pos := labelWidth + m.col*colWidth
+		// 最后几列放不下完整缩写时不标注，保持每行宽度一致
+		if pos+len(name) > len(line) {
+			break
+		}
+		if prev >= 0 && pos <= prev+len(name) {
+			copy(line[prev:], "   ")
+		}
+		copy(line[pos:], name)
+		prev = pos
+	}
+	b.Write(line)
+	b.WriteByte('\n')
+}
+
+// verticalHeader 返回 vertical 版面的星期标题行（如 "    Su  Mo  Tu ..."），从 weekStart 开始。
+func verticalHeader(weekStart time.Weekday) string {
+	var b strings.Builder
+	b.WriteString("    ")
+	for i := 0; i < 7; i++ {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(((weekStart + time.Weekday(i)) % 7).String()[:2])
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// writeVertical 按周为行、星期为列写入热力图，每月第一周的行首标注月份。
+func writeVertical(b *strings.Builder, stats map[time.Time]int, g heatmapGrid, style cellStyle, scale levelScale) {
+	b.WriteString(verticalHeader(g.weekStart))
+
+	gap := strings.Repeat(" ", wideColWidth-style.width())
+	lastMonth := time.Month(0)
+	for col := range g.weekStarts {
+		if month := g.colMonth(col); month != lastMonth {
+			lastMonth = month
+			b.WriteString(lastMonth.String()[:3] + " ")
+		} else {
+			b.WriteString("    ")
+		}
+
+		var row strings.Builder
+		for wd := 0; wd < 7; wd++ {
+			day, ok := g.day(col, wd)
+			if !ok {
+				row.WriteString("    ")
+				continue
+			}
+			row.WriteString(g.paintDay(style, scale, stats, day) + gap)
+		}
+		b.WriteString(strings.TrimRight(row.String(), " "))
+		b.WriteByte('\n')
+	}
+}
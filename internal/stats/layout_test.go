@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLayout(t *testing.T) {
+	for _, name := range []string{"", "auto", "Wide", "compact", "stacked", "vertical"} {
+		l, err := ParseLayout(name)
+		require.NoError(t, err, name)
+		if name != "" {
+			assert.Equal(t, strings.ToLower(name), l.String())
+		}
+	}
+
+	_, err := ParseLayout("diagonal")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported layout")
+}
+
+func TestLayoutResolve_PicksLayoutThatFits(t *testing.T) {
+	// 53 周：wide 需 216 列，compact 需 110 列
+	assert.Equal(t, LayoutWide, LayoutAuto.resolve(53, 0), "unknown width keeps wide")
+	assert.Equal(t, LayoutWide, LayoutAuto.resolve(53, 220))
+	assert.Equal(t, LayoutCompact, LayoutAuto.resolve(53, 120))
+	assert.Equal(t, LayoutStacked, LayoutAuto.resolve(53, 80))
+	assert.Equal(t, LayoutVertical, LayoutAuto.resolve(53, 40))
+	assert.Equal(t, LayoutVertical, LayoutVertical.resolve(53, 220), "explicit layout wins")
+}
+
+func TestRenderHeatmapWithOptions_Layouts(t *testing.T) {
+	loc := time.Local
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, loc)
+	stats := map[time.Time]int{
+		time.Date(2024, 3, 5, 0, 0, 0, 0, loc): 3,
+	}
+	render := func(layout Layout, width int) []string {
+		out := RenderHeatmapWithOptions(stats, HeatmapOptions{Since: start, Until: end, Layout: layout, Width: width, NoColor: true, Charset: CharsetASCII})
+		return strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	}
+	maxWidth := func(lines []string) int {
+		w := 0
+		for _, line := range lines {
+			w = max(w, len(line))
+		}
+		return w
+	}
+
+	wide := render(LayoutAuto, 0)
+	require.Len(t, wide, 8)
+	assert.Equal(t, 4+53*4, maxWidth(wide))
+
+	compact := render(LayoutAuto, 120)
+	require.Len(t, compact, 8)
+	assert.LessOrEqual(t, maxWidth(compact), 120)
+	assert.Contains(t, compact[0], "Jan")
+	assert.Contains(t, compact[0], "Dec")
+
+	stacked := render(LayoutAuto, 80)
+	assert.LessOrEqual(t, maxWidth(stacked), 80)
+	assert.Greater(t, len(stacked), 8, "stacked renders several blocks")
+	assert.Equal(t, "", stacked[8], "blocks are separated by a blank line")
+	assert.True(t, strings.HasPrefix(stacked[9], "    May "), "blocks break at a month boundary: %q", stacked[9])
+
+	vertical := render(LayoutVertical, 0)
+	assert.Equal(t, strings.TrimSuffix(verticalHeader(time.Sunday), "\n"), vertical[0])
+	assert.Len(t, vertical, 1+53)
+	assert.True(t, strings.HasPrefix(vertical[1], "Jan "), "first week starts on Sunday 2023-12-31 but is labelled by its first in-range day")
+	assert.True(t, strings.HasPrefix(vertical[10], "Mar ..  ..  oo"), "2024-03-05 is the Tuesday of the week starting Mar 3: %q", vertical[10])
+}
+
+func TestWriteMonthHeaderCols_DropsCrowdedLabel(t *testing.T) {
+	loc := time.Local
+	// 第 0 列为 6/30（周日），第 1 列起是 7 月
+	g, ok := newHeatmapGrid(time.Date(2024, 6, 30, 0, 0, 0, 0, loc), time.Date(2024, 7, 20, 0, 0, 0, 0, loc), time.Sunday)
+	require.True(t, ok)
+	require.Len(t, g.weekStarts, 3)
+
+	var b strings.Builder
+	writeMonthHeaderCols(&b, g, compactColWidth)
+	assert.Equal(t, "      Jul \n", b.String())
+}
+
+func TestRenderHeatmapWithOptions_WeekStartMonday(t *testing.T) {
+	loc := time.UTC
+	opts := HeatmapOptions{
+		Since:     time.Date(2025, 6, 1, 0, 0, 0, 0, loc), // 周日
+		Until:     time.Date(2025, 6, 30, 0, 0, 0, 0, loc),
+		NoColor:   true,
+		Charset:   CharsetASCII,
+		Layout:    LayoutWide,
+		WeekStart: time.Monday,
+	}
+	stats := map[time.Time]int{time.Date(2025, 6, 2, 0, 0, 0, 0, loc): 1} // 周一
+
+	lines := strings.Split(RenderHeatmapWithOptions(stats, opts), "\n")
+	// 周一为第一行；周日 6/1 落在上一周（从 5/26 开始）的最后一行
+	assert.Equal(t, "Mon     oo  ..  ..  ..  ..  ", lines[1])
+	assert.Equal(t, "Wed     ..  ..  ..  ..      ", lines[3])
+	assert.Equal(t, "    ..  ..  ..  ..  ..      ", lines[7])
+
+	opts.Layout = LayoutVertical
+	vertical := strings.Split(RenderHeatmapWithOptions(stats, opts), "\n")
+	assert.Equal(t, "    Mo  Tu  We  Th  Fr  Sa  Su", vertical[0])
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// LogCommit 是 log 命令列出的单个提交。
+type LogCommit struct {
+	Repo    string    `json:"repo"`
+	Hash    string    `json:"hash"`
+	Time    time.Time `json:"time"`   // 作者时间，按划分日期使用的时区表示
+	Author  string    `json:"author"` // 经 mailmap 与别名规范化后的作者邮箱
+	Subject string    `json:"subject"`
+}
+
+// ShortHash 返回 7 位短哈希。
+func (c LogCommit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// CollectCommits 并发遍历各仓库，返回命中过滤条件的提交（按时间从新到旧）。
+// 过滤口径与 CollectActivity 相同（邮箱、别名、时间范围、分支、合并提交、路径、共同作者），
+// 始终直接读取提交对象，不使用结果缓存。
+// 单个仓库失败时跳过并继续，返回已完成的仓库与聚合错误。
+func CollectCommits(opts CollectOptions) ([]LogCommit, []string, error) {
+	var commits []LogCommit
+	done, err := collectCommonGeneric(opts, collectRepoCommits, func(_ string, repoCommits []LogCommit) {
+		commits = append(commits, repoCommits...)
+	})
+	SortLogCommits(commits)
+	return commits, done, err
+}
+
+// SortLogCommits 将提交按时间从新到旧排序，时间相同时按仓库与哈希排序，保证输出稳定。
+func SortLogCommits(commits []LogCommit) {
+	sort.Slice(commits, func(i, j int) bool {
+		a, b := commits[i], commits[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.After(b.Time)
+		}
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		return a.Hash < b.Hash
+	})
+}
+
+// collectRepoCommits 收集单个仓库中命中过滤条件的提交。
+func collectRepoCommits(repoPath string, q repoQuery) ([]LogCommit, error) {
+	repo, err := openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	normalizeEmail := resolveNormalizeEmail(q.normalizeEmail)
+
+	var commits []LogCommit
+	err = walkRepoCommits(repo, repoPath, q, func(_ []string, _ int, c *object.Commit) error {
+		commits = append(commits, LogCommit{
+			Repo:    repoPath,
+			Hash:    c.Hash.String(),
+			Time:    c.Author.When.In(q.dayLocation(c.Author.When)),
+			Author:  normalizeEmail(q.mailmap.Email(c.Author.Name, c.Author.Email)),
+			Subject: commitSubject(c.Message),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// commitSubject 返回提交信息的第一行（标题）。
+func commitSubject(message string) string {
+	message = strings.TrimSpace(message)
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		message = message[:i]
+	}
+	return strings.TrimSpace(message)
+}
@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectCommits_FiltersAndSortsNewestFirst(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	repoA := t.TempDir()
+	wtA := worktree(t, initRepo(t, repoA))
+	commitMessage(t, wtA, repoA, "a.txt", "me@example.com", "Fix login\n\nlonger body", day.Add(9*time.Hour))
+	commitMessage(t, wtA, repoA, "b.txt", "other@example.com", "Not mine", day.Add(10*time.Hour))
+	commitMessage(t, wtA, repoA, "c.txt", "me@example.com", "Next day", day.AddDate(0, 0, 1).Add(9*time.Hour))
+
+	repoB := t.TempDir()
+	wtB := worktree(t, initRepo(t, repoB))
+	commitMessage(t, wtB, repoB, "a.txt", "Me@Example.com", "Add API", day.Add(14*time.Hour))
+
+	commits, done, err := CollectCommits(CollectOptions{
+		Repos:  []string{repoA, repoB},
+		Emails: []string{"me@example.com"},
+		Since:  day,
+		Until:  day,
+		NormalizeEmail: func(e string) string {
+			return "me@example.com"
+		},
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{repoA, repoB}, done)
+	require.Len(t, commits, 3, "normalize maps every author to me, only the next-day commit is out of range")
+
+	assert.Equal(t, []string{"Add API", "Not mine", "Fix login"}, []string{commits[0].Subject, commits[1].Subject, commits[2].Subject})
+	assert.Equal(t, repoB, commits[0].Repo)
+	assert.Equal(t, "me@example.com", commits[0].Author)
+	assert.Len(t, commits[0].Hash, 40)
+	assert.Len(t, commits[0].ShortHash(), 7)
+	assert.Equal(t, 14, commits[0].Time.Hour())
+}
+
+func TestCollectCommits_TimeUsesDayLocation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	wt := worktree(t, initRepo(t, repoPath))
+	// 作者时区 UTC+9 的 3 月 15 日 08:00 即 UTC 的 3 月 14 日 23:00
+	when := time.Date(2025, 3, 15, 8, 0, 0, 0, time.FixedZone("UTC+9", 9*3600))
+	commitMessage(t, wt, repoPath, "a.txt", "me@example.com", "Late night", when)
+
+	utcDay := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	commits, _, err := CollectCommits(CollectOptions{Repos: []string{repoPath}, Since: utcDay, Until: utcDay})
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "2025-03-14T23:00:00Z", commits[0].Time.Format(time.RFC3339))
+
+	authorDay := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	commits, _, err = CollectCommits(CollectOptions{Repos: []string{repoPath}, Since: authorDay, Until: authorDay, AuthorLocal: true})
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "2025-03-15T08:00:00+09:00", commits[0].Time.Format(time.RFC3339))
+}
+
+func TestCommitSubject(t *testing.T) {
+	assert.Equal(t, "Fix bug", commitSubject("Fix bug\n\nDetails\n"))
+	assert.Equal(t, "Single", commitSubject("  Single  "))
+	assert.Equal(t, "", commitSubject(""))
+}
+
+func worktree(t *testing.T, r *git.Repository) *git.Worktree {
+	t.Helper()
+
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+	return wt
+}
+
+func commitMessage(t *testing.T, wt *git.Worktree, repoPath, name, email, message string, when time.Time) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, name), []byte(message), 0o644))
+	_, err := wt.Add(name)
+	require.NoError(t, err)
+
+	sig := &object.Signature{Name: "Test", Email: email, When: when}
+	_, err = wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig})
+	require.NoError(t, err)
+}
@@ -0,0 +1,245 @@
+package stats
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MailmapFileName 是仓库工作区内 mailmap 文件的文件名。
+const MailmapFileName = ".mailmap"
+
+// Mailmap 保存 git mailmap 规则（见 gitmailmap(5)），用于把提交中的姓名/邮箱映射为规范身份。
+// 邮箱与姓名匹配均大小写不敏感；同时指定提交姓名与邮箱的规则优先于只指定邮箱的规则。
+type Mailmap struct {
+	byEmail     map[string]mailmapEntry // key: 小写提交邮箱
+	byNameEmail map[string]mailmapEntry // key: 小写提交姓名 + "\x00" + 小写提交邮箱
+}
+
+// mailmapEntry 是一条规则的替换目标，空字段表示保留原值。
+type mailmapEntry struct {
+	name  string
+	email string
+}
+
+// ParseMailmap 解析 mailmap 内容，支持以下四种形式（"#" 开头为注释）：
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+//
+// 无法解析的行会被跳过并汇总到返回的 error 中（带行号），其余规则仍然生效。
+func ParseMailmap(r io.Reader) (*Mailmap, error) {
+	m := &Mailmap{
+		byEmail:     make(map[string]mailmapEntry),
+		byNameEmail: make(map[string]mailmapEntry),
+	}
+
+	var errs []error
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := m.addLine(line); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", lineNo, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return m, errors.Join(errs...)
+}
+
+// LoadMailmap 读取并解析 mailmap 文件。文件不存在时返回 (nil, err)，
+// 解析错误时返回已解析的部分规则与 error。
+func LoadMailmap(path string) (*Mailmap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := ParseMailmap(f)
+	if err != nil {
+		return m, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// RepoMailmapPath 返回仓库工作区根目录下 .mailmap 的路径。
+func RepoMailmapPath(repoPath string) string {
+	return filepath.Join(repoPath, MailmapFileName)
+}
+
+// addLine 解析一行规则并写入映射表。
+func (m *Mailmap) addLine(line string) error {
+	name1, email1, rest, ok := parseNameEmail(line)
+	if !ok {
+		return fmt.Errorf("expected \"Name <email>\": %q", line)
+	}
+
+	// 第二组可选：缺省时为 "Proper Name <commit@email>" 形式
+	name2, email2, rest2, ok := parseNameEmail(rest)
+	if !ok {
+		if tail := strings.TrimSpace(rest); tail != "" && !strings.HasPrefix(tail, "#") {
+			return fmt.Errorf("unexpected trailing text %q", tail)
+		}
+		if name1 == "" || email1 == "" {
+			return fmt.Errorf("rule maps nothing: %q", line)
+		}
+		putMailmapEntry(m.byEmail, strings.ToLower(email1), mailmapEntry{name: name1})
+		return nil
+	}
+	if tail := strings.TrimSpace(rest2); tail != "" && !strings.HasPrefix(tail, "#") {
+		return fmt.Errorf("unexpected trailing text %q", tail)
+	}
+	if email2 == "" {
+		return fmt.Errorf("empty commit email: %q", line)
+	}
+
+	entry := mailmapEntry{name: name1, email: email1}
+	if name2 != "" {
+		putMailmapEntry(m.byNameEmail, mailmapNameKey(name2, email2), entry)
+	} else {
+		putMailmapEntry(m.byEmail, strings.ToLower(email2), entry)
+	}
+	return nil
+}
+
+// putMailmapEntry 写入规则；同一 key 已存在时只覆盖非空字段（与 git 一致，
+// 允许 "Name <email>" 与 "<proper> <email>" 两行分别提供姓名与邮箱）。
+func putMailmapEntry(dst map[string]mailmapEntry, key string, entry mailmapEntry) {
+	cur := dst[key]
+	if entry.name != "" {
+		cur.name = entry.name
+	}
+	if entry.email != "" {
+		cur.email = entry.email
+	}
+	dst[key] = cur
+}
+
+// parseNameEmail 从 s 开头解析 "Name <email>"，返回剩余部分。
+func parseNameEmail(s string) (name, email, rest string, ok bool) {
+	open := strings.IndexByte(s, '<')
+	if open < 0 {
+		return "", "", s, false
+	}
+	closeIdx := strings.IndexByte(s[open:], '>')
+	if closeIdx < 0 {
+		return "", "", s, false
+	}
+	closeIdx += open
+	name = strings.TrimSpace(s[:open])
+	email = strings.TrimSpace(s[open+1 : closeIdx])
+	return name, email, s[closeIdx+1:], true
+}
+
+// mailmapNameKey 返回按 "姓名 + 邮箱" 匹配的规则 key。
+func mailmapNameKey(name, email string) string {
+	return strings.ToLower(name) + "\x00" + strings.ToLower(email)
+}
+
+// Resolve 返回映射后的姓名与邮箱；nil Mailmap 或无匹配规则时原样返回。
+func (m *Mailmap) Resolve(name, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+	var (
+		entry mailmapEntry
+		ok    bool
+	)
+	if len(m.byNameEmail) > 0 {
+		entry, ok = m.byNameEmail[mailmapNameKey(name, email)]
+	}
+	if !ok {
+		entry, ok = m.byEmail[strings.ToLower(email)]
+	}
+	if !ok {
+		return name, email
+	}
+	if entry.name != "" {
+		name = entry.name
+	}
+	if entry.email != "" {
+		email = entry.email
+	}
+	return name, email
+}
+
+// Email 返回映射后的邮箱。
+func (m *Mailmap) Email(name, email string) string {
+	_, email = m.Resolve(name, email)
+	return email
+}
+
+// Len 返回规则条数。
+func (m *Mailmap) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.byEmail) + len(m.byNameEmail)
+}
+
+// Merge 返回合并后的 Mailmap，other 中的规则覆盖 m 中的同名规则（与 git 后读取者优先一致）。
+// 两者均为空时返回 nil。
+func (m *Mailmap) Merge(other *Mailmap) *Mailmap {
+	if m.Len() == 0 {
+		if other.Len() == 0 {
+			return nil
+		}
+		return other
+	}
+	if other.Len() == 0 {
+		return m
+	}
+	out := &Mailmap{
+		byEmail:     make(map[string]mailmapEntry, len(m.byEmail)+len(other.byEmail)),
+		byNameEmail: make(map[string]mailmapEntry, len(m.byNameEmail)+len(other.byNameEmail)),
+	}
+	for _, src := range []*Mailmap{m, other} {
+		for k, v := range src.byEmail {
+			putMailmapEntry(out.byEmail, k, v)
+		}
+		for k, v := range src.byNameEmail {
+			putMailmapEntry(out.byNameEmail, k, v)
+		}
+	}
+	return out
+}
+
+// Fingerprint 返回规则集合的摘要，用于缓存键；空规则返回 ""。
+func (m *Mailmap) Fingerprint() string {
+	if m.Len() == 0 {
+		return ""
+	}
+	lines := make([]string, 0, m.Len())
+	for k, v := range m.byEmail {
+		lines = append(lines, "e\x00"+k+"\x00"+v.name+"\x00"+v.email)
+	}
+	for k, v := range m.byNameEmail {
+		lines = append(lines, "n\x00"+k+"\x00"+v.name+"\x00"+v.email)
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadRepoMailmap 加载仓库 .mailmap 并与全局 mailmap 合并（全局规则优先，与 git 的 mailmap.file 一致）。
+// 收集时对缺失或部分无法解析的文件保持宽容，问题由 doctor 报告。
+func loadRepoMailmap(repoPath string, global *Mailmap) *Mailmap {
+	local, _ := LoadMailmap(RepoMailmapPath(repoPath))
+	return local.Merge(global)
+}
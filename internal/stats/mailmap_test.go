@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-visible/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMailmap_Forms(t *testing.T) {
+	m, err := ParseMailmap(strings.NewReader(`# comment
+Alice Smith <alice@old.com>
+<alice@company.com> <Alice@Old.com>
+Bob <bob@company.com> <bob@gmail.com>
+Carol <carol@company.com> carol <shared@example.com>   # trailing comment
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, m.Len(), "two rules for alice@old.com share one entry")
+
+	name, email := m.Resolve("alice", "ALICE@old.com")
+	assert.Equal(t, "Alice Smith", name, "name and email rules for the same address are combined")
+	assert.Equal(t, "alice@company.com", email)
+
+	name, email = m.Resolve("bobby", "bob@gmail.com")
+	assert.Equal(t, "Bob", name)
+	assert.Equal(t, "bob@company.com", email)
+
+	assert.Equal(t, "carol@company.com", m.Email("Carol", "shared@example.com"))
+	assert.Equal(t, "shared@example.com", m.Email("Dave", "shared@example.com"), "name-qualified rule only matches that name")
+	assert.Equal(t, "nobody@example.com", m.Email("", "nobody@example.com"))
+
+	var nilMap *Mailmap
+	assert.Equal(t, "x@example.com", nilMap.Email("X", "x@example.com"))
+	assert.Empty(t, nilMap.Fingerprint())
+}
+
+func TestParseMailmap_InvalidLinesReported(t *testing.T) {
+	m, err := ParseMailmap(strings.NewReader("no brackets here\n<ok@example.com> <old@example.com>\nName <unterminated\n<only@example.com>\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1")
+	assert.Contains(t, err.Error(), "line 3")
+	assert.Contains(t, err.Error(), "line 4")
+	assert.Equal(t, "ok@example.com", m.Email("", "old@example.com"), "valid lines still apply")
+}
+
+func TestMailmap_MergeLaterWins(t *testing.T) {
+	local, err := ParseMailmap(strings.NewReader("<repo@example.com> <old@example.com>\n"))
+	require.NoError(t, err)
+	global, err := ParseMailmap(strings.NewReader("<global@example.com> <old@example.com>\n"))
+	require.NoError(t, err)
+
+	merged := local.Merge(global)
+	assert.Equal(t, "global@example.com", merged.Email("", "old@example.com"))
+	assert.NotEqual(t, local.Fingerprint(), merged.Fingerprint())
+	assert.Same(t, local, local.Merge(nil))
+	assert.Nil(t, (*Mailmap)(nil).Merge(nil))
+}
+
+func TestCollectActivity_RepoMailmapBeforeFilterAndAlias(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	commitFile(t, wt, repoPath, "a.txt", "a", "alice@company.com", when)
+	commitFile(t, wt, repoPath, "b.txt", "b", "alice@old-laptop.local", when.Add(time.Minute))
+	commitFile(t, wt, repoPath, "c.txt", "c", "alice@gmail.com", when.Add(2*time.Minute))
+
+	cfg := &config.Config{Aliases: []config.Alias{{Name: "Alice", Emails: []string{"alice@company.com", "alice@gmail.com"}}}}
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{
+		Repos:          []string{repoPath},
+		Emails:         []string{"alice@company.com"},
+		Since:          day,
+		Until:          day,
+		NormalizeEmail: cfg.NormalizeEmail,
+	}
+
+	for _, useCache := range []bool{false, true} {
+		opts.UseCache = useCache
+		require.NoError(t, os.RemoveAll(RepoMailmapPath(repoPath)))
+
+		got, err := CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, SumActivity(got).Commits, "useCache=%t", useCache)
+
+		// mailmap 把旧邮箱映射到 gmail，再经别名归入 company，缓存键随规则变化而失效
+		require.NoError(t, os.WriteFile(RepoMailmapPath(repoPath), []byte("<alice@gmail.com> <alice@old-laptop.local>\n"), 0o644))
+		got, err = CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 3, SumActivity(got).Commits, "useCache=%t", useCache)
+	}
+
+	// 全局 mailmap 覆盖仓库规则
+	global := filepath.Join(t.TempDir(), "mailmap")
+	require.NoError(t, os.WriteFile(global, []byte("<someone@else.com> <alice@old-laptop.local>\n"), 0o644))
+	opts.MailmapFile = global
+	got, err := CollectActivity(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, SumActivity(got).Commits)
+}
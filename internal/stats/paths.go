@@ -0,0 +1,358 @@
+package stats
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var collectRepoByPathFn = collectRepoByPath
+
+// PathFilter 按变更文件路径过滤提交（类似 git log -- <pathspec>）。
+// 提交只要有一个变更文件命中任一包含规则、且该文件未被排除规则命中即被统计；
+// 没有包含规则时默认包含全部文件。
+type PathFilter struct {
+	include []string
+	exclude []string
+}
+
+// ParsePathFilter 解析 --path 规则列表，空列表返回 nil（不过滤）。
+// 规则语法：
+//   - 不含通配符的规则匹配该路径本身及其下的所有文件（如 "services/api"）；
+//   - "*"、"?"、"[...]" 匹配单个路径段内的字符，"**" 匹配任意层目录；
+//   - 以 "!" 开头表示排除。
+func ParsePathFilter(patterns []string) (*PathFilter, error) {
+	f := &PathFilter{}
+	for _, raw := range patterns {
+		p := strings.TrimSpace(raw)
+		if p == "" {
+			continue
+		}
+		negate := strings.HasPrefix(p, "!")
+		if negate {
+			p = strings.TrimSpace(p[1:])
+		}
+		p = normalizePathPattern(p)
+		if p == "" {
+			return nil, fmt.Errorf("invalid path pattern %q", raw)
+		}
+		for _, seg := range strings.Split(p, "/") {
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, fmt.Errorf("invalid path pattern %q: %w", raw, err)
+			}
+		}
+		if negate {
+			f.exclude = append(f.exclude, p)
+		} else {
+			f.include = append(f.include, p)
+		}
+	}
+	if len(f.include) == 0 && len(f.exclude) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Patterns 返回规范化并排序后的规则（排除规则带 "!" 前缀），用于缓存键。
+func (f *PathFilter) Patterns() []string {
+	if f == nil {
+		return nil
+	}
+	out := make([]string, 0, len(f.include)+len(f.exclude))
+	out = append(out, f.include...)
+	for _, p := range f.exclude {
+		out = append(out, "!"+p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Match 判断单个文件是否被过滤器选中。nil 过滤器选中全部文件。
+func (f *PathFilter) Match(file string) bool {
+	if f == nil {
+		return true
+	}
+	for _, p := range f.exclude {
+		if matchPathPattern(p, file) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, p := range f.include {
+		if matchPathPattern(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAny 判断变更文件列表中是否至少有一个文件被选中。
+func (f *PathFilter) MatchAny(files []string) bool {
+	for _, file := range files {
+		if f.Match(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePathPattern 统一使用 "/" 分隔，去掉开头的 "./"、"/" 与结尾的 "/"。
+func normalizePathPattern(p string) string {
+	p = filepath.ToSlash(strings.TrimSpace(p))
+	p = strings.TrimPrefix(p, "./")
+	p = strings.Trim(p, "/")
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// matchPathPattern 判断文件路径是否匹配规则。
+// 规则匹配路径本身或其任一祖先目录即视为命中，因此 "docs" 与 "docs/**" 等价。
+func matchPathPattern(pattern, file string) bool {
+	patSegs := strings.Split(pattern, "/")
+	fileSegs := strings.Split(file, "/")
+	for n := len(fileSegs); n >= 1; n-- {
+		if matchSegments(patSegs, fileSegs[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments 逐段匹配，"**" 可匹配零个或多个路径段。
+func matchSegments(pat, segs []string) bool {
+	if len(pat) == 0 {
+		return len(segs) == 0
+	}
+	if pat[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pat[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pat[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pat[1:], segs[1:])
+}
+
+// commitFilesFn 计算提交的变更文件，测试中可替换以统计 diff 次数。
+var commitFilesFn = commitFiles
+
+// commitFiles 返回提交变更的文件路径（根提交相对空树）。
+// 合并提交只返回相对每个父提交都有变更的文件，与 git log -- <path> 的历史简化一致：
+// 侧分支带入、与某个父提交相同的文件已计入侧分支上的提交，不再重复计入合并提交。
+func commitFiles(c *object.Commit) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	if c.NumParents() == 0 {
+		return diffTreeFiles(nil, tree)
+	}
+
+	var files []string
+	for i := 0; i < c.NumParents(); i++ {
+		parent, err := c.Parent(i)
+		if err != nil {
+			return nil, err
+		}
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+		changed, err := diffTreeFiles(parentTree, tree)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			files = changed
+			continue
+		}
+		files = intersectSorted(files, changed)
+	}
+	return files, nil
+}
+
+// diffTreeFiles 返回两棵树之间变更的文件路径（已排序、去重），from 为 nil 时相对空树。
+func diffTreeFiles(from, to *object.Tree) ([]string, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(changes))
+	seen := make(map[string]struct{}, len(changes))
+	for _, ch := range changes {
+		for _, name := range []string{ch.From.Name, ch.To.Name} {
+			if name == "" {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// intersectSorted 返回两个已排序切片的交集，结果复用 a 的底层数组。
+func intersectSorted(a, b []string) []string {
+	out := a[:0]
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// pathBucket 返回文件所在目录的前 depth 段，位于仓库根目录的文件返回 "."。
+func pathBucket(file string, depth int) string {
+	dir := path.Dir(file)
+	if dir == "." || depth <= 0 {
+		return "."
+	}
+	segs := strings.Split(dir, "/")
+	if len(segs) > depth {
+		segs = segs[:depth]
+	}
+	return strings.Join(segs, "/")
+}
+
+// commitPathBuckets 返回提交涉及的目录桶（已按过滤器筛选、去重）。
+func commitPathBuckets(files []string, filter *PathFilter, depth int) []string {
+	seen := make(map[string]struct{})
+	out := make([]string, 0, 1)
+	for _, file := range files {
+		if !filter.Match(file) {
+			continue
+		}
+		bucket := pathBucket(file, depth)
+		if _, ok := seen[bucket]; ok {
+			continue
+		}
+		seen[bucket] = struct{}{}
+		out = append(out, bucket)
+	}
+	return out
+}
+
+// CollectActivityByPath 按仓库内的目录收集按天活动统计。
+// 返回 map 的 key 为 "仓库路径/目录"（目录取变更文件路径的前 depth 段），
+// 仓库根目录下的文件归入仓库路径本身。一个提交涉及多个目录时在每个目录各计一次。
+func CollectActivityByPath(opts CollectOptions, depth int) (map[string]map[time.Time]DayActivity, error) {
+	if depth <= 0 {
+		return nil, fmt.Errorf("path depth must be > 0, got %d", depth)
+	}
+
+	loc := opts.Until.Location()
+	out := make(map[string]map[time.Time]DayActivity)
+	collectFn := func(repoPath string, q repoQuery) (map[string]map[int]DayActivity, error) {
+		return collectRepoByPathFn(repoPath, q, depth)
+	}
+	done, err := collectCommonGeneric[map[string]map[int]DayActivity](opts, collectFn, func(repoPath string, byDir map[string]map[int]DayActivity) {
+		for dir, daily := range byDir {
+			key := filepath.Join(repoPath, filepath.FromSlash(dir))
+			bucket := out[key]
+			if bucket == nil {
+				bucket = make(map[time.Time]DayActivity, len(daily))
+				out[key] = bucket
+			}
+			for dayKey, act := range daily {
+				day := dayKeyToTime(dayKey, loc)
+				bucket[day] = bucket[day].Add(act)
+			}
+		}
+	})
+	if err != nil && len(done) == 0 {
+		return nil, err
+	}
+	return out, err
+}
+
+// collectRepoByPath 收集单个仓库按目录分桶的按天活动。
+// 按目录统计不使用结果缓存；启用缓存时基于提交索引（变更文件列表会写回索引）。
+func collectRepoByPath(repoPath string, q repoQuery, depth int) (map[string]map[int]DayActivity, error) {
+	repo, err := openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]map[int]DayActivity)
+	add := func(files []string, dayKey int, act DayActivity) {
+		for _, dir := range commitPathBuckets(files, q.paths, depth) {
+			daily := out[dir]
+			if daily == nil {
+				daily = make(map[int]DayActivity)
+				out[dir] = daily
+			}
+			daily[dayKey] = daily[dayKey].Add(act)
+		}
+	}
+
+	if q.useCache {
+		ri, startPoints, err := openRepoIndex(repo, repoPath, q)
+		if err != nil {
+			return nil, err
+		}
+		defer ri.save()
+
+		err = ri.walk(startPoints, q, func(_ []string, dayKey int, pos int) error {
+			files, err := ri.files(pos)
+			if err != nil {
+				return err
+			}
+			act, err := ri.activity(repo, repoPath, pos, false)
+			if err != nil {
+				return err
+			}
+			add(files, dayKey, act)
+			return nil
+		})
+		return out, err
+	}
+
+	// 路径过滤与分桶都需要变更文件：由 visitor 统一 diff 一次并自行过滤，walk 不再重复计算
+	walkQuery := q
+	walkQuery.paths = nil
+	err = walkRepoCommits(repo, repoPath, walkQuery, func(_ []string, dayKey int, c *object.Commit) error {
+		files, err := commitFilesFn(c)
+		if err != nil {
+			return fmt.Errorf("diff repo %s commit %s: %w", repoPath, c.Hash, err)
+		}
+		if q.paths != nil && !q.paths.MatchAny(files) {
+			return nil
+		}
+		act, err := commitActivity(c, false)
+		if err != nil {
+			return err
+		}
+		add(files, dayKey, act)
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,181 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathFilter_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		file     string
+		want     bool
+	}{
+		{"plain dir matches descendants", []string{"services/api"}, "services/api/handler/user.go", true},
+		{"plain dir does not match sibling prefix", []string{"services/api"}, "services/api-gateway/main.go", false},
+		{"trailing slash and ./ normalized", []string{"./services/api/"}, "services/api/main.go", true},
+		{"single star stays in segment", []string{"services/*/main.go"}, "services/api/main.go", true},
+		{"single star does not cross segments", []string{"services/*.go"}, "services/api/main.go", false},
+		{"double star crosses segments", []string{"**/*.go"}, "services/api/main.go", true},
+		{"double star matches zero segments", []string{"services/**/main.go"}, "services/main.go", true},
+		{"glob on directory segment", []string{"doc?"}, "docs/a/b.md", true},
+		{"negation only excludes", []string{"!vendor"}, "vendor/lib/x.go", false},
+		{"negation only keeps others", []string{"!vendor"}, "cmd/main.go", true},
+		{"negation wins over include", []string{"services", "!services/legacy"}, "services/legacy/x.go", false},
+		{"include with negation keeps rest", []string{"services", "!services/legacy"}, "services/api/x.go", true},
+		{"not included", []string{"services"}, "docs/readme.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParsePathFilter(tt.patterns)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.Match(tt.file))
+		})
+	}
+}
+
+func TestParsePathFilter_EmptyAndInvalid(t *testing.T) {
+	f, err := ParsePathFilter([]string{"", "  "})
+	require.NoError(t, err)
+	assert.Nil(t, f, "blank patterns mean no filtering")
+	assert.True(t, f.Match("any/file.go"))
+
+	_, err = ParsePathFilter([]string{"src/[a-"})
+	require.Error(t, err)
+
+	_, err = ParsePathFilter([]string{"!"})
+	require.Error(t, err)
+}
+
+func TestPathFilter_PatternsOrderIndependent(t *testing.T) {
+	a, err := ParsePathFilter([]string{"b", "!c", "a"})
+	require.NoError(t, err)
+	b, err := ParsePathFilter([]string{"a", "b", "!c"})
+	require.NoError(t, err)
+	assert.Equal(t, a.Patterns(), b.Patterns())
+}
+
+func TestPathBucket(t *testing.T) {
+	assert.Equal(t, ".", pathBucket("README.md", 1))
+	assert.Equal(t, "services", pathBucket("services/api/main.go", 1))
+	assert.Equal(t, "services/api", pathBucket("services/api/main.go", 2))
+	assert.Equal(t, "services/api", pathBucket("services/api/main.go", 5))
+}
+
+func TestCollectActivity_PathFilterAndByPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	for _, dir := range []string{"services/api", "services/web", "docs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoPath, filepath.FromSlash(dir)), 0o755))
+	}
+	commitFile(t, wt, repoPath, "services/api/main.go", "api", "dev@example.com", when)
+	commitFile(t, wt, repoPath, "services/web/main.go", "web", "dev@example.com", when.Add(time.Minute))
+	commitFile(t, wt, repoPath, "docs/readme.md", "docs", "dev@example.com", when.Add(2*time.Minute))
+	commitFile(t, wt, repoPath, "README.md", "root", "dev@example.com", when.Add(3*time.Minute))
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{Repos: []string{repoPath}, Since: day, Until: day}
+
+	for _, useCache := range []bool{false, true} {
+		opts.UseCache = useCache
+
+		opts.Paths = []string{"services", "!services/web"}
+		got, err := CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 1, SumActivity(got).Commits, "useCache=%t", useCache)
+
+		opts.Paths = []string{"**/*.go"}
+		got, err = CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, SumActivity(got).Commits, "useCache=%t", useCache)
+
+		opts.Paths = nil
+		byPath, err := CollectActivityByPath(opts, 1)
+		require.NoError(t, err)
+		counts := make(map[string]int)
+		for key, daily := range byPath {
+			counts[key] = SumActivity(daily).Commits
+		}
+		assert.Equal(t, map[string]int{
+			filepath.Join(repoPath, "services"): 2,
+			filepath.Join(repoPath, "docs"):     1,
+			repoPath:                            1,
+		}, counts, "useCache=%t", useCache)
+	}
+}
+
+func TestCollectActivity_PathFilterSkipsFilesMergedFromSideBranch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	// 合并提交相对第一个父提交带入了 side.txt，但该改动已由侧分支提交计入
+	createRepoWithMergeCommit(t, repoPath, when)
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{Repos: []string{repoPath}, Since: day, Until: day}
+
+	for _, useCache := range []bool{false, true} {
+		opts.UseCache = useCache
+
+		opts.Paths = []string{"side.txt"}
+		got, err := CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 1, SumActivity(got).Commits, "useCache=%t", useCache)
+		assert.Equal(t, 0, SumActivity(got).Merges, "useCache=%t", useCache)
+
+		opts.Paths = []string{"*.txt"}
+		got, err = CollectActivity(opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, SumActivity(got).Commits, "useCache=%t", useCache)
+	}
+}
+
+func TestCollectActivityByPath_DiffsEachCommitOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	when := time.Date(2025, 6, 2, 12, 0, 0, 0, time.Local)
+	for _, dir := range []string{"services/api", "docs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoPath, filepath.FromSlash(dir)), 0o755))
+	}
+	commitFile(t, wt, repoPath, "services/api/main.go", "api", "dev@example.com", when)
+	commitFile(t, wt, repoPath, "docs/readme.md", "docs", "dev@example.com", when.Add(time.Minute))
+	commitFile(t, wt, repoPath, "README.md", "root", "dev@example.com", when.Add(2*time.Minute))
+
+	diffs := 0
+	originalFiles := commitFilesFn
+	commitFilesFn = func(c *object.Commit) ([]string, error) {
+		diffs++
+		return originalFiles(c)
+	}
+	t.Cleanup(func() {
+		commitFilesFn = originalFiles
+	})
+
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	opts := CollectOptions{Repos: []string{repoPath}, Since: day, Until: day, Paths: []string{"services"}}
+	byPath, err := CollectActivityByPath(opts, 1)
+	require.NoError(t, err)
+	require.Len(t, byPath, 1)
+	assert.Equal(t, 1, SumActivity(byPath[filepath.Join(repoPath, "services")]).Commits)
+	assert.Equal(t, 3, diffs, "each commit in range is diffed exactly once")
+}
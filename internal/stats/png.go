@@ -0,0 +1,366 @@
+package stats
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PNGOptions 控制 PNG 热力图的内容与样式，字段含义与 SVG 导出一致（共用 svg 配置）。
+type PNGOptions = SVGOptions
+
+// 内置点阵字体的字形尺寸（像素，未缩放）。
+const (
+	glyphWidth   = 5
+	glyphHeight  = 7
+	glyphAdvance = glyphWidth + 1
+)
+
+// glyphs5x7 是 ASCII 0x20-0x7E 的 5x7 点阵字体，每个字符 5 列，每列一个字节，bit0 为最上一行。
+var glyphs5x7 = [95][glyphWidth]byte{
+	{0x00, 0x00, 0x00, 0x00, 0x00}, // ' '
+	{0x00, 0x00, 0x5F, 0x00, 0x00}, // '!'
+	{0x00, 0x07, 0x00, 0x07, 0x00}, // '"'
+	{0x14, 0x7F, 0x14, 0x7F, 0x14}, // '#'
+	{0x24, 0x2A, 0x7F, 0x2A, 0x12}, // '$'
+	{0x23, 0x13, 0x08, 0x64, 0x62}, // '%'
+	{0x36, 0x49, 0x55, 0x22, 0x50}, // '&'
+	{0x00, 0x05, 0x03, 0x00, 0x00}, // '\''
+	{0x00, 0x1C, 0x22, 0x41, 0x00}, // '('
+	{0x00, 0x41, 0x22, 0x1C, 0x00}, // ')'
+	{0x08, 0x2A, 0x1C, 0x2A, 0x08}, // '*'
+	{0x08, 0x08, 0x3E, 0x08, 0x08}, // '+'
+	{0x00, 0x50, 0x30, 0x00, 0x00}, // ','
+	{0x08, 0x08, 0x08, 0x08, 0x08}, // '-'
+	{0x00, 0x60, 0x60, 0x00, 0x00}, // '.'
+	{0x20, 0x10, 0x08, 0x04, 0x02}, // '/'
+	{0x3E, 0x51, 0x49, 0x45, 0x3E}, // '0'
+	{0x00, 0x42, 0x7F, 0x40, 0x00}, // '1'
+	{0x42, 0x61, 0x51, 0x49, 0x46}, // '2'
+	{0x21, 0x41, 0x45, 0x4B, 0x31}, // '3'
+	{0x18, 0x14, 0x12, 0x7F, 0x10}, // '4'
+	{0x27, 0x45, 0x45, 0x45, 0x39}, // '5'
+	{0x3C, 0x4A, 0x49, 0x49, 0x30}, // '6'
+	{0x01, 0x71, 0x09, 0x05, 0x03}, // '7'
+	{0x36, 0x49, 0x49, 0x49, 0x36}, // '8'
+	{0x06, 0x49, 0x49, 0x29, 0x1E}, // '9'
+	{0x00, 0x36, 0x36, 0x00, 0x00}, // ':'
+	{0x00, 0x56, 0x36, 0x00, 0x00}, // ';'
+	{0x08, 0x14, 0x22, 0x41, 0x00}, // '<'
+	{0x14, 0x14, 0x14, 0x14, 0x14}, // '='
+	{0x00, 0x41, 0x22, 0x14, 0x08}, // '>'
+	{0x02, 0x01, 0x51, 0x09, 0x06}, // '?'
+	{0x32, 0x49, 0x79, 0x41, 0x3E}, // '@'
+	{0x7E, 0x11, 0x11, 0x11, 0x7E}, // 'A'
+	{0x7F, 0x49, 0x49, 0x49, 0x36}, // 'B'
+	{0x3E, 0x41, 0x41, 0x41, 0x22}, // 'C'
+	{0x7F, 0x41, 0x41, 0x22, 0x1C}, // 'D'
+	{0x7F, 0x49, 0x49, 0x49, 0x41}, // 'E'
+	{0x7F, 0x09, 0x09, 0x09, 0x01}, // 'F'
+	{0x3E, 0x41, 0x49, 0x49, 0x7A}, // 'G'
+	{0x7F, 0x08, 0x08, 0x08, 0x7F}, // 'H'
+	{0x00, 0x41, 0x7F, 0x41, 0x00}, // 'I'
+	{0x20, 0x40, 0x41, 0x3F, 0x01}, // 'J'
+	{0x7F, 0x08, 0x14, 0x22, 0x41}, // 'K'
+	{0x7F, 0x40, 0x40, 0x40, 0x40}, // 'L'
+	{0x7F, 0x02, 0x0C, 0x02, 0x7F}, // 'M'
+	{0x7F, 0x04, 0x08, 0x10, 0x7F}, // 'N'
+	{0x3E, 0x41, 0x41, 0x41, 0x3E}, // 'O'
+	{0x7F, 0x09, 0x09, 0x09, 0x06}, // 'P'
+	{0x3E, 0x41, 0x51, 0x21, 0x5E}, // 'Q'
+	{0x7F, 0x09, 0x19, 0x29, 0x46}, // 'R'
+	{0x46, 0x49, 0x49, 0x49, 0x31}, // 'S'
+	{0x01, 0x01, 0x7F, 0x01, 0x01}, // 'T'
+	{0x3F, 0x40, 0x40, 0x40, 0x3F}, // 'U'
+	{0x1F, 0x20, 0x40, 0x20, 0x1F}, // 'V'
+	{0x3F, 0x40, 0x38, 0x40, 0x3F}, // 'W'
+	{0x63, 0x14, 0x08, 0x14, 0x63}, // 'X'
+	{0x07, 0x08, 0x70, 0x08, 0x07}, // 'Y'
+	{0x61, 0x51, 0x49, 0x45, 0x43}, // 'Z'
+	{0x00, 0x7F, 0x41, 0x41, 0x00}, // '['
+	{0x02, 0x04, 0x08, 0x10, 0x20}, // '\\'
+	{0x00, 0x41, 0x41, 0x7F, 0x00}, // ']'
+	{0x04, 0x02, 0x01, 0x02, 0x04}, // '^'
+	{0x40, 0x40, 0x40, 0x40, 0x40}, // '_'
+	{0x00, 0x01, 0x02, 0x04, 0x00}, // '`'
+	{0x20, 0x54, 0x54, 0x54, 0x78}, // 'a'
+	{0x7F, 0x48, 0x44, 0x44, 0x38}, // 'b'
+	{0x38, 0x44, 0x44, 0x44, 0x20}, // 'c'
+	{0x38, 0x44, 0x44, 0x48, 0x7F}, // 'd'
+	{0x38, 0x54, 0x54, 0x54, 0x18}, // 'e'
+	{0x08, 0x7E, 0x09, 0x01, 0x02}, // 'f'
+	{0x0C, 0x52, 0x52, 0x52, 0x3E}, // 'g'
+	{0x7F, 0x08, 0x04, 0x04, 0x78}, // 'h'
+	{0x00, 0x44, 0x7D, 0x40, 0x00}, // 'i'
+	{0x20, 0x40, 0x44, 0x3D, 0x00}, // 'j'
+	{0x7F, 0x10, 0x28, 0x44, 0x00}, // 'k'
+	{0x00, 0x41, 0x7F, 0x40, 0x00}, // 'l'
+	{0x7C, 0x04, 0x18, 0x04, 0x78}, // 'm'
+	{0x7C, 0x08, 0x04, 0x04, 0x78}, // 'n'
+	{0x38, 0x44, 0x44, 0x44, 0x38}, // 'o'
+	{0x7C, 0x14, 0x14, 0x14, 0x08}, // 'p'
+	{0x08, 0x14, 0x14, 0x18, 0x7C}, // 'q'
+	{0x7C, 0x08, 0x04, 0x04, 0x08}, // 'r'
+	{0x48, 0x54, 0x54, 0x54, 0x20}, // 's'
+	{0x04, 0x3F, 0x44, 0x40, 0x20}, // 't'
+	{0x3C, 0x40, 0x40, 0x20, 0x7C}, // 'u'
+	{0x1C, 0x20, 0x40, 0x20, 0x1C}, // 'v'
+	{0x3C, 0x40, 0x30, 0x40, 0x3C}, // 'w'
+	{0x44, 0x28, 0x10, 0x28, 0x44}, // 'x'
+	{0x0C, 0x50, 0x50, 0x50, 0x3C}, // 'y'
+	{0x44, 0x64, 0x54, 0x4C, 0x44}, // 'z'
+	{0x00, 0x08, 0x36, 0x41, 0x00}, // '{'
+	{0x00, 0x00, 0x7F, 0x00, 0x00}, // '|'
+	{0x00, 0x41, 0x36, 0x08, 0x00}, // '}'
+	{0x08, 0x04, 0x08, 0x10, 0x08}, // '~'
+}
+
+// glyph 返回字符对应的点阵；摘要中的分栏符 "│" 映射为 '|'，其他非 ASCII 字符显示为 '?'。
+func glyph(r rune) [glyphWidth]byte {
+	if r == '│' {
+		r = '|'
+	}
+	if r < 0x20 || r > 0x7E {
+		r = '?'
+	}
+	return glyphs5x7[r-0x20]
+}
+
+// pngLayout 是 PNG 中各元素的像素尺寸，由单元格大小推导。
+type pngLayout struct {
+	cell   int // 单元格边长
+	gap    int // 单元格间距
+	scale  int // 点阵字体放大倍数
+	left   int // 星期标签区域宽度
+	top    int // 月份标签区域高度
+	margin int // 外边距
+}
+
+// newPNGLayout 按单元格边长推导间距、字体倍数与边距。
+func newPNGLayout(cellSize int) pngLayout {
+	if cellSize <= 0 {
+		cellSize = DefaultSVGCellSize
+	}
+	l := pngLayout{
+		cell:   cellSize,
+		gap:    max(2, cellSize/5),
+		scale:  max(1, (cellSize+5)/8),
+		margin: max(4, cellSize/2),
+	}
+	l.left = l.textWidth("Wed") + l.gap*2
+	l.top = l.textHeight() + l.gap*2
+	return l
+}
+
+// pitch 返回相邻单元格左上角的距离。
+func (l pngLayout) pitch() int {
+	return l.cell + l.gap
+}
+
+// textWidth 返回文本渲染后的像素宽度。
+func (l pngLayout) textWidth(s string) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*glyphAdvance - 1) * l.scale
+}
+
+// textHeight 返回单行文本的像素高度。
+func (l pngLayout) textHeight() int {
+	return glyphHeight * l.scale
+}
+
+// RenderHeatmapPNG 将热力图渲染为 PNG 并写入 w，仅依赖标准库 image 包。
+// 版面、分档与 SVG 导出一致；文字使用内置 5x7 点阵字体绘制。
+func RenderHeatmapPNG(w io.Writer, stats map[time.Time]int, opts PNGOptions) error {
+	start, end := resolveHeatmapRange(opts.Since, opts.Until, opts.WeekStart)
+	g, ok := newHeatmapGrid(start, end, opts.WeekStart)
+	if !ok {
+		return fmt.Errorf("invalid heatmap range: %s > %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	colors := opts.Colors.withDefaults()
+	palette, err := parsePNGPalette(colors)
+	if err != nil {
+		return err
+	}
+	levelColors := [4]color.RGBA{palette.empty, palette.low, palette.medium, palette.high}
+	scale := opts.Thresholds.scale(stats, g)
+
+	l := newPNGLayout(opts.CellSize)
+	gridW := len(g.weekStarts)*l.pitch() - l.gap
+	gridH := 7*l.pitch() - l.gap
+	width := l.margin*2 + l.left + gridW
+	height := l.margin + l.top + gridH
+
+	var summaryLines []string
+	if opts.ShowSummary {
+		summaryLines = svgSummaryLines(RenderSummary(CalculateSummary(stats)) + opts.SummaryFooter)
+	}
+	for _, line := range summaryLines {
+		width = max(width, l.margin*2+l.textWidth(line))
+	}
+	lineHeight := l.textHeight() + l.gap*2
+	legendY := height + l.gap*2
+	if opts.ShowLegend {
+		width = max(width, l.margin+l.left+l.legendWidth(scale.labels())+l.margin)
+		height += l.gap*2 + l.cell + l.gap + lineHeight
+	}
+	summaryY := height + l.gap
+	height += len(summaryLines) * lineHeight
+	height += l.margin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: palette.background}, image.Point{}, draw.Src)
+
+	originX := l.margin + l.left
+	originY := l.margin + l.top
+
+	// 月份标题与星期标签（与终端一致，只标注周一、周三、周五）
+	for _, m := range g.spacedMonthLabels((l.textWidth("Mmm") + l.gap + l.pitch() - 1) / l.pitch()) {
+		l.drawText(img, originX+m.col*l.pitch(), l.margin, m.name, palette.text)
+	}
+	for row := 0; row < 7; row++ {
+		label := strings.TrimSpace(weekdayLabel(g.weekday(row)))
+		if label == "" {
+			continue
+		}
+		l.drawText(img, l.margin, originY+row*l.pitch()+(l.cell-l.textHeight())/2, label, palette.text)
+	}
+
+	// 单元格；今天的单元格绘制描边
+	border := max(1, l.cell/8)
+	for col := range g.weekStarts {
+		for row := 0; row < 7; row++ {
+			day, ok := g.day(col, row)
+			if !ok {
+				continue
+			}
+			x, y := originX+col*l.pitch(), originY+row*l.pitch()
+			fillRect(img, x, y, l.cell, l.cell, levelColors[scale.level(stats[day])])
+			if day.Equal(g.today) {
+				strokeRect(img, x, y, l.cell, l.cell, border, palette.today)
+			}
+		}
+	}
+
+	if opts.ShowLegend {
+		l.drawLegend(img, palette.text, levelColors, scale.labels(), originX, legendY)
+	}
+
+	for i, line := range summaryLines {
+		l.drawText(img, l.margin, summaryY+i*lineHeight, line, palette.text)
+	}
+
+	return png.Encode(w, img)
+}
+
+// legendWidth 返回图例（Less、四个色块、More）的宽度。
+func (l pngLayout) legendWidth(labels [4]string) int {
+	return l.textWidth("Less") + l.gap*2 + 4*l.legendStep(labels) + l.textWidth("More")
+}
+
+// legendStep 返回图例相邻色块的间距，保证居中的档位说明互不重叠。
+func (l pngLayout) legendStep(labels [4]string) int {
+	widest := l.textWidth("10+")
+	for _, label := range labels {
+		widest = max(widest, l.textWidth(label)+l.gap)
+	}
+	return l.cell + widest
+}
+
+// drawLegend 绘制 "Less ■ ■ ■ ■ More" 图例及其下方的档位说明。
+func (l pngLayout) drawLegend(img *image.RGBA, text color.RGBA, levelColors [4]color.RGBA, labels [4]string, x, y int) {
+	textY := y + (l.cell-l.textHeight())/2
+	l.drawText(img, x, textY, "Less", text)
+	step := l.legendStep(labels)
+	cx := x + l.textWidth("Less") + l.gap*2
+	for i, c := range levelColors {
+		cellX := cx + i*step
+		fillRect(img, cellX, y, l.cell, l.cell, c)
+		label := labels[i]
+		l.drawText(img, cellX+(l.cell-l.textWidth(label))/2, y+l.cell+l.gap, label, text)
+	}
+	l.drawText(img, cx+len(levelColors)*step, textY, "More", text)
+}
+
+// drawText 以点阵字体在 (x, y)（左上角）绘制单行文本。
+func (l pngLayout) drawText(img *image.RGBA, x, y int, s string, c color.RGBA) {
+	for _, r := range s {
+		cols := glyph(r)
+		for gx, bits := range cols {
+			for gy := 0; gy < glyphHeight; gy++ {
+				if bits&(1<<gy) != 0 {
+					fillRect(img, x+gx*l.scale, y+gy*l.scale, l.scale, l.scale, c)
+				}
+			}
+		}
+		x += glyphAdvance * l.scale
+	}
+}
+
+// fillRect 填充矩形区域。
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// strokeRect 沿矩形内侧绘制宽度为 t 的边框。
+func strokeRect(img *image.RGBA, x, y, w, h, t int, c color.RGBA) {
+	fillRect(img, x, y, w, t, c)
+	fillRect(img, x, y+h-t, w, t, c)
+	fillRect(img, x, y, t, h, c)
+	fillRect(img, x+w-t, y, t, h, c)
+}
+
+// pngPalette 是解析后的 PNG 配色。
+type pngPalette struct {
+	background, text, empty, low, medium, high, today color.RGBA
+}
+
+// parsePNGPalette 将 #rgb/#rrggbb 颜色解析为 RGBA。
+func parsePNGPalette(c SVGColors) (pngPalette, error) {
+	var p pngPalette
+	for _, f := range []struct {
+		name string
+		src  string
+		dst  *color.RGBA
+	}{
+		{"background", c.Background, &p.background},
+		{"text", c.Text, &p.text},
+		{"empty", c.Empty, &p.empty},
+		{"low", c.Low, &p.low},
+		{"medium", c.Medium, &p.medium},
+		{"high", c.High, &p.high},
+		{"today", c.Today, &p.today},
+	} {
+		rgba, err := parseHexColor(f.src)
+		if err != nil {
+			return pngPalette{}, fmt.Errorf("color %s: %w", f.name, err)
+		}
+		*f.dst = rgba
+	}
+	return p, nil
+}
+
+// parseHexColor 解析 #rgb 或 #rrggbb 形式的颜色。
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 || !strings.HasPrefix(strings.TrimSpace(s), "#") {
+		return color.RGBA{}, fmt.Errorf("expected #rgb or #rrggbb, got %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("expected #rgb or #rrggbb, got %q", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHeatmapPNG_CellColorsMatchThresholds(t *testing.T) {
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local) // 周日，第 0 列
+	until := time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local)
+	data := map[time.Time]int{
+		time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local): 3,
+		time.Date(2025, 6, 3, 0, 0, 0, 0, time.Local): 7,
+		time.Date(2025, 6, 4, 0, 0, 0, 0, time.Local): 12,
+	}
+	opts := PNGOptions{Since: since, Until: until, Colors: SVGColors{Low: "#123"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, RenderHeatmapPNG(&buf, data, opts))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+
+	l := newPNGLayout(0)
+	g, ok := newHeatmapGrid(since, until, time.Sunday)
+	require.True(t, ok)
+	assert.Equal(t, l.margin*2+l.left+len(g.weekStarts)*l.pitch()-l.gap, img.Bounds().Dx())
+
+	pixel := func(col, row int) color.RGBA {
+		x := l.margin + l.left + col*l.pitch() + l.cell/2
+		y := l.margin + l.top + row*l.pitch() + l.cell/2
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+	hex := func(s string) color.RGBA {
+		c, err := parseHexColor(s)
+		require.NoError(t, err)
+		return c
+	}
+	d := DefaultSVGColors()
+	assert.Equal(t, hex(d.Empty), pixel(0, 0), "2025-06-01 has no commits")
+	assert.Equal(t, hex("#112233"), pixel(0, 1), "custom low color")
+	assert.Equal(t, hex(d.Medium), pixel(0, 2))
+	assert.Equal(t, hex(d.High), pixel(0, 3))
+	assert.Equal(t, hex(d.Background), pixel(4, 2), "days after until are not drawn")
+}
+
+func TestRenderHeatmapPNG_LegendAndSummaryGrowImage(t *testing.T) {
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(2025, 6, 7, 0, 0, 0, 0, time.Local)
+	data := map[time.Time]int{time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local): 1}
+
+	decode := func(opts PNGOptions) (int, int) {
+		var buf bytes.Buffer
+		require.NoError(t, RenderHeatmapPNG(&buf, data, opts))
+		img, err := png.Decode(&buf)
+		require.NoError(t, err)
+		return img.Bounds().Dx(), img.Bounds().Dy()
+	}
+
+	plainW, plainH := decode(PNGOptions{Since: since, Until: until})
+	fullW, fullH := decode(PNGOptions{Since: since, Until: until, ShowLegend: true, ShowSummary: true})
+	assert.Greater(t, fullW, plainW, "summary text widens a one-week image")
+	assert.Greater(t, fullH, plainH)
+}
+
+func TestRenderHeatmapPNG_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderHeatmapPNG(&buf, nil, PNGOptions{
+		Since: time.Date(2025, 6, 10, 0, 0, 0, 0, time.Local),
+		Until: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+	})
+	assert.Error(t, err)
+
+	err = RenderHeatmapPNG(&buf, nil, PNGOptions{Colors: SVGColors{High: "green"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "color high")
+}
+
+func TestGlyph_FallbacksAndLabels(t *testing.T) {
+	assert.Equal(t, glyph('|'), glyph('│'))
+	assert.Equal(t, glyph('?'), glyph('🔥'))
+	for _, r := range "JanFebMarAprMayJunJulAugSepOctNovDecMonWedFriLessMore0123456789+-" {
+		assert.NotEqual(t, glyph(' '), glyph(r), "glyph %q should not be blank", r)
+	}
+}
@@ -0,0 +1,276 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var collectRepoPunchcardFn = collectRepoPunchcard
+
+// 工作时间窗口（本地时间 [09:00, 18:00)，周一至周五），用于统计非工作时间提交占比。
+const (
+	workdayStartHour = 9
+	workdayEndHour   = 18
+)
+
+// Punchcard 是按星期 × 小时聚合的提交数，下标为 [time.Weekday][hour]。
+type Punchcard [7][24]int
+
+// Add 将另一个 Punchcard 累加到当前值。
+func (p *Punchcard) Add(o Punchcard) {
+	for wd := range p {
+		for h := range p[wd] {
+			p[wd][h] += o[wd][h]
+		}
+	}
+}
+
+// Total 返回全部格子的提交总数。
+func (p Punchcard) Total() int {
+	total := 0
+	for wd := range p {
+		for h := range p[wd] {
+			total += p[wd][h]
+		}
+	}
+	return total
+}
+
+// Max 返回单个格子的最大提交数。
+func (p Punchcard) Max() int {
+	maxCount := 0
+	for wd := range p {
+		for h := range p[wd] {
+			maxCount = max(maxCount, p[wd][h])
+		}
+	}
+	return maxCount
+}
+
+// PunchcardSummary 汇总 punchcard 的关键指标。
+type PunchcardSummary struct {
+	TotalCommits      int
+	PeakWeekday       time.Weekday
+	PeakHour          int
+	PeakCommits       int
+	AfterHours        int     // 工作日 09:00-18:00 之外及周末的提交数
+	AfterHoursPercent float64 // AfterHours 占 TotalCommits 的百分比
+}
+
+// CalculatePunchcardSummary 计算 punchcard 的峰值时段与非工作时间占比。
+func CalculatePunchcardSummary(p Punchcard) PunchcardSummary {
+	s := PunchcardSummary{TotalCommits: p.Total()}
+	for wd := range p {
+		for h, count := range p[wd] {
+			if count > s.PeakCommits {
+				s.PeakCommits = count
+				s.PeakWeekday = time.Weekday(wd)
+				s.PeakHour = h
+			}
+			if isAfterHours(time.Weekday(wd), h) {
+				s.AfterHours += count
+			}
+		}
+	}
+	if s.TotalCommits > 0 {
+		s.AfterHoursPercent = float64(s.AfterHours) / float64(s.TotalCommits) * 100
+	}
+	return s
+}
+
+// isAfterHours 判断某个星期几的某个小时是否处于工作时间之外。
+func isAfterHours(wd time.Weekday, hour int) bool {
+	if wd == time.Saturday || wd == time.Sunday {
+		return true
+	}
+	return hour < workdayStartHour || hour >= workdayEndHour
+}
+
+// CollectPunchcard 并发收集多个仓库的提交，按作者时间的星期几与小时聚合。
+// 时区、邮箱过滤、分支与时间范围的口径与 CollectStats 相同。
+func CollectPunchcard(opts CollectOptions) (Punchcard, error) {
+	var out Punchcard
+	done, err := collectCommonGeneric[Punchcard](opts, collectRepoPunchcardFn, func(_ string, p Punchcard) {
+		out.Add(p)
+	})
+	if err != nil && len(done) == 0 {
+		return Punchcard{}, err
+	}
+	return out, err
+}
+
+// collectRepoPunchcard 收集单个仓库的 punchcard。
+// 启用缓存时基于提交索引计算，否则直接遍历提交对象。
+func collectRepoPunchcard(repoPath string, q repoQuery) (Punchcard, error) {
+	var out Punchcard
+
+	repo, err := openRepository(repoPath)
+	if err != nil {
+		return out, err
+	}
+
+	add := func(when time.Time) {
+		t := when.In(q.dayLocation(when))
+		out[t.Weekday()][t.Hour()]++
+	}
+
+	if q.useCache {
+		ri, startPoints, err := openRepoIndex(repo, repoPath, q)
+		if err != nil {
+			return out, err
+		}
+		defer ri.save()
+
+		err = ri.walk(startPoints, q, func(_ []string, _ int, pos int) error {
+			add(indexedAuthorTime(&ri.data.Commits[pos]))
+			return nil
+		})
+		return out, err
+	}
+
+	err = walkRepoCommits(repo, repoPath, q, func(_ []string, _ int, c *object.Commit) error {
+		add(c.Author.When)
+		return nil
+	})
+	return out, err
+}
+
+// PunchcardOptions 控制 punchcard 的渲染内容与配色。
+type PunchcardOptions struct {
+	ShowLegend  bool
+	ShowSummary bool
+	Theme       Theme        // zero value = github
+	NoColor     bool         // true 时不输出 ANSI 颜色，档位改用字符区分
+	Charset     Charset      // zero value = unicode
+	WeekStart   time.Weekday // 第一行的星期，zero value = Sunday
+}
+
+// RenderPunchcard 使用默认主题渲染星期 × 小时的 punchcard 表格。
+func RenderPunchcard(p Punchcard, includeLegend bool, includeSummary bool) string {
+	return RenderPunchcardWithOptions(p, PunchcardOptions{ShowLegend: includeLegend, ShowSummary: includeSummary})
+}
+
+// RenderPunchcardWithOptions 渲染星期 × 小时的 punchcard 表格，颜色与热力图单元格一致。
+// 由于单格提交数随统计范围变化很大，强度按最大值的三等分划分。
+func RenderPunchcardWithOptions(p Punchcard, opts PunchcardOptions) string {
+	var b strings.Builder
+	style := newCellStyle(opts.Theme, opts.NoColor, opts.Charset)
+
+	// 小时标题行：每 3 小时标注一次
+	b.WriteString("    ")
+	for h := 0; h < 24; h++ {
+		if h%3 == 0 {
+			b.WriteString(fmt.Sprintf("%02d ", h))
+			continue
+		}
+		b.WriteString("   ")
+	}
+	b.WriteByte('\n')
+
+	low, medium := punchcardThresholds(p.Max())
+	for row := 0; row < 7; row++ {
+		wd := (opts.WeekStart + time.Weekday(row)) % 7
+		b.WriteString(WeekdayAbbrev(wd))
+		b.WriteByte(' ')
+		for h := 0; h < 24; h++ {
+			b.WriteString(renderPunchcardCell(style, p[wd][h], low, medium))
+		}
+		b.WriteByte('\n')
+	}
+
+	if opts.ShowLegend {
+		b.WriteByte('\n')
+		b.WriteString(renderPunchcardLegend(style, p.Max(), low, medium))
+	}
+
+	if opts.ShowSummary {
+		b.WriteByte('\n')
+		summary := RenderPunchcardSummary(CalculatePunchcardSummary(p))
+		if opts.Charset == CharsetASCII {
+			summary = ToASCII(summary)
+		}
+		b.WriteString(summary)
+	}
+
+	return b.String()
+}
+
+// punchcardThresholds 根据最大值计算浅/中两档的上限（含），高于 medium 为深色。
+func punchcardThresholds(maxCount int) (low, medium int) {
+	low = (maxCount + 2) / 3
+	medium = (2*maxCount + 2) / 3
+	return low, medium
+}
+
+// renderPunchcardCell 渲染 punchcard 的单个格子。
+func renderPunchcardCell(style cellStyle, count, low, medium int) string {
+	level := 3
+	switch {
+	case count == 0:
+		level = 0
+	case count <= low:
+		level = 1
+	case count <= medium:
+		level = 2
+	}
+	return style.paint(level, false) + " "
+}
+
+// renderPunchcardLegend 渲染 punchcard 图例，每个色块后紧跟该档对应的提交数区间。
+// 区间宽度不定（如 "10-20"），另起一行对齐会与 2 列宽的色块错位，因此与色块写在同一行。
+func renderPunchcardLegend(style cellStyle, maxCount, low, medium int) string {
+	ranges := []string{
+		"0",
+		countRange(1, low),
+		countRange(low+1, medium),
+		countRange(medium+1, maxCount),
+	}
+
+	var b strings.Builder
+	b.WriteString("Less ")
+	for level, r := range ranges {
+		if level > 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(style.paint(level, false) + " " + r)
+	}
+	b.WriteString(" More\n")
+	return b.String()
+}
+
+// countRange 格式化提交数区间，区间为空时返回 "-"。
+func countRange(from, to int) string {
+	switch {
+	case from > to:
+		return "-"
+	case from == to:
+		return fmt.Sprintf("%d", from)
+	default:
+		return fmt.Sprintf("%d-%d", from, to)
+	}
+}
+
+// RenderPunchcardSummary 渲染 punchcard 摘要信息。
+func RenderPunchcardSummary(s PunchcardSummary) string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("─", summaryRuleLen))
+	b.WriteByte('\n')
+
+	peak := "-"
+	if s.PeakCommits > 0 {
+		peak = fmt.Sprintf("%s %02d:00 (%d %s)", WeekdayAbbrev(s.PeakWeekday), s.PeakHour, s.PeakCommits, Pluralize(s.PeakCommits, "commit", "commits"))
+	}
+	b.WriteString(fmt.Sprintf("Total: %d commits │ Peak: %s\n", s.TotalCommits, peak))
+	b.WriteString(fmt.Sprintf(
+		"After hours: %d %s (%.1f%%, weekends and outside %02d:00-%02d:00)\n",
+		s.AfterHours,
+		Pluralize(s.AfterHours, "commit", "commits"),
+		s.AfterHoursPercent,
+		workdayStartHour,
+		workdayEndHour,
+	))
+	return b.String()
+}
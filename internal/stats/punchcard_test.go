@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectPunchcard_BucketsByWeekdayAndHour(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	r := initRepo(t, repoPath)
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+
+	// 2025-06-02 是周一
+	monday10 := time.Date(2025, 6, 2, 10, 15, 0, 0, time.Local)
+	commitFile(t, wt, repoPath, "a.txt", "1", "me@example.com", monday10)
+	commitFile(t, wt, repoPath, "a.txt", "2", "me@example.com", monday10.Add(30*time.Minute))
+	commitFile(t, wt, repoPath, "a.txt", "3", "me@example.com", time.Date(2025, 6, 7, 23, 5, 0, 0, time.Local))
+	commitFile(t, wt, repoPath, "a.txt", "4", "other@example.com", monday10)
+
+	opts := CollectOptions{
+		Repos:  []string{repoPath},
+		Emails: []string{"me@example.com"},
+		Since:  time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		Until:  time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local),
+	}
+
+	for _, useCache := range []bool{false, true} {
+		opts.UseCache = useCache
+		got, err := CollectPunchcard(opts)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, got.Total(), "useCache=%t", useCache)
+		assert.Equal(t, 2, got[time.Monday][10], "useCache=%t", useCache)
+		assert.Equal(t, 1, got[time.Saturday][23], "useCache=%t", useCache)
+	}
+}
+
+func TestCalculatePunchcardSummary(t *testing.T) {
+	var p Punchcard
+	p[time.Tuesday][14] = 5
+	p[time.Tuesday][20] = 2
+	p[time.Sunday][11] = 3
+
+	s := CalculatePunchcardSummary(p)
+	assert.Equal(t, 10, s.TotalCommits)
+	assert.Equal(t, time.Tuesday, s.PeakWeekday)
+	assert.Equal(t, 14, s.PeakHour)
+	assert.Equal(t, 5, s.PeakCommits)
+	assert.Equal(t, 5, s.AfterHours)
+	assert.InDelta(t, 50.0, s.AfterHoursPercent, 0.001)
+}
+
+func TestRenderPunchcard_UsesHeatmapPalette(t *testing.T) {
+	var p Punchcard
+	p[time.Monday][9] = 1
+	p[time.Monday][10] = 3
+
+	out := RenderPunchcard(p, true, true)
+	assert.Contains(t, out, "Mon ")
+	assert.Contains(t, out, colorLow+"██"+colorReset)
+	assert.Contains(t, out, colorHigh+"██"+colorReset)
+	assert.Contains(t, out, "Peak: Mon 10:00 (3 commits)")
+}
+
+func TestRenderPunchcardWithOptions_ASCIIWithoutColor(t *testing.T) {
+	var p Punchcard
+	p[time.Monday][9] = 1
+	p[time.Monday][10] = 3
+
+	out := RenderPunchcardWithOptions(p, PunchcardOptions{ShowLegend: true, ShowSummary: true, NoColor: true, Charset: CharsetASCII})
+	assert.NotContains(t, out, "\x1b[")
+	assert.Contains(t, out, "oo @@")
+	assert.Contains(t, out, "Less .. 0  oo 1  OO 2  @@ 3 More\n")
+	assert.Contains(t, out, "Total: 4 commits | Peak: Mon 10:00 (3 commits)")
+}
+
+func TestRenderPunchcardWithOptions_WeekStart(t *testing.T) {
+	var p Punchcard
+	out := RenderPunchcardWithOptions(p, PunchcardOptions{NoColor: true, WeekStart: time.Monday})
+	lines := strings.Split(out, "\n")
+	assert.True(t, strings.HasPrefix(lines[1], "Mon "))
+	assert.True(t, strings.HasPrefix(lines[7], "Sun "))
+}
+
+func TestRenderPunchcardLegend_RangesFollowSwatches(t *testing.T) {
+	style := newCellStyle(DefaultTheme(), true, CharsetASCII)
+	low, medium := punchcardThresholds(30)
+
+	assert.Equal(t, "Less .. 0  oo 1-10  OO 11-20  @@ 21-30 More\n", renderPunchcardLegend(style, 30, low, medium))
+	assert.Equal(t, "Less .. 0  oo 1  OO -  @@ - More\n", renderPunchcardLegend(style, 1, 1, 1))
+}
@@ -7,15 +7,17 @@ import (
 
 // RepoRank 表示单个仓库在排行榜中的统计结果。
 type RepoRank struct {
-	Repository string  `json:"repository"`
-	Commits    int     `json:"commits"`
-	Percent    float64 `json:"percent"`
+	Repository string      `json:"repository"`
+	Commits    int         `json:"commits"`
+	Percent    float64     `json:"percent"`
+	Lines      *LineTotals `json:"lines,omitempty"` // 仅基于活动统计排行时填充
 }
 
 // RepoRanking 表示仓库排行榜结果。
 type RepoRanking struct {
-	Repositories []RepoRank `json:"repositories"`
-	TotalCommits int        `json:"totalCommits"`
+	Repositories []RepoRank  `json:"repositories"`
+	TotalCommits int         `json:"totalCommits"`
+	TotalLines   *LineTotals `json:"totalLines,omitempty"` // 仅基于活动统计排行时填充
 }
 
 // percentRemainder 用于百分比舍入分配算法（Largest Remainder Method）。
@@ -49,6 +51,34 @@ func RankRepositories(statsPerRepo map[string]map[time.Time]int, limit int) Repo
 		})
 	}
 
+	return rankRows(rows, limit)
+}
+
+// RankRepositoriesActivity 与 RankRepositories 相同，但额外为每个仓库汇总代码行统计。
+// 排序仍以提交数为准，TotalLines 为输出行（受 limit 影响）的合计。
+func RankRepositoriesActivity(activityPerRepo map[string]map[time.Time]DayActivity, limit int) RepoRanking {
+	rows := make([]RepoRank, 0, len(activityPerRepo))
+	for repoPath, daily := range activityPerRepo {
+		total := SumActivity(daily)
+		lines := total.Lines
+		rows = append(rows, RepoRank{
+			Repository: repoPath,
+			Commits:    total.Commits,
+			Lines:      &lines,
+		})
+	}
+
+	ranking := rankRows(rows, limit)
+	var totalLines LineTotals
+	for _, r := range ranking.Repositories {
+		totalLines = totalLines.Add(*r.Lines)
+	}
+	ranking.TotalLines = &totalLines
+	return ranking
+}
+
+// rankRows 对已汇总的仓库行排序、截断并计算百分比。
+func rankRows(rows []RepoRank, limit int) RepoRanking {
 	sort.Slice(rows, func(i, j int) bool {
 		if rows[i].Commits != rows[j].Commits {
 			return rows[i].Commits > rows[j].Commits
@@ -8,7 +8,8 @@ import (
 
 // ANSI 颜色代码常量，用于终端热力图渲染。
 const (
-	colorReset = "\033[0m" // 重置颜色
+	colorReset   = "\033[0m" // 重置颜色
+	colorReverse = "\033[7m" // 反色，用于光标
 
 	colorEmpty  = "\033[38;5;240m" // 灰色 - 无提交
 	colorLow    = "\033[38;5;120m" // 浅绿 - 1-4 次提交
@@ -23,15 +24,27 @@ const defaultHeatmapMonths = 6
 type HeatmapOptions struct {
 	ShowLegend  bool
 	ShowSummary bool
-	Since       time.Time // zero value = auto-calculated from months
-	Until       time.Time // zero value = now
+	Since       time.Time    // zero value = auto-calculated from months
+	Until       time.Time    // zero value = now
+	Theme       Theme        // zero value = github
+	Thresholds  Thresholds   // zero value = fixed 1 / 5 / 10
+	NoColor     bool         // true 时不输出 ANSI 颜色，档位改用字符区分
+	Charset     Charset      // zero value = unicode
+	Layout      Layout       // zero value = auto（按 Width 选择）
+	Width       int          // 终端宽度（列），<= 0 表示未知
+	WeekStart   time.Weekday // 每列（周）的第一天，zero value = Sunday
+	Cursor      time.Time    // 反色标出的光标日期（tui 使用），zero value = 无光标
 }
 
 // RenderHeatmapWithOptions renders a heatmap with the given options.
 func RenderHeatmapWithOptions(stats map[time.Time]int, opts HeatmapOptions) string {
-	start := opts.Since
-	end := opts.Until
+	start, end := resolveHeatmapRange(opts.Since, opts.Until, opts.WeekStart)
+	return renderHeatmapRange(stats, start, end, opts)
+}
 
+// resolveHeatmapRange 补全热力图的时间范围：未指定结束日期时取今天，
+// 未指定起始日期时取结束日期前 defaultHeatmapMonths 个月（对齐到 weekStart）。
+func resolveHeatmapRange(start, end time.Time, weekStart time.Weekday) (time.Time, time.Time) {
 	loc := timeNow().Location()
 	if !end.IsZero() {
 		loc = end.Location()
@@ -40,120 +53,175 @@ func RenderHeatmapWithOptions(stats map[time.Time]int, opts HeatmapOptions) stri
 	}
 
 	if end.IsZero() {
-		end = beginningOfDay(timeNow(), loc)
+		end = beginningOfDay(currentTime(loc), loc)
 	}
 	if start.IsZero() {
-		start = heatmapStart(end, defaultHeatmapMonths)
+		start = heatmapStart(end, defaultHeatmapMonths, weekStart)
 	}
+	return start, end
+}
 
-	return renderHeatmapRange(stats, start, end, opts.ShowLegend, opts.ShowSummary)
+// heatmapGrid 是热力图的版面：列为按 weekStart 对齐的周，行为星期（0=weekStart）。
+// 终端、SVG 与 PNG 渲染共用同一版面，保证各输出的周对齐与月份标注一致。
+type heatmapGrid struct {
+	start      time.Time    // 范围起点（当天 00:00）
+	end        time.Time    // 范围终点（当天 00:00）
+	today      time.Time    // 今天 00:00，用于高亮
+	cursor     time.Time    // 光标所在日期 00:00，零值表示无光标
+	weekStart  time.Weekday // 每周第一天（第 0 行）
+	weekStarts []time.Time  // 每列的第一天日期
 }
 
-// renderHeatmapRange 是热力图渲染的核心实现。
-func renderHeatmapRange(stats map[time.Time]int, start, end time.Time, includeLegend bool, includeSummary bool) string {
+// newHeatmapGrid 构建 [start, end] 的版面，每列从 weekStart 开始，范围非法时返回 false。
+func newHeatmapGrid(start, end time.Time, weekStart time.Weekday) (heatmapGrid, bool) {
 	if start.IsZero() || end.IsZero() || start.After(end) {
-		return ""
+		return heatmapGrid{}, false
 	}
 
 	loc := end.Location()
-	start = beginningOfDay(start, loc)
-	end = beginningOfDay(end, loc)
-
-	today := beginningOfDay(timeNow(), loc)
+	g := heatmapGrid{
+		start:     beginningOfDay(start, loc),
+		end:       beginningOfDay(end, loc),
+		today:     beginningOfDay(currentTime(loc), loc),
+		weekStart: weekStart,
+	}
 
-	// 热力图从周日开始对齐列。
-	weekStart := start
-	for weekStart.Weekday() != time.Sunday {
-		weekStart = weekStart.AddDate(0, 0, -1)
+	// 热力图按每周第一天对齐列。
+	first := g.start
+	for first.Weekday() != weekStart {
+		first = first.AddDate(0, 0, -1)
 	}
 
 	// 构建每周的起始日期列表（用作列）
-	weekStarts := make([]time.Time, 0, 32)
-	for d := weekStart; !d.After(end); d = d.AddDate(0, 0, 7) {
-		weekStarts = append(weekStarts, d)
+	g.weekStarts = make([]time.Time, 0, 32)
+	for d := first; !d.After(g.end); d = d.AddDate(0, 0, 7) {
+		g.weekStarts = append(g.weekStarts, d)
 	}
+	return g, true
+}
 
-	var b strings.Builder
-	// 写入月份标题行
-	writeMonthHeader(&b, weekStarts)
-
-	// 按行（星期几）渲染热力图
-	for row := 0; row < 7; row++ {
-		b.WriteString(weekdayLabel(row)) // 左侧星期标签
-
-		for _, ws := range weekStarts {
-			day := ws.AddDate(0, 0, row)
-			// 跳过范围外的日期
-			if day.Before(start) || day.After(end) {
-				b.WriteString("    ")
-				continue
-			}
-
-			key := beginningOfDay(day, loc)
-			count := stats[key]
-			isToday := key.Equal(today)
-			b.WriteString(renderCell(count, isToday))
+// weekday 返回第 row 行对应的星期。
+func (g heatmapGrid) weekday(row int) time.Weekday {
+	return (g.weekStart + time.Weekday(row)) % 7
+}
+
+// day 返回第 col 列第 row 行的日期，超出统计范围时返回 false。
+func (g heatmapGrid) day(col, row int) (time.Time, bool) {
+	day := beginningOfDay(g.weekStarts[col].AddDate(0, 0, row), g.end.Location())
+	if day.Before(g.start) || day.After(g.end) {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// paintDay 返回 day 对应单元格的字符：按提交数取档位，今天高亮，光标所在日期反色。
+func (g heatmapGrid) paintDay(style cellStyle, scale levelScale, stats map[time.Time]int, day time.Time) string {
+	level := scale.level(stats[day])
+	if !g.cursor.IsZero() && day.Equal(g.cursor) {
+		return style.paintCursor(level)
+	}
+	return style.paint(level, day.Equal(g.today))
+}
+
+// monthLabel 是月份标题在版面中的位置。
+type monthLabel struct {
+	col  int
+	name string // 月份缩写（如 Jan）
+}
+
+// colMonth 返回第 col 列第一个范围内日期所在的月份。
+// 列按每周第一天对齐，首列的周起点可能早于范围起点（如落在上一年 12 月），不能直接取其月份。
+func (g heatmapGrid) colMonth(col int) time.Month {
+	day := g.weekStarts[col]
+	if day.Before(g.start) {
+		day = g.start
+	}
+	return day.Month()
+}
+
+// monthLabels 返回每月第一次出现的列及其月份缩写。
+func (g heatmapGrid) monthLabels() []monthLabel {
+	labels := make([]monthLabel, 0, 13)
+	lastMonth := time.Month(0)
+	for col := range g.weekStarts {
+		month := g.colMonth(col)
+		if month == lastMonth {
+			continue
 		}
-		b.WriteByte('\n')
+		lastMonth = month
+		labels = append(labels, monthLabel{col: col, name: lastMonth.String()[:3]})
+	}
+	return labels
+}
+
+// spacedMonthLabels 返回至少间隔 minCols 列的月份标题，用于按像素排版、标题宽度超过一列的图片输出。
+// 过近时丢弃较早的标题（通常是范围开头只占一两列的残月）。
+func (g heatmapGrid) spacedMonthLabels(minCols int) []monthLabel {
+	labels := g.monthLabels()
+	out := labels[:0]
+	for _, m := range labels {
+		if n := len(out); n > 0 && m.col-out[n-1].col < minCols {
+			out = out[:n-1]
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// renderHeatmapRange 是热力图渲染的核心实现。
+func renderHeatmapRange(stats map[time.Time]int, start, end time.Time, opts HeatmapOptions) string {
+	g, ok := newHeatmapGrid(start, end, opts.WeekStart)
+	if !ok {
+		return ""
 	}
+	if !opts.Cursor.IsZero() {
+		g.cursor = beginningOfDay(opts.Cursor, g.end.Location())
+	}
+	style := newCellStyle(opts.Theme, opts.NoColor, opts.Charset)
+	scale := opts.Thresholds.scale(stats, g)
+
+	var b strings.Builder
+	writeGrid(&b, stats, g, style, scale, opts)
 
-	if includeLegend {
+	if opts.ShowLegend {
 		b.WriteByte('\n')
-		b.WriteString(RenderLegend())
+		b.WriteString(renderLegend(style, scale))
 	}
 
-	if includeSummary {
+	if opts.ShowSummary {
 		b.WriteByte('\n')
-		b.WriteString(RenderSummary(CalculateSummary(stats)))
+		summary := RenderSummary(CalculateSummary(stats))
+		if opts.Charset == CharsetASCII {
+			summary = ToASCII(summary)
+		}
+		b.WriteString(summary)
 	}
 
 	return b.String()
 }
 
-// writeMonthHeader 写入月份标题行。
+// writeMonthHeader 写入 wide 版面的月份标题行。
 // 在每月第一周的位置显示月份缩写（如 Jan, Feb）。
-func writeMonthHeader(b *strings.Builder, weekStarts []time.Time) {
-	b.WriteString("    ") // 左侧对齐空格（与星期标签对齐）
-
-	lastMonth := time.Month(0)
-	for _, ws := range weekStarts {
-		month := ws.Month()
-		if month != lastMonth {
-			// 新月份开始，显示月份名称
-			name := month.String()
-			if len(name) > 3 {
-				name = name[:3] // 截取前3个字符
-			}
-			b.WriteString(name)
-			if len(name) < 3 {
-				b.WriteString(strings.Repeat(" ", 3-len(name)))
-			}
-			b.WriteByte(' ')
-			lastMonth = month
-			continue
-		}
-		// 同一月份内的周，显示空格
-		b.WriteString("    ")
-	}
-	b.WriteByte('\n')
+func writeMonthHeader(b *strings.Builder, g heatmapGrid) {
+	writeMonthHeaderCols(b, g, wideColWidth)
 }
 
-// weekdayLabel 返回指定行（0-6，对应周日到周六）的星期标签。
+// weekdayLabel 返回指定星期所在行的标签。
 // 只在周一、周三、周五显示标签，其他行显示空格。
-func weekdayLabel(row int) string {
-	switch row {
-	case 1:
+func weekdayLabel(wd time.Weekday) string {
+	switch wd {
+	case time.Monday:
 		return "Mon "
-	case 3:
+	case time.Wednesday:
 		return "Wed "
-	case 5:
+	case time.Friday:
 		return "Fri "
 	default:
 		return "    "
 	}
 }
 
-// renderCell 渲染单个日期的热力图单元格。
+// renderCell 使用默认主题与默认档位渲染单个日期的热力图单元格。
 // 根据提交数量选择颜色：
 //   - 0: 灰色空心方块
 //   - 1-4: 浅绿实心方块
@@ -161,50 +229,40 @@ func weekdayLabel(row int) string {
 //   - 10+: 深绿实心方块
 //   - 今天: 粉色高亮
 func renderCell(count int, today bool) string {
-	color := colorEmpty
-	switch {
-	case count > 0 && count < 5:
-		color = colorLow
-	case count >= 5 && count < 10:
-		color = colorMedium
-	case count >= 10:
-		color = colorHigh
-	}
-	// 今天使用特殊颜色高亮
-	if today {
-		color = colorToday
-	}
-
-	// 无提交显示空心方块，有提交显示实心方块
-	if count == 0 {
-		return color + "░░" + colorReset + "  "
-	}
-	return color + "██" + colorReset + "  "
+	return defaultCellStyle().paint(defaultScale.level(count), today) + "  "
 }
 
-// RenderLegend 渲染热力图图例。
+// RenderLegend 使用默认主题与默认档位渲染热力图图例。
 // 输出包含 ANSI 颜色代码的字符串，可直接输出到终端。
 func RenderLegend() string {
+	return renderLegend(defaultCellStyle(), defaultScale)
+}
+
+// renderLegend 渲染图例：第一行为各档位色块，第二行为对应的提交数区间。
+func renderLegend(style cellStyle, scale levelScale) string {
 	var b strings.Builder
 
 	b.WriteString("Less ")
-	b.WriteString(colorEmpty)
-	b.WriteString("░░")
-	b.WriteString(colorReset)
-	b.WriteByte(' ')
-	b.WriteString(colorLow)
-	b.WriteString("██")
-	b.WriteString(colorReset)
-	b.WriteByte(' ')
-	b.WriteString(colorMedium)
-	b.WriteString("██")
-	b.WriteString(colorReset)
-	b.WriteByte(' ')
-	b.WriteString(colorHigh)
-	b.WriteString("██")
-	b.WriteString(colorReset)
+	for level := range style.glyphs {
+		if level > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(style.paint(level, false))
+	}
 	b.WriteString(" More\n")
 
-	b.WriteString("     0  1-4 5-9 10+\n")
+	// 标签与色块左对齐，宽度不足色块（2 列）时补齐
+	labels := scale.labels()
+	b.WriteString("     ")
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if i < len(labels)-1 && len(label) < 2 {
+			label += strings.Repeat(" ", 2-len(label))
+		}
+		b.WriteString(label)
+	}
+	b.WriteByte('\n')
 	return b.String()
 }
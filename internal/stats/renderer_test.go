@@ -175,6 +175,28 @@ func TestRenderHeatmapWithOptions_TodayHighlight(t *testing.T) {
 	assert.Contains(t, result, colorToday, "today should use special highlight color")
 }
 
+func TestRenderHeatmapWithOptions_Cursor(t *testing.T) {
+	loc := time.Local
+	start := time.Date(2024, 6, 2, 0, 0, 0, 0, loc) // 周日
+	end := time.Date(2024, 6, 15, 0, 0, 0, 0, loc)
+	cursor := time.Date(2024, 6, 11, 15, 0, 0, 0, loc) // 第二列周二，时刻会被忽略
+	data := map[time.Time]int{time.Date(2024, 6, 11, 0, 0, 0, 0, loc): 12}
+
+	plain := RenderHeatmapWithOptions(data, HeatmapOptions{Since: start, Until: end, NoColor: true, Charset: CharsetASCII, Cursor: cursor})
+	lines := strings.Split(plain, "\n")
+	assert.Equal(t, "    ..  []  ", lines[3], "cursor replaces the glyph without color")
+	assert.Equal(t, 1, strings.Count(plain, "[]"))
+
+	narrow := RenderHeatmapWithOptions(data, HeatmapOptions{Since: start, Until: end, NoColor: true, Charset: CharsetASCII, Cursor: cursor, Layout: LayoutCompact})
+	assert.Equal(t, 1, strings.Count(narrow, "X"))
+
+	colored := RenderHeatmapWithOptions(data, HeatmapOptions{Since: start, Until: end, Cursor: cursor})
+	assert.Contains(t, colored, colorReverse+colorHigh+"██"+colorReset, "cursor is shown in reverse video")
+
+	none := RenderHeatmapWithOptions(data, HeatmapOptions{Since: start, Until: end, NoColor: true, Charset: CharsetASCII})
+	assert.NotContains(t, none, "[]")
+}
+
 func TestRenderHeatmapWithOptions_InvalidRange(t *testing.T) {
 	loc := time.Local
 	start := time.Date(2024, 7, 1, 0, 0, 0, 0, loc)
@@ -203,17 +225,114 @@ func TestWeekdayLabel(t *testing.T) {
 func TestWriteMonthHeader_ShowsMonthNames(t *testing.T) {
 	loc := time.Local
 	// 4 weeks spanning Jun-Jul
-	weekStarts := []time.Time{
-		time.Date(2024, 6, 16, 0, 0, 0, 0, loc),
-		time.Date(2024, 6, 23, 0, 0, 0, 0, loc),
-		time.Date(2024, 6, 30, 0, 0, 0, 0, loc),
-		time.Date(2024, 7, 7, 0, 0, 0, 0, loc),
-	}
+	g, ok := newHeatmapGrid(time.Date(2024, 6, 16, 0, 0, 0, 0, loc), time.Date(2024, 7, 13, 0, 0, 0, 0, loc), time.Sunday)
+	require.True(t, ok)
+	require.Len(t, g.weekStarts, 4)
 
 	var b strings.Builder
-	writeMonthHeader(&b, weekStarts)
+	writeMonthHeader(&b, g)
 	header := b.String()
 
 	assert.Contains(t, header, "Jun")
 	assert.Contains(t, header, "Jul")
 }
+
+func TestHeatmapGrid_MonthLabelsUseFirstInRangeDay(t *testing.T) {
+	// 2025-01-01 是周三，第 0 列从 2024-12-29 开始，但其中范围内的日期都在 1 月
+	g, ok := newHeatmapGrid(time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local), time.Sunday)
+	require.True(t, ok)
+
+	all := g.monthLabels()
+	require.Equal(t, monthLabel{col: 0, name: "Jan"}, all[0])
+	assert.Equal(t, "Feb", all[1].name)
+}
+
+func TestHeatmapGrid_SpacedMonthLabelsDropsCrowdedLeadingMonth(t *testing.T) {
+	// 2025-01-29 是周三，第 0 列只有 1 月的最后三天，第 1 列开始是 2 月
+	g, ok := newHeatmapGrid(time.Date(2025, 1, 29, 0, 0, 0, 0, time.Local), time.Date(2025, 4, 10, 0, 0, 0, 0, time.Local), time.Sunday)
+	require.True(t, ok)
+
+	all := g.monthLabels()
+	require.Equal(t, "Jan", all[0].name)
+
+	spaced := g.spacedMonthLabels(2)
+	names := make([]string, 0, len(spaced))
+	for _, m := range spaced {
+		names = append(names, m.name)
+	}
+	assert.Equal(t, []string{"Feb", "Mar", "Apr"}, names)
+}
+
+func TestRenderHeatmapWithOptions_ThemeAndThresholdsUpdateLegend(t *testing.T) {
+	loc := time.Local
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, loc)
+	end := time.Date(2024, 6, 30, 0, 0, 0, 0, loc)
+	stats := map[time.Time]int{
+		time.Date(2024, 6, 5, 0, 0, 0, 0, loc): 15,
+	}
+
+	halloween, ok := BuiltinTheme("halloween")
+	require.True(t, ok)
+	th, err := ParseThresholds("1,10,20")
+	require.NoError(t, err)
+
+	result := RenderHeatmapWithOptions(stats, HeatmapOptions{
+		ShowLegend: true,
+		Since:      start,
+		Until:      end,
+		Theme:      halloween,
+		Thresholds: th,
+	})
+
+	p := halloween.palette()
+	assert.Contains(t, result, p.levels[2]+"██", "15 commits falls into the 10-19 bucket")
+	assert.NotContains(t, result, colorHigh)
+	assert.Contains(t, stripANSI(result), "0  1-9 10-19 20+")
+}
+
+func TestRenderHeatmapWithOptions_NoColorAndASCII(t *testing.T) {
+	loc := time.Local
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, loc)
+	end := time.Date(2024, 6, 30, 0, 0, 0, 0, loc)
+	stats := map[time.Time]int{
+		time.Date(2024, 6, 5, 0, 0, 0, 0, loc):  2,
+		time.Date(2024, 6, 10, 0, 0, 0, 0, loc): 7,
+		time.Date(2024, 6, 15, 0, 0, 0, 0, loc): 15,
+	}
+
+	plain := RenderHeatmapWithOptions(stats, HeatmapOptions{
+		ShowLegend: true, ShowSummary: true,
+		Since: start, Until: end,
+		NoColor: true,
+	})
+	assert.NotContains(t, plain, "\x1b[", "no ANSI escapes without color")
+	assert.Contains(t, plain, "Less ░░ ▒▒ ▓▓ ██ More", "levels use distinct shades without color")
+
+	ascii := RenderHeatmapWithOptions(stats, HeatmapOptions{
+		ShowLegend: true, ShowSummary: true,
+		Since: start, Until: end,
+		NoColor: true, Charset: CharsetASCII,
+	})
+	for _, r := range ascii {
+		require.Less(t, r, rune(0x80), "ascii output contains %q", r)
+	}
+	assert.Contains(t, ascii, "Less .. oo OO @@ More")
+	assert.Contains(t, ascii, "Total: 24 commits | Active days: 3")
+
+	// ASCII 字符集与颜色可以同时使用
+	colored := RenderHeatmapWithOptions(stats, HeatmapOptions{Since: start, Until: end, Charset: CharsetASCII})
+	assert.Contains(t, colored, colorHigh+"@@"+colorReset)
+}
+
+func TestParseCharset(t *testing.T) {
+	c, err := ParseCharset("")
+	require.NoError(t, err)
+	assert.Equal(t, CharsetUnicode, c)
+
+	c, err = ParseCharset("ASCII")
+	require.NoError(t, err)
+	assert.Equal(t, CharsetASCII, c)
+
+	_, err = ParseCharset("latin1")
+	assert.Error(t, err)
+}
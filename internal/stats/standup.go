@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"sort"
+	"time"
+)
+
+// RepoCommits 是单个仓库的提交列表，用于按仓库分组展示。
+type RepoCommits struct {
+	Repo    string      `json:"repo"`
+	Commits []LogCommit `json:"commits"`
+}
+
+// WorkingDaysBefore 返回 day 之前第 n 个工作日（跳过周六、周日），n <= 0 时返回 day 当天的起点。
+// 例如周一的前 1 个工作日为上周五，周二为周一。
+func WorkingDaysBefore(day time.Time, n int) time.Time {
+	day = beginningOfDay(day, day.Location())
+	for n > 0 {
+		day = day.AddDate(0, 0, -1)
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			n--
+		}
+	}
+	return day
+}
+
+// GroupCommitsByRepo 按仓库分组提交，组内按时间从旧到新排列；
+// 仓库按其最早一次提交的时间排序，时间相同时按路径排序。
+func GroupCommitsByRepo(commits []LogCommit) []RepoCommits {
+	index := make(map[string]int)
+	var groups []RepoCommits
+	for _, c := range commits {
+		i, ok := index[c.Repo]
+		if !ok {
+			i = len(groups)
+			index[c.Repo] = i
+			groups = append(groups, RepoCommits{Repo: c.Repo})
+		}
+		groups[i].Commits = append(groups[i].Commits, c)
+	}
+
+	for _, g := range groups {
+		SortLogCommits(g.Commits)
+		// SortLogCommits 为从新到旧，此处反转为从旧到新
+		for i, j := 0, len(g.Commits)-1; i < j; i, j = i+1, j-1 {
+			g.Commits[i], g.Commits[j] = g.Commits[j], g.Commits[i]
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].Commits[0].Time, groups[j].Commits[0].Time
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return groups[i].Repo < groups[j].Repo
+	})
+	return groups
+}
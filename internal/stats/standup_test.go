@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkingDaysBefore(t *testing.T) {
+	monday := time.Date(2025, 3, 17, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		day  time.Time
+		n    int
+		want string
+	}{
+		{"monday skips weekend", monday, 1, "2025-03-14"},
+		{"tuesday", monday.AddDate(0, 0, 1), 1, "2025-03-17"},
+		{"saturday", monday.AddDate(0, 0, -2), 1, "2025-03-14"},
+		{"sunday", monday.AddDate(0, 0, -1), 1, "2025-03-14"},
+		{"three working days from monday", monday, 3, "2025-03-12"},
+		{"zero is today", monday, 0, "2025-03-17"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WorkingDaysBefore(tt.day, tt.n)
+			assert.Equal(t, tt.want, got.Format("2006-01-02"))
+			assert.Zero(t, got.Hour())
+		})
+	}
+}
+
+func TestGroupCommitsByRepo_OldestFirst(t *testing.T) {
+	base := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	commits := []LogCommit{
+		{Repo: "/web", Hash: "w2", Time: base.Add(5 * time.Hour)},
+		{Repo: "/api", Hash: "a2", Time: base.Add(3 * time.Hour)},
+		{Repo: "/web", Hash: "w1", Time: base.Add(2 * time.Hour)},
+		{Repo: "/api", Hash: "a1", Time: base},
+	}
+
+	groups := GroupCommitsByRepo(commits)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "/api", groups[0].Repo)
+	assert.Equal(t, []string{"a1", "a2"}, []string{groups[0].Commits[0].Hash, groups[0].Commits[1].Hash})
+	assert.Equal(t, "/web", groups[1].Repo)
+	assert.Equal(t, []string{"w1", "w2"}, []string{groups[1].Commits[0].Hash, groups[1].Commits[1].Hash})
+
+	assert.Empty(t, GroupCommitsByRepo(nil))
+}
@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Charset 表示终端渲染使用的字符集。
+type Charset int
+
+const (
+	// CharsetUnicode 使用方块字符（░░ ██）与制表符分隔线，默认值。
+	CharsetUnicode Charset = iota
+	// CharsetASCII 只输出 ASCII 字符（. o O @），适合不支持 Unicode 的终端与日志。
+	CharsetASCII
+)
+
+// ParseCharset 解析字符集名称：""/unicode 或 ascii。
+func ParseCharset(s string) (Charset, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "unicode", "utf8", "utf-8":
+		return CharsetUnicode, nil
+	case "ascii":
+		return CharsetASCII, nil
+	default:
+		return CharsetUnicode, fmt.Errorf("unsupported charset %q (supported: unicode, ascii)", s)
+	}
+}
+
+// asciiReplacer 将摘要等文本中的制表符替换为 ASCII 等价字符。
+var asciiReplacer = strings.NewReplacer("─", "-", "│", "|", "–", "-")
+
+// ToASCII 将终端文本中的分隔线与竖线替换为 ASCII 字符，其余内容保持不变。
+func ToASCII(s string) string {
+	return asciiReplacer.Replace(s)
+}
+
+// cellStyle 决定终端单元格使用的字符与颜色。
+type cellStyle struct {
+	palette ansiPalette
+	glyphs  [4]string // 各档位的单元格字符（均为 2 列宽）
+	color   bool
+}
+
+// newCellStyle 根据主题、是否着色与字符集构建单元格样式。
+// 不着色时各档位需要靠字符区分：Unicode 使用 ░▒▓█ 四级灰度，ASCII 使用 . o O @。
+func newCellStyle(theme Theme, noColor bool, charset Charset) cellStyle {
+	s := cellStyle{palette: theme.palette(), color: !noColor}
+	switch {
+	case charset == CharsetASCII:
+		s.glyphs = [4]string{"..", "oo", "OO", "@@"}
+	case noColor:
+		s.glyphs = [4]string{"░░", "▒▒", "▓▓", "██"}
+	default:
+		s.glyphs = [4]string{"░░", "██", "██", "██"}
+	}
+	return s
+}
+
+// defaultCellStyle 是默认主题、着色、Unicode 字符集的样式。
+func defaultCellStyle() cellStyle {
+	return newCellStyle(DefaultTheme(), false, CharsetUnicode)
+}
+
+// paint 返回指定档位的单元格字符；着色时包裹 ANSI 颜色，今天使用高亮色。
+func (s cellStyle) paint(level int, today bool) string {
+	if !s.color {
+		return s.glyphs[level]
+	}
+	color := s.palette.levels[level]
+	if today {
+		color = s.palette.today
+	}
+	return color + s.glyphs[level] + colorReset
+}
+
+// paintCursor 返回光标所在单元格的字符：着色时对档位字符反色显示，
+// 不着色时改用 "[]"（compact 版面为 "X"），保证无颜色时光标仍可辨认。
+func (s cellStyle) paintCursor(level int) string {
+	if !s.color {
+		if s.width() == 1 {
+			return "X"
+		}
+		return "[]"
+	}
+	return colorReverse + s.palette.levels[level] + s.glyphs[level] + colorReset
+}
+
+// width 返回单元格字符的显示宽度（列数）。
+func (s cellStyle) width() int {
+	return utf8.RuneCountInString(s.glyphs[0])
+}
+
+// narrow 返回每个单元格只占 1 列的样式，用于 compact 版面。
+func (s cellStyle) narrow() cellStyle {
+	for i, g := range s.glyphs {
+		r, _ := utf8.DecodeRuneInString(g)
+		s.glyphs[i] = string(r)
+	}
+	return s
+}
@@ -53,10 +53,6 @@ func CalculateSummary(stats map[time.Time]int) Summary {
 		return out
 	}
 
-	now := timeNow()
-	loc := now.Location()
-	today := beginningOfDay(now, loc)
-
 	var weekdayTotals [7]int
 	days := make([]time.Time, 0, len(stats))
 
@@ -83,6 +79,10 @@ func CalculateSummary(stats map[time.Time]int) Summary {
 
 	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
 
+	// "今天"取统计日期所在的时区，与按天划分保持一致。
+	loc := days[len(days)-1].Location()
+	today := beginningOfDay(currentTime(loc), loc)
+
 	// Current streak: from today backwards, consecutive days with commits.
 	if stats[today] > 0 {
 		for d := today; stats[d] > 0; d = d.AddDate(0, 0, -1) {
@@ -152,16 +152,16 @@ func RenderSummary(s Summary) string {
 		s.TotalCommits,
 		s.ActiveDays,
 		s.CurrentStreak,
-		pluralize(s.CurrentStreak, "day", "days"),
+		Pluralize(s.CurrentStreak, "day", "days"),
 	))
 
 	if s.LongestStreak.Days == 0 || s.LongestStreak.Start.IsZero() || s.LongestStreak.End.IsZero() {
-		b.WriteString(fmt.Sprintf("Longest streak: %d %s\n", s.LongestStreak.Days, pluralize(s.LongestStreak.Days, "day", "days")))
+		b.WriteString(fmt.Sprintf("Longest streak: %d %s\n", s.LongestStreak.Days, Pluralize(s.LongestStreak.Days, "day", "days")))
 	} else {
 		b.WriteString(fmt.Sprintf(
 			"Longest streak: %d %s (%s - %s)\n",
 			s.LongestStreak.Days,
-			pluralize(s.LongestStreak.Days, "day", "days"),
+			Pluralize(s.LongestStreak.Days, "day", "days"),
 			formatShortDate(s.LongestStreak.Start),
 			formatShortDate(s.LongestStreak.End),
 		))
@@ -188,6 +188,25 @@ func RenderSummary(s Summary) string {
 	return b.String()
 }
 
+// RenderLineTotals 渲染代码行变更合计，格式与 RenderSummary 的分栏风格一致。
+func RenderLineTotals(l LineTotals) string {
+	return fmt.Sprintf(
+		"Lines: +%d / -%d │ Files changed: %d\n",
+		l.Additions,
+		l.Deletions,
+		l.Files,
+	)
+}
+
+// RenderMergeTotals 渲染合并提交数及其在全部提交中的占比。
+func RenderMergeTotals(merges, commits int) string {
+	pct := 0.0
+	if commits > 0 {
+		pct = float64(merges) / float64(commits) * 100
+	}
+	return fmt.Sprintf("Merge commits: %d (%.1f%% of commits)\n", merges, pct)
+}
+
 // WeekdayAbbrev 返回星期几的 3 字符缩写（如 Mon, Tue）。
 func WeekdayAbbrev(wd time.Weekday) string {
 	name := wd.String()
@@ -202,8 +221,8 @@ func formatShortDate(t time.Time) string {
 	return t.Format("Jan 02")
 }
 
-// pluralize 根据数量返回单数或复数形式。
-func pluralize(n int, singular, plural string) string {
+// Pluralize 根据数量返回单数或复数形式。
+func Pluralize(n int, singular, plural string) string {
 	if n == 1 {
 		return singular
 	}
@@ -0,0 +1,251 @@
+package stats
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultSVGCellSize 是 SVG 热力图默认的单元格边长（像素）。
+const DefaultSVGCellSize = 11
+
+// SVGColors 定义 SVG 热力图使用的颜色（CSS 颜色值），空字段使用默认配色。
+type SVGColors struct {
+	Background string
+	Text       string
+	Empty      string
+	Low        string
+	Medium     string
+	High       string
+	Today      string // 今天单元格的描边颜色
+}
+
+// DefaultSVGColors 返回与 GitHub 贡献图相近的默认配色。
+func DefaultSVGColors() SVGColors {
+	return SVGColors{
+		Background: "#ffffff",
+		Text:       "#57606a",
+		Empty:      "#ebedf0",
+		Low:        "#9be9a8",
+		Medium:     "#40c463",
+		High:       "#216e39",
+		Today:      "#ff5fd7",
+	}
+}
+
+// withDefaults 用默认配色补全未设置的字段。
+func (c SVGColors) withDefaults() SVGColors {
+	d := DefaultSVGColors()
+	for _, f := range []struct {
+		dst *string
+		def string
+	}{
+		{&c.Background, d.Background},
+		{&c.Text, d.Text},
+		{&c.Empty, d.Empty},
+		{&c.Low, d.Low},
+		{&c.Medium, d.Medium},
+		{&c.High, d.High},
+		{&c.Today, d.Today},
+	} {
+		if strings.TrimSpace(*f.dst) == "" {
+			*f.dst = f.def
+		}
+	}
+	return c
+}
+
+// SVGOptions 控制 SVG 热力图的内容与样式。
+type SVGOptions struct {
+	ShowLegend  bool
+	ShowSummary bool
+	Since       time.Time // zero value = auto-calculated from months
+	Until       time.Time // zero value = now
+	CellSize    int       // 单元格边长（像素），<= 0 时使用 DefaultSVGCellSize
+	Colors      SVGColors
+	Thresholds  Thresholds   // 档位划分，零值为默认固定档位
+	WeekStart   time.Weekday // 每列（周）的第一天，零值为周日
+	// SummaryFooter 追加在摘要之后的文本（如合并提交、代码行合计），按行拆分渲染。
+	SummaryFooter string
+}
+
+// svgLayout 是 SVG 中各元素的像素尺寸，由单元格大小推导。
+type svgLayout struct {
+	cell     int // 单元格边长
+	gap      int // 单元格间距
+	fontSize int // 标签字号
+	left     int // 星期标签区域宽度
+	top      int // 月份标签区域高度
+	margin   int // 外边距
+}
+
+// newSVGLayout 按单元格边长推导间距、字号与边距。
+func newSVGLayout(cellSize int) svgLayout {
+	if cellSize <= 0 {
+		cellSize = DefaultSVGCellSize
+	}
+	l := svgLayout{
+		cell:     cellSize,
+		gap:      max(2, cellSize/5),
+		fontSize: max(8, cellSize-1),
+		margin:   max(4, cellSize/2),
+	}
+	l.left = l.fontSize*2 + l.gap*2
+	l.top = l.fontSize + l.gap*2
+	return l
+}
+
+// pitch 返回相邻单元格左上角的距离。
+func (l svgLayout) pitch() int {
+	return l.cell + l.gap
+}
+
+// RenderHeatmapSVG 将热力图渲染为独立的 SVG 文档。
+// 版面与终端热力图一致（按 WeekStart 对齐的周列、月份标题、Mon/Wed/Fri 标签），
+// 每个单元格带 <title> 悬浮提示；可选附带图例与摘要。
+func RenderHeatmapSVG(stats map[time.Time]int, opts SVGOptions) string {
+	start, end := resolveHeatmapRange(opts.Since, opts.Until, opts.WeekStart)
+	g, ok := newHeatmapGrid(start, end, opts.WeekStart)
+	if !ok {
+		return ""
+	}
+
+	l := newSVGLayout(opts.CellSize)
+	colors := opts.Colors.withDefaults()
+	levelColors := [4]string{colors.Empty, colors.Low, colors.Medium, colors.High}
+	scale := opts.Thresholds.scale(stats, g)
+
+	gridW := len(g.weekStarts)*l.pitch() - l.gap
+	gridH := 7*l.pitch() - l.gap
+	width := l.margin*2 + l.left + gridW
+	height := l.margin + l.top + gridH
+
+	var summaryLines []string
+	if opts.ShowSummary {
+		summaryLines = svgSummaryLines(RenderSummary(CalculateSummary(stats)) + opts.SummaryFooter)
+	}
+	// 文本宽度按平均字宽（约 0.6em）估算，保证图例与摘要不被裁切
+	for _, line := range summaryLines {
+		width = max(width, l.margin*2+utf8.RuneCountInString(line)*l.fontSize*6/10)
+	}
+	lineHeight := l.fontSize + l.gap*2
+	legendY := height + l.gap*2
+	if opts.ShowLegend {
+		width = max(width, l.margin+l.left+l.legendWidth(scale.labels())+l.margin)
+		height += l.gap*2 + l.cell + lineHeight
+	}
+	summaryY := height + l.gap
+	height += len(summaryLines) * lineHeight
+	height += l.margin
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, l.fontSize)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgAttr(colors.Background))
+
+	originX := l.margin + l.left
+	originY := l.margin + l.top
+
+	// 月份标题
+	fmt.Fprintf(&b, `<g fill="%s">`+"\n", svgAttr(colors.Text))
+	// 月份缩写宽度按约 1.8em 估算
+	for _, m := range g.spacedMonthLabels((l.fontSize*18/10 + l.gap + l.pitch() - 1) / l.pitch()) {
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", originX+m.col*l.pitch(), originY-l.gap*2, m.name)
+	}
+	// 星期标签（与终端一致，只标注周一、周三、周五）
+	for row := 0; row < 7; row++ {
+		label := strings.TrimSpace(weekdayLabel(g.weekday(row)))
+		if label == "" {
+			continue
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle">%s</text>`+"\n", l.margin, originY+row*l.pitch()+l.cell/2, label)
+	}
+	b.WriteString("</g>\n")
+
+	// 单元格
+	radius := max(1, l.cell/5)
+	b.WriteString("<g>\n")
+	for col := range g.weekStarts {
+		for row := 0; row < 7; row++ {
+			day, ok := g.day(col, row)
+			if !ok {
+				continue
+			}
+			count := stats[day]
+			stroke := ""
+			if day.Equal(g.today) {
+				stroke = fmt.Sprintf(` stroke="%s" stroke-width="1.5"`, svgAttr(colors.Today))
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s"%s><title>%s: %d commits</title></rect>`+"\n",
+				originX+col*l.pitch(), originY+row*l.pitch(), l.cell, l.cell, radius,
+				svgAttr(levelColors[scale.level(count)]), stroke, day.Format("2006-01-02"), count)
+		}
+	}
+	b.WriteString("</g>\n")
+
+	if opts.ShowLegend {
+		writeSVGLegend(&b, l, colors, levelColors, scale.labels(), originX, legendY)
+	}
+
+	if len(summaryLines) > 0 {
+		fmt.Fprintf(&b, `<g fill="%s">`+"\n", svgAttr(colors.Text))
+		for i, line := range summaryLines {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="hanging">%s</text>`+"\n", l.margin, summaryY+i*lineHeight, html.EscapeString(line))
+		}
+		b.WriteString("</g>\n")
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// legendWidth 返回图例（Less、四个色块、More）的估算宽度。
+func (l svgLayout) legendWidth(labels [4]string) int {
+	labelW := l.fontSize * 3
+	return labelW + 4*l.legendStep(labels) + labelW
+}
+
+// legendStep 返回图例相邻色块的间距，保证居中的档位说明互不重叠。
+func (l svgLayout) legendStep(labels [4]string) int {
+	widest := 0
+	for _, label := range labels {
+		widest = max(widest, utf8.RuneCountInString(label)*l.fontSize*6/10+l.gap)
+	}
+	return l.cell + max(l.fontSize*3/2, widest-l.cell/2)
+}
+
+// writeSVGLegend 写入 "Less ■ ■ ■ ■ More" 图例及其下方的档位说明。
+func writeSVGLegend(b *strings.Builder, l svgLayout, colors SVGColors, levelColors, labels [4]string, x, y int) {
+	labelW := l.fontSize * 3
+	step := l.legendStep(labels)
+	fmt.Fprintf(b, `<g fill="%s">`+"\n", svgAttr(colors.Text))
+	fmt.Fprintf(b, `<text x="%d" y="%d" dominant-baseline="middle">Less</text>`+"\n", x, y+l.cell/2)
+	cx := x + labelW
+	for i, c := range levelColors {
+		cellX := cx + i*step
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s"/>`+"\n", cellX, y, l.cell, l.cell, max(1, l.cell/5), svgAttr(c))
+		fmt.Fprintf(b, `<text x="%d" y="%d" dominant-baseline="hanging" text-anchor="middle">%s</text>`+"\n", cellX+l.cell/2, y+l.cell+l.gap, labels[i])
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d" dominant-baseline="middle">More</text>`+"\n", cx+len(levelColors)*step, y+l.cell/2)
+	b.WriteString("</g>\n")
+}
+
+// svgSummaryLines 将终端摘要拆分为文本行，去掉分隔线与空行。
+func svgSummaryLines(summary string) []string {
+	lines := make([]string, 0, 4)
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Trim(line, "─") == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// svgAttr 转义属性值。
+func svgAttr(s string) string {
+	return html.EscapeString(strings.TrimSpace(s))
+}
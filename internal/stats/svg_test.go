@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHeatmapSVG_CellsAndTooltips(t *testing.T) {
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local)
+	data := map[time.Time]int{
+		time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local):  3,
+		time.Date(2025, 6, 3, 0, 0, 0, 0, time.Local):  7,
+		time.Date(2025, 6, 4, 0, 0, 0, 0, time.Local):  12,
+		time.Date(2025, 7, 10, 0, 0, 0, 0, time.Local): 5, // 超出范围，不渲染
+	}
+
+	svg := RenderHeatmapSVG(data, SVGOptions{Since: since, Until: until})
+	require.True(t, strings.HasPrefix(svg, "<svg "), "svg=%s", svg)
+	require.NoError(t, xml.Unmarshal([]byte(svg), new(struct{})), "output should be well-formed XML")
+
+	d := DefaultSVGColors()
+	assert.Contains(t, svg, `fill="`+d.Low+`"><title>2025-06-02: 3 commits</title>`)
+	assert.Contains(t, svg, `fill="`+d.Medium+`"><title>2025-06-03: 7 commits</title>`)
+	assert.Contains(t, svg, `fill="`+d.High+`"><title>2025-06-04: 12 commits</title>`)
+	assert.Contains(t, svg, `fill="`+d.Empty+`"><title>2025-06-30: 0 commits</title>`)
+	assert.NotContains(t, svg, "2025-07-10")
+	assert.Equal(t, 30, strings.Count(svg, "<title>"), "one cell per day in range")
+	assert.Contains(t, svg, ">Jun</text>")
+	assert.Contains(t, svg, ">Mon</text>")
+	assert.NotContains(t, svg, "Less", "legend disabled")
+}
+
+func TestRenderHeatmapSVG_LegendSummaryAndOptions(t *testing.T) {
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(2025, 6, 14, 0, 0, 0, 0, time.Local)
+	data := map[time.Time]int{time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local): 2}
+
+	svg := RenderHeatmapSVG(data, SVGOptions{
+		ShowLegend:    true,
+		ShowSummary:   true,
+		Since:         since,
+		Until:         until,
+		CellSize:      20,
+		Colors:        SVGColors{Low: "#123456"},
+		SummaryFooter: "Merge commits: 0\n",
+	})
+	require.NoError(t, xml.Unmarshal([]byte(svg), new(struct{})))
+
+	assert.Contains(t, svg, `width="20" height="20"`)
+	assert.Contains(t, svg, `fill="#123456"><title>2025-06-02: 2 commits</title>`)
+	assert.Contains(t, svg, DefaultSVGColors().Empty, "unset colors fall back to defaults")
+	labels := defaultScale.labels()
+	for _, label := range append([]string{"Less", "More"}, labels[:]...) {
+		assert.Contains(t, svg, ">"+label+"</text>")
+	}
+	assert.Contains(t, svg, "Total: 2 commits")
+	assert.Contains(t, svg, ">Merge commits: 0</text>")
+}
+
+func TestRenderHeatmapSVG_InvalidRange(t *testing.T) {
+	since := time.Date(2025, 6, 10, 0, 0, 0, 0, time.Local)
+	until := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	assert.Empty(t, RenderHeatmapSVG(nil, SVGOptions{Since: since, Until: until}))
+}
@@ -0,0 +1,248 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultThemeName 是默认主题名称。
+const DefaultThemeName = "github"
+
+// Theme 定义热力图各档位的配色。
+// Levels/Today 为 hex 颜色，用于 SVG/PNG/HTML；终端使用 ANSI 序列，
+// 内置主题单独调校（适配深色终端），自定义主题由 hex 推导为最接近的 256 色。
+type Theme struct {
+	Name   string
+	Levels [4]string // 各档位颜色：无提交、低、中、高
+	Today  string    // 今天的高亮颜色
+
+	ansi *ansiPalette // 内置主题的终端配色，nil 时由 hex 推导
+}
+
+// ansiPalette 是终端渲染使用的 ANSI 前景色序列。
+type ansiPalette struct {
+	levels [4]string
+	today  string
+}
+
+// githubPalette 是默认主题的终端配色，与早期版本的固定配色一致。
+var githubPalette = ansiPalette{
+	levels: [4]string{colorEmpty, colorLow, colorMedium, colorHigh},
+	today:  colorToday,
+}
+
+// builtinThemes 是内置主题，按名称排序。
+var builtinThemes = []Theme{
+	{
+		Name:   "colorblind-safe",
+		Levels: [4]string{"#ebedf0", "#9ecae1", "#4292c6", "#08519c"},
+		Today:  "#f28e2b",
+		ansi: &ansiPalette{
+			levels: [4]string{ansi256(240), ansi256(153), ansi256(75), ansi256(27)},
+			today:  ansi256(208),
+		},
+	},
+	{
+		Name:   DefaultThemeName,
+		Levels: [4]string{"#ebedf0", "#9be9a8", "#40c463", "#216e39"},
+		Today:  "#ff5fd7",
+		ansi:   &githubPalette,
+	},
+	{
+		Name:   "halloween",
+		Levels: [4]string{"#ebedf0", "#ffee4a", "#ffc501", "#fe9600"},
+		Today:  "#6f42c1",
+		ansi: &ansiPalette{
+			levels: [4]string{ansi256(240), ansi256(228), ansi256(214), ansi256(202)},
+			today:  ansi256(135),
+		},
+	},
+	{
+		Name:   "monochrome",
+		Levels: [4]string{"#ebedf0", "#bdbdbd", "#737373", "#252525"},
+		Today:  "#000000",
+		ansi: &ansiPalette{
+			levels: [4]string{ansi256(238), ansi256(244), ansi256(249), ansi256(255)},
+			today:  "\033[4;38;5;255m", // 灰阶中用下划线区分今天
+		},
+	},
+}
+
+// DefaultTheme 返回默认（github）主题。
+func DefaultTheme() Theme {
+	t, _ := BuiltinTheme(DefaultThemeName)
+	return t
+}
+
+// BuiltinTheme 按名称（大小写不敏感）查找内置主题。
+func BuiltinTheme(name string) (Theme, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, t := range builtinThemes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// BuiltinThemeNames 返回全部内置主题名称（已排序）。
+func BuiltinThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for _, t := range builtinThemes {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// NewTheme 基于 hex 颜色构建自定义主题；未设置的档位沿用默认主题的颜色。
+func NewTheme(name string, levels [4]string, today string) (Theme, error) {
+	t := DefaultTheme()
+	t.Name = name
+	t.ansi = nil
+	for i, c := range levels {
+		if strings.TrimSpace(c) == "" {
+			continue
+		}
+		if _, err := parseHexColor(c); err != nil {
+			return Theme{}, fmt.Errorf("theme %q: %s: %w", name, levelNames[i], err)
+		}
+		t.Levels[i] = strings.TrimSpace(c)
+	}
+	if strings.TrimSpace(today) != "" {
+		if _, err := parseHexColor(today); err != nil {
+			return Theme{}, fmt.Errorf("theme %q: today: %w", name, err)
+		}
+		t.Today = strings.TrimSpace(today)
+	}
+	return t, nil
+}
+
+// ResolveTheme 按名称查找主题：先查 custom（自定义主题），再查内置主题；空名称返回默认主题。
+func ResolveTheme(name string, custom map[string]Theme) (Theme, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return DefaultTheme(), nil
+	}
+	for k, t := range custom {
+		if strings.EqualFold(k, name) {
+			return t, nil
+		}
+	}
+	if t, ok := BuiltinTheme(name); ok {
+		return t, nil
+	}
+
+	available := BuiltinThemeNames()
+	for k := range custom {
+		available = append(available, k)
+	}
+	sort.Strings(available)
+	return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(available, ", "))
+}
+
+// levelNames 是各档位在配置中的名称。
+var levelNames = [4]string{"empty", "low", "medium", "high"}
+
+// orDefault 返回 t 本身，零值主题返回默认主题。
+func (t Theme) orDefault() Theme {
+	if t.Name == "" && t.Levels == [4]string{} {
+		return DefaultTheme()
+	}
+	return t
+}
+
+// palette 返回终端配色；自定义主题由 hex 颜色推导最接近的 256 色。
+func (t Theme) palette() ansiPalette {
+	t = t.orDefault()
+	if t.ansi != nil {
+		return *t.ansi
+	}
+	var p ansiPalette
+	for i, c := range t.Levels {
+		p.levels[i] = hexToANSI(c)
+	}
+	p.today = hexToANSI(t.Today)
+	return p
+}
+
+// SVGColors 返回主题对应的图片配色（背景与文字使用默认值）。
+func (t Theme) SVGColors() SVGColors {
+	t = t.orDefault()
+	c := DefaultSVGColors()
+	c.Empty, c.Low, c.Medium, c.High = t.Levels[0], t.Levels[1], t.Levels[2], t.Levels[3]
+	c.Today = t.Today
+	return c
+}
+
+// Override 返回以 o 中非空字段覆盖后的配色。
+func (c SVGColors) Override(o SVGColors) SVGColors {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&c.Background, o.Background},
+		{&c.Text, o.Text},
+		{&c.Empty, o.Empty},
+		{&c.Low, o.Low},
+		{&c.Medium, o.Medium},
+		{&c.High, o.High},
+		{&c.Today, o.Today},
+	} {
+		if strings.TrimSpace(f.src) != "" {
+			*f.dst = f.src
+		}
+	}
+	return c
+}
+
+// ansi256 返回 256 色前景色序列。
+func ansi256(n int) string {
+	return fmt.Sprintf("\033[38;5;%dm", n)
+}
+
+// hexToANSI 将 hex 颜色转换为最接近的 256 色前景色序列，无法解析时返回默认主题的空档位颜色。
+func hexToANSI(hex string) string {
+	c, err := parseHexColor(hex)
+	if err != nil {
+		return colorEmpty
+	}
+	return ansi256(nearestANSI256(int(c.R), int(c.G), int(c.B)))
+}
+
+// nearestANSI256 返回 xterm 256 色中（6x6x6 色立方与 24 级灰阶）与 RGB 最接近的色号。
+func nearestANSI256(r, g, b int) int {
+	cubeLevels := [6]int{0, 95, 135, 175, 215, 255}
+	nearestLevel := func(v int) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if abs(v-l) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	dist := func(r2, g2, b2 int) int {
+		return (r-r2)*(r-r2) + (g-g2)*(g-g2) + (b-b2)*(b-b2)
+	}
+
+	ri, gi, bi := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDist := dist(cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// 灰阶 232-255：8, 18, ..., 238
+	grayIdx := min(23, max(0, ((r+g+b)/3-8+5)/10))
+	gv := 8 + 10*grayIdx
+	if dist(gv, gv, gv) < cubeDist {
+		return 232 + grayIdx
+	}
+	return cube
+}
+
+// abs 返回整数绝对值。
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
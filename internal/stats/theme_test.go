@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTheme_BuiltinAndDefault(t *testing.T) {
+	def, err := ResolveTheme("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultThemeName, def.Name)
+	assert.Equal(t, githubPalette, def.palette(), "default theme keeps the original terminal colors")
+
+	for _, name := range []string{"github", "Halloween", "colorblind-safe", "monochrome"} {
+		theme, err := ResolveTheme(name, nil)
+		require.NoError(t, err, name)
+		assert.NotEmpty(t, theme.palette().levels[3], name)
+	}
+}
+
+func TestResolveTheme_CustomOverridesBuiltinAndUnknownFails(t *testing.T) {
+	ocean, err := NewTheme("ocean", [4]string{"", "#caf0f8", "#48cae4", "#023e8a"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTheme().Levels[0], ocean.Levels[0], "unset levels fall back to github")
+	assert.Equal(t, DefaultTheme().Today, ocean.Today)
+
+	theme, err := ResolveTheme("OCEAN", map[string]Theme{"ocean": ocean})
+	require.NoError(t, err)
+	assert.Equal(t, "#023e8a", theme.Levels[3])
+	assert.Equal(t, ansi256(nearestANSI256(0x02, 0x3e, 0x8a)), theme.palette().levels[3], "custom themes derive terminal colors from hex")
+
+	_, err = ResolveTheme("sunset", map[string]Theme{"ocean": ocean})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown theme "sunset"`)
+	assert.Contains(t, err.Error(), "halloween, monochrome, ocean")
+}
+
+func TestNewTheme_InvalidColor(t *testing.T) {
+	_, err := NewTheme("bad", [4]string{"", "green", "", ""}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `theme "bad": low`)
+}
+
+func TestNearestANSI256(t *testing.T) {
+	assert.Equal(t, 16, nearestANSI256(0, 0, 0))
+	assert.Equal(t, 231, nearestANSI256(255, 255, 255))
+	assert.Equal(t, 196, nearestANSI256(255, 0, 0))
+	assert.Equal(t, 244, nearestANSI256(0x80, 0x80, 0x80), "grays map to the grayscale ramp")
+}
+
+func TestThemeSVGColors_OverrideKeepsExplicitColors(t *testing.T) {
+	halloween, _ := BuiltinTheme("halloween")
+	c := halloween.SVGColors().Override(SVGColors{High: "#000000"})
+
+	assert.Equal(t, "#ffee4a", c.Low)
+	assert.Equal(t, "#000000", c.High)
+	assert.Equal(t, DefaultSVGColors().Background, c.Background)
+}
@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ThresholdMode 表示热力图档位的划分方式。
+type ThresholdMode int
+
+const (
+	// ThresholdsFixed 使用固定的档位下限（默认 1 / 5 / 10）。
+	ThresholdsFixed ThresholdMode = iota
+	// ThresholdsQuantile 按统计范围内有提交日的提交数三分位自动计算档位。
+	ThresholdsQuantile
+)
+
+// defaultBounds 是默认固定档位：1-4 / 5-9 / 10+。
+var defaultBounds = [3]int{1, 5, 10}
+
+// Thresholds 定义热力图的档位划分。零值等价于默认固定档位。
+type Thresholds struct {
+	Mode   ThresholdMode
+	Bounds [3]int // 固定模式下低、中、高三档的下限（严格递增且 >= 1）
+}
+
+// ParseThresholds 解析档位配置："" 或 "fixed" 为默认固定档位，"quantile" 为按数据分位，
+// "a,b,c" 为自定义固定档位下限（如 "1,10,20" 表示 1-9 / 10-19 / 20+）。
+func ParseThresholds(s string) (Thresholds, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "fixed":
+		return Thresholds{Bounds: defaultBounds}, nil
+	case "quantile":
+		return Thresholds{Mode: ThresholdsQuantile}, nil
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return Thresholds{}, fmt.Errorf("invalid thresholds %q (expected fixed, quantile, or three increasing numbers like 1,5,10)", s)
+	}
+	var t Thresholds
+	for i, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || n < 1 || (i > 0 && n <= t.Bounds[i-1]) {
+			return Thresholds{}, fmt.Errorf("invalid thresholds %q (expected fixed, quantile, or three increasing numbers like 1,5,10)", s)
+		}
+		t.Bounds[i] = n
+	}
+	return t, nil
+}
+
+// String 返回档位配置的文本形式，可被 ParseThresholds 解析。
+func (t Thresholds) String() string {
+	if t.Mode == ThresholdsQuantile {
+		return "quantile"
+	}
+	b := t.fixedBounds()
+	if b == defaultBounds {
+		return "fixed"
+	}
+	return fmt.Sprintf("%d,%d,%d", b[0], b[1], b[2])
+}
+
+// fixedBounds 返回固定档位下限，未设置时为默认值。
+func (t Thresholds) fixedBounds() [3]int {
+	if t.Bounds == [3]int{} {
+		return defaultBounds
+	}
+	return t.Bounds
+}
+
+// levelScale 是解析后的档位下限：count >= scale[i] 时至少为 i+1 档。
+type levelScale [3]int
+
+// defaultScale 是默认固定档位。
+var defaultScale = levelScale(defaultBounds)
+
+// scale 基于统计范围内的数据确定档位；分位模式下取有提交日提交数的三分位点，
+// 并保证各档下限严格递增，数据为空时退回默认档位。
+func (t Thresholds) scale(stats map[time.Time]int, g heatmapGrid) levelScale {
+	if t.Mode != ThresholdsQuantile {
+		return levelScale(t.fixedBounds())
+	}
+
+	counts := make([]int, 0, len(stats))
+	for day, c := range stats {
+		if c > 0 && !day.Before(g.start) && !day.After(g.end) {
+			counts = append(counts, c)
+		}
+	}
+	if len(counts) == 0 {
+		return defaultScale
+	}
+	sort.Ints(counts)
+	quantile := func(q float64) int {
+		return counts[int(q*float64(len(counts)-1))]
+	}
+
+	s := levelScale{1, quantile(1.0/3) + 1, quantile(2.0/3) + 1}
+	s[1] = max(s[1], s[0]+1)
+	s[2] = max(s[2], s[1]+1)
+	return s
+}
+
+// level 返回提交数对应的档位：0 为最浅，3 为最深。
+func (s levelScale) level(count int) int {
+	switch {
+	case count >= s[2]:
+		return 3
+	case count >= s[1]:
+		return 2
+	case count >= s[0]:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// labels 返回图例中各档位的文字说明（如 "0"、"1-4"、"5-9"、"10+"）。
+func (s levelScale) labels() [4]string {
+	return [4]string{
+		countRange(0, s[0]-1),
+		countRange(s[0], s[1]-1),
+		countRange(s[1], s[2]-1),
+		fmt.Sprintf("%d+", s[2]),
+	}
+}
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThresholds(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{"", "fixed"},
+		{"fixed", "fixed"},
+		{"Quantile", "quantile"},
+		{"1, 10, 20", "1,10,20"},
+		{"1,5,10", "fixed"},
+	} {
+		th, err := ParseThresholds(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, th.String(), tc.in)
+	}
+
+	for _, bad := range []string{"auto", "1,5", "0,5,10", "5,5,10", "1,x,10"} {
+		_, err := ParseThresholds(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestThresholds_FixedLevelsAndLabels(t *testing.T) {
+	th, err := ParseThresholds("1,10,20")
+	require.NoError(t, err)
+	s := th.scale(nil, heatmapGrid{})
+
+	assert.Equal(t, [4]string{"0", "1-9", "10-19", "20+"}, s.labels())
+	assert.Equal(t, 1, s.level(9))
+	assert.Equal(t, 2, s.level(10))
+	assert.Equal(t, 3, s.level(25))
+	assert.Equal(t, [4]string{"0", "1-4", "5-9", "10+"}, defaultScale.labels())
+}
+
+func TestThresholds_QuantileFromData(t *testing.T) {
+	loc := time.Local
+	g, ok := newHeatmapGrid(time.Date(2024, 6, 1, 0, 0, 0, 0, loc), time.Date(2024, 6, 30, 0, 0, 0, 0, loc), time.Sunday)
+	require.True(t, ok)
+
+	stats := map[time.Time]int{
+		time.Date(2024, 5, 1, 0, 0, 0, 0, loc): 500, // 范围外，不参与分位计算
+	}
+	for i, c := range []int{10, 20, 30, 40, 50, 60} {
+		stats[time.Date(2024, 6, i+1, 0, 0, 0, 0, loc)] = c
+	}
+
+	s := Thresholds{Mode: ThresholdsQuantile}.scale(stats, g)
+	assert.Equal(t, levelScale{1, 21, 41}, s)
+	assert.Equal(t, 1, s.level(20), "6 个值三等分：10,20 | 30,40 | 50,60")
+	assert.Equal(t, 3, s.level(60))
+
+	// 无数据时退回默认档位；数据集中时档位仍保持严格递增
+	assert.Equal(t, defaultScale, Thresholds{Mode: ThresholdsQuantile}.scale(nil, g))
+	flat := map[time.Time]int{time.Date(2024, 6, 3, 0, 0, 0, 0, loc): 1}
+	assert.Equal(t, levelScale{1, 2, 3}, Thresholds{Mode: ThresholdsQuantile}.scale(flat, g))
+}
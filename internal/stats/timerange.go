@@ -11,14 +11,16 @@ import (
 //   - ISO 日期: "2025-01-15"
 //   - 年月: "2025-01" → 2025-01-01
 //   - 相对日期: "1w"/"2m"/"1y" → 1周前/2月前/1年前
-func ParseDate(s string) (time.Time, error) {
+//
+// 日期与"今天"按 loc 解释，nil 表示本地时区。
+func ParseDate(s string, loc *time.Location) (time.Time, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return time.Time{}, fmt.Errorf("date is empty")
 	}
 
-	now := timeNow()
-	loc := now.Location()
+	now := currentTime(loc)
+	loc = now.Location()
 
 	// ISO date: YYYY-MM-DD
 	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
@@ -56,13 +58,14 @@ func ParseDate(s string) (time.Time, error) {
 }
 
 // TimeRange 计算时间范围。
-// 优先级: since/until > months
-func TimeRange(since, until string, months int) (start, end time.Time, err error) {
+// 优先级: since/until > months；由 months 推算的起点向前对齐到 weekStart。
+// 日期与"今天"按 loc 解释，nil 表示本地时区。
+func TimeRange(since, until string, months int, weekStart time.Weekday, loc *time.Location) (start, end time.Time, err error) {
 	since = strings.TrimSpace(since)
 	until = strings.TrimSpace(until)
 
-	now := timeNow()
-	loc := now.Location()
+	now := currentTime(loc)
+	loc = now.Location()
 
 	// Default: use months ending today.
 	if since == "" && until == "" {
@@ -70,12 +73,12 @@ func TimeRange(since, until string, months int) (start, end time.Time, err error
 			return time.Time{}, time.Time{}, fmt.Errorf("months must be > 0, got %d", months)
 		}
 		end = beginningOfDay(now, loc)
-		start = heatmapStart(now, months)
+		start = heatmapStart(now, months, weekStart)
 		return start, end, nil
 	}
 
 	if since != "" {
-		t, parseErr := ParseDate(since)
+		t, parseErr := ParseDate(since, loc)
 		if parseErr != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("parse --since: %w", parseErr)
 		}
@@ -83,7 +86,7 @@ func TimeRange(since, until string, months int) (start, end time.Time, err error
 	}
 
 	if until != "" {
-		t, parseErr := ParseDate(until)
+		t, parseErr := ParseDate(until, loc)
 		if parseErr != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("parse --until: %w", parseErr)
 		}
@@ -97,7 +100,7 @@ func TimeRange(since, until string, months int) (start, end time.Time, err error
 		if months <= 0 {
 			return time.Time{}, time.Time{}, fmt.Errorf("months must be > 0, got %d", months)
 		}
-		start = heatmapStart(end, months)
+		start = heatmapStart(end, months, weekStart)
 	}
 
 	if start.After(end) {
@@ -13,7 +13,7 @@ func TestParseDate_ISODate(t *testing.T) {
 	defer func() { timeNow = origNow }()
 	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
 
-	got, err := ParseDate("2025-01-15")
+	got, err := ParseDate("2025-01-15", nil)
 	require.NoError(t, err)
 
 	want := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
@@ -25,7 +25,7 @@ func TestParseDate_YearMonth(t *testing.T) {
 	defer func() { timeNow = origNow }()
 	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
 
-	got, err := ParseDate("2025-01")
+	got, err := ParseDate("2025-01", nil)
 	require.NoError(t, err)
 
 	want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -48,7 +48,7 @@ func TestParseDate_Relative(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.in, func(t *testing.T) {
-			got, err := ParseDate(tt.in)
+			got, err := ParseDate(tt.in, nil)
 			require.NoError(t, err)
 			assert.True(t, got.Equal(tt.want), "ParseDate(%q) = %v, want %v", tt.in, got, tt.want)
 		})
@@ -60,7 +60,7 @@ func TestParseDate_Invalid(t *testing.T) {
 	defer func() { timeNow = origNow }()
 	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
 
-	_, err := ParseDate("not-a-date")
+	_, err := ParseDate("not-a-date", nil)
 	assert.Error(t, err)
 }
 
@@ -69,7 +69,7 @@ func TestTimeRange_SinceAfterUntil(t *testing.T) {
 	defer func() { timeNow = origNow }()
 	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
 
-	_, _, err := TimeRange("2025-02-01", "2025-01-01", 6)
+	_, _, err := TimeRange("2025-02-01", "2025-01-01", 6, time.Sunday, nil)
 	assert.Error(t, err, "since > until should return error")
 }
 
@@ -78,7 +78,7 @@ func TestTimeRange_Priority_SinceUntilOverridesMonths(t *testing.T) {
 	defer func() { timeNow = origNow }()
 	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
 
-	start, end, err := TimeRange("2025-01-01", "2025-06-30", 1)
+	start, end, err := TimeRange("2025-01-01", "2025-06-30", 1, time.Sunday, nil)
 	require.NoError(t, err)
 
 	wantStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -92,7 +92,7 @@ func TestTimeRange_OnlySince(t *testing.T) {
 	defer func() { timeNow = origNow }()
 	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
 
-	start, end, err := TimeRange("2025-01-01", "", 0)
+	start, end, err := TimeRange("2025-01-01", "", 0, time.Sunday, nil)
 	require.NoError(t, err)
 
 	wantStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -106,7 +106,7 @@ func TestTimeRange_OnlyUntil_UsesMonths(t *testing.T) {
 	defer func() { timeNow = origNow }()
 	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
 
-	start, end, err := TimeRange("", "2025-06-30", 6)
+	start, end, err := TimeRange("", "2025-06-30", 6, time.Sunday, nil)
 	require.NoError(t, err)
 
 	wantEnd := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
@@ -116,3 +116,16 @@ func TestTimeRange_OnlyUntil_UsesMonths(t *testing.T) {
 	wantStart := time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC)
 	assert.True(t, start.Equal(wantStart), "start = %v, want %v", start, wantStart)
 }
+
+func TestTimeRange_MonthsAlignToWeekStart(t *testing.T) {
+	origNow := timeNow
+	defer func() { timeNow = origNow }()
+	timeNow = func() time.Time { return time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC) }
+
+	start, _, err := TimeRange("", "2025-06-30", 6, time.Monday, nil)
+	require.NoError(t, err)
+
+	// 2024-12-30 恰好是周一，无需再向前对齐
+	wantStart := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+	assert.True(t, start.Equal(wantStart), "start = %v, want %v", start, wantStart)
+}
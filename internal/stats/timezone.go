@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// 内嵌 IANA 时区数据库，保证精简的 CI 镜像中 --tz 同样可用
+	_ "time/tzdata"
+)
+
+// TimezoneAuthor 表示按每个提交作者时间戳自带的时区划分日期（"author-local day"）。
+const TimezoneAuthor = "author"
+
+// Timezone 决定日期解析与提交按天划分使用的时区。零值为本地时区。
+type Timezone struct {
+	Location    *time.Location // 日期解析、"今天"与按天划分使用的时区，nil 表示本地时区
+	AuthorLocal bool           // 为 true 时每个提交按其作者时间戳记录的时区划分日期
+}
+
+// ParseTimezone 解析时区配置："" 或 "local" 为本地时区，"author" 为按作者时区划分，
+// 其余按 IANA 名称解析（如 UTC、Asia/Shanghai、America/New_York）。
+func ParseTimezone(s string) (Timezone, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "local":
+		return Timezone{}, nil
+	case TimezoneAuthor:
+		return Timezone{AuthorLocal: true}, nil
+	}
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return Timezone{}, fmt.Errorf("invalid timezone %q (expected local, author, or an IANA name like UTC or Europe/Berlin)", s)
+	}
+	return Timezone{Location: loc}, nil
+}
+
+// String 返回时区配置的文本形式，可被 ParseTimezone 解析。
+func (tz Timezone) String() string {
+	switch {
+	case tz.AuthorLocal:
+		return TimezoneAuthor
+	case tz.Location != nil:
+		return tz.Location.String()
+	default:
+		return "local"
+	}
+}
+
+// currentTime 返回当前时间，loc 非 nil 时转换到该时区。
+func currentTime(loc *time.Location) time.Time {
+	t := timeNow()
+	if loc != nil {
+		return t.In(loc)
+	}
+	return t
+}
@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimezone(t *testing.T) {
+	for _, s := range []string{"", "local", " Local "} {
+		tz, err := ParseTimezone(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, Timezone{}, tz, s)
+		assert.Equal(t, "local", tz.String())
+	}
+
+	tz, err := ParseTimezone("Author")
+	require.NoError(t, err)
+	assert.True(t, tz.AuthorLocal)
+	assert.Equal(t, TimezoneAuthor, tz.String())
+
+	tz, err = ParseTimezone("Asia/Tokyo")
+	require.NoError(t, err)
+	require.NotNil(t, tz.Location)
+	assert.Equal(t, "Asia/Tokyo", tz.String())
+
+	_, err = ParseTimezone("Mars/Olympus")
+	assert.ErrorContains(t, err, "invalid timezone")
+}
+
+func TestTimeRange_UsesGivenLocation(t *testing.T) {
+	setFixedNow(t, time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC))
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	// UTC 3/10 20:00 在东京已是 3/11
+	start, end, err := TimeRange("1w", "", 0, time.Sunday, tokyo)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 11, 0, 0, 0, 0, tokyo), end)
+	assert.Equal(t, time.Date(2025, 3, 4, 0, 0, 0, 0, tokyo), start)
+
+	// 未指定时区时仍按 timeNow 所在时区解释
+	_, end, err = TimeRange("1w", "", 0, time.Sunday, nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), end)
+
+	// 当前连续天数按统计日期所在时区的"今天"计算
+	s := CalculateSummary(map[time.Time]int{time.Date(2025, 3, 11, 0, 0, 0, 0, tokyo): 1})
+	assert.Equal(t, 1, s.CurrentStreak)
+}
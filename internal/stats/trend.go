@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultTrendBarWidth 是终端宽度未知时柱状条的最大长度。
+const defaultTrendBarWidth = 40
+
+// TrendOptions 控制趋势视图的渲染。
+type TrendOptions struct {
+	ShowSummary bool
+	Charset     Charset // zero value = unicode
+	Width       int     // 终端宽度（列），<= 0 时柱状条最长 defaultTrendBarWidth
+}
+
+// sparkLevels 是迷你折线图（sparkline）由低到高的字符。
+var sparkLevels = map[Charset][]rune{
+	CharsetUnicode: []rune("▁▂▃▄▅▆▇█"),
+	CharsetASCII:   []rune("_.:-=+*#"),
+}
+
+// RenderTrend 渲染按时间段聚合的趋势视图：每个时间段一行（标签、柱状条、提交数），
+// 末尾附 sparkline 与可选的合计/平均/峰值摘要。
+func RenderTrend(buckets []Bucket, g Granularity, opts TrendOptions) string {
+	if len(buckets) == 0 {
+		return ""
+	}
+
+	labelW, countW, maxCount, total := 0, 1, 0, 0
+	peak := buckets[0]
+	for _, b := range buckets {
+		c := b.Activity.Commits
+		labelW = max(labelW, len(b.Label))
+		countW = max(countW, len(fmt.Sprint(c)))
+		maxCount = max(maxCount, c)
+		total += c
+		if c > peak.Activity.Commits {
+			peak = b
+		}
+	}
+
+	barW := defaultTrendBarWidth
+	if opts.Width > 0 {
+		barW = max(10, min(opts.Width-labelW-countW-2, 2*defaultTrendBarWidth))
+	}
+	barChar := "█"
+	if opts.Charset == CharsetASCII {
+		barChar = "#"
+	}
+
+	var b strings.Builder
+	for _, bucket := range buckets {
+		c := bucket.Activity.Commits
+		n := 0
+		if maxCount > 0 {
+			n = (c*barW + maxCount/2) / maxCount
+			if c > 0 {
+				n = max(1, n)
+			}
+		}
+		bar := strings.Repeat(barChar, n) + strings.Repeat(" ", barW-n)
+		fmt.Fprintf(&b, "%-*s %s %*d\n", labelW, bucket.Label, bar, countW, c)
+	}
+
+	b.WriteByte('\n')
+	b.WriteString("Trend: " + sparkline(buckets, sparkLevels[opts.Charset]) + "\n")
+
+	if opts.ShowSummary {
+		summary := fmt.Sprintf("Total: %d commits │ Average: %.1f per %s", total, float64(total)/float64(len(buckets)), g)
+		if peak.Activity.Commits > 0 {
+			summary += fmt.Sprintf(" │ Peak: %s (%d commits)", peak.Label, peak.Activity.Commits)
+		}
+		if opts.Charset == CharsetASCII {
+			summary = ToASCII(summary)
+		}
+		b.WriteString(summary + "\n")
+	}
+	return b.String()
+}
+
+// sparkline 将各时间段的提交数映射为 levels 中的字符。
+func sparkline(buckets []Bucket, levels []rune) string {
+	maxCount := 0
+	for _, b := range buckets {
+		maxCount = max(maxCount, b.Activity.Commits)
+	}
+	out := make([]rune, 0, len(buckets))
+	for _, b := range buckets {
+		i := 0
+		if maxCount > 0 {
+			i = b.Activity.Commits * (len(levels) - 1) / maxCount
+		}
+		out = append(out, levels[i])
+	}
+	return string(out)
+}
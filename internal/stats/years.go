@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseYears 解析年份列表，支持逗号分隔与闭区间（如 "2023,2024,2025" 或 "2023-2025"），
+// 结果去重并升序排列。
+func ParseYears(s string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to := part, part
+		if i := strings.Index(part, "-"); i > 0 {
+			from, to = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		first, err1 := parseYear(from)
+		last, err2 := parseYear(to)
+		if err1 != nil || err2 != nil || first > last {
+			return nil, fmt.Errorf("invalid years %q (expected YYYY[,YYYY...] or YYYY-YYYY)", s)
+		}
+		for y := first; y <= last; y++ {
+			seen[y] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("invalid years %q (expected YYYY[,YYYY...] or YYYY-YYYY)", s)
+	}
+
+	years := make([]int, 0, len(seen))
+	for y := range seen {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+	return years, nil
+}
+
+// parseYear 解析四位年份。
+func parseYear(s string) (int, error) {
+	if len(s) != 4 {
+		return 0, fmt.Errorf("invalid year %q", s)
+	}
+	return strconv.Atoi(s)
+}
+
+// YearRange 返回 year 年 1 月 1 日与 12 月 31 日（loc 时区的 00:00）。
+func YearRange(year int, loc *time.Location) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, loc), time.Date(year, time.December, 31, 0, 0, 0, 0, loc)
+}
+
+// YearsBetween 返回 [start, end] 覆盖的全部日历年。
+func YearsBetween(start, end time.Time) []int {
+	if start.IsZero() || end.IsZero() || start.After(end) {
+		return nil
+	}
+	years := make([]int, 0, end.Year()-start.Year()+1)
+	for y := start.Year(); y <= end.Year(); y++ {
+		years = append(years, y)
+	}
+	return years
+}
+
+// RenderYearsHeatmap 为每个年份渲染一个 1 月至 12 月的热力图块并上下堆叠，
+// 每块上方标注年份、下方附该年的单行摘要（ShowSummary 时），图例在末尾共用一份。
+// 档位按全部年份的数据统一计算，保证各块颜色可比；opts.Since/Until 不参与排版。
+func RenderYearsHeatmap(stats map[time.Time]int, years []int, opts HeatmapOptions) string {
+	if len(years) == 0 {
+		return ""
+	}
+	loc := timeNow().Location()
+	if !opts.Until.IsZero() {
+		loc = opts.Until.Location()
+	}
+	first, _ := YearRange(years[0], loc)
+	_, last := YearRange(years[len(years)-1], loc)
+	all, ok := newHeatmapGrid(first, last, opts.WeekStart)
+	if !ok {
+		return ""
+	}
+
+	style := newCellStyle(opts.Theme, opts.NoColor, opts.Charset)
+	scale := opts.Thresholds.scale(stats, all)
+
+	var b strings.Builder
+	for i, year := range years {
+		start, end := YearRange(year, loc)
+		g, _ := newHeatmapGrid(start, end, opts.WeekStart)
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%d\n", year)
+		writeGrid(&b, stats, g, style, scale, opts)
+
+		if opts.ShowSummary {
+			line := RenderYearSummary(CalculateSummary(statsBetween(stats, start, end)))
+			if opts.Charset == CharsetASCII {
+				line = ToASCII(line)
+			}
+			b.WriteString(line)
+		}
+	}
+
+	if opts.ShowLegend {
+		b.WriteByte('\n')
+		b.WriteString(renderLegend(style, scale))
+	}
+	return b.String()
+}
+
+// RenderYearSummary 将摘要渲染为单行，用于按年堆叠视图中每个年份块的下方。
+func RenderYearSummary(s Summary) string {
+	line := fmt.Sprintf("Summary: %d commits │ Active days: %d │ Longest streak: %d %s",
+		s.TotalCommits, s.ActiveDays, s.LongestStreak.Days, Pluralize(s.LongestStreak.Days, "day", "days"))
+	if s.PeakDay.Commits > 0 && !s.PeakDay.Date.IsZero() {
+		line += fmt.Sprintf(" │ Peak day: %s (%d commits)", formatShortDate(s.PeakDay.Date), s.PeakDay.Commits)
+	}
+	return line + "\n"
+}
+
+// statsBetween 返回 [start, end] 范围内的按天统计。
+func statsBetween(stats map[time.Time]int, start, end time.Time) map[time.Time]int {
+	out := make(map[time.Time]int)
+	for day, c := range stats {
+		if !day.Before(start) && !day.After(end) {
+			out[day] = c
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYears(t *testing.T) {
+	years, err := ParseYears("2025, 2023,2024,2023")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2023, 2024, 2025}, years)
+
+	years, err = ParseYears("2021-2023,2025")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2021, 2022, 2023, 2025}, years)
+
+	for _, bad := range []string{"", "23", "2025-2023", "20x5", ","} {
+		_, err := ParseYears(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestYearsBetween(t *testing.T) {
+	loc := time.UTC
+	assert.Equal(t, []int{2023, 2024, 2025}, YearsBetween(time.Date(2023, 5, 1, 0, 0, 0, 0, loc), time.Date(2025, 2, 1, 0, 0, 0, 0, loc)))
+	assert.Nil(t, YearsBetween(time.Time{}, time.Date(2025, 2, 1, 0, 0, 0, 0, loc)))
+}
+
+func TestRenderYearsHeatmap_StacksBlocksWithSummaries(t *testing.T) {
+	setFixedNow(t, time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC))
+	loc := time.UTC
+	stats := map[time.Time]int{
+		time.Date(2023, 3, 1, 0, 0, 0, 0, loc):  4,
+		time.Date(2023, 3, 2, 0, 0, 0, 0, loc):  2,
+		time.Date(2025, 7, 14, 0, 0, 0, 0, loc): 12,
+	}
+
+	out := RenderYearsHeatmap(stats, []int{2023, 2025}, HeatmapOptions{ShowLegend: true, ShowSummary: true, NoColor: true})
+	lines := strings.Split(out, "\n")
+
+	require.Equal(t, "2023", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "    Jan "), "each block starts in January: %q", lines[1])
+	assert.Contains(t, lines[1], "Dec")
+	assert.Equal(t, "Summary: 6 commits │ Active days: 2 │ Longest streak: 2 days │ Peak day: Mar 01 (4 commits)", lines[9])
+	assert.Equal(t, "", lines[10])
+	assert.Equal(t, "2025", lines[11])
+	assert.Equal(t, "Summary: 12 commits │ Active days: 1 │ Longest streak: 1 day │ Peak day: Jul 14 (12 commits)", lines[20])
+	assert.Equal(t, 1, strings.Count(out, "Less "), "legend is shared")
+	assert.NotContains(t, out, "2024")
+}